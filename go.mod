@@ -3,6 +3,7 @@ module github.com/openshift-kni/eco-gosystem
 go 1.20
 
 require (
+	github.com/blang/semver/v4 v4.0.0
 	github.com/golang/glog v1.1.2
 	github.com/kelseyhightower/envconfig v1.4.0
 	github.com/onsi/ginkgo/v2 v2.13.2
@@ -10,19 +11,24 @@ require (
 	github.com/openshift-kni/cluster-group-upgrades-operator v0.0.0-20231216054307-28180628cf50
 	github.com/openshift-kni/eco-goinfra v0.0.0-20240202154232-b24741524946
 	github.com/openshift-kni/k8sreporter v1.0.5
+	github.com/openshift-kni/lifecycle-agent v0.0.0-20240109211418-4489c4a1eb46
+	github.com/openshift/api v3.9.1-0.20190916204813-cdbe64fb0c91+incompatible
 	github.com/openshift/cluster-node-tuning-operator v0.0.0-20231225123609-e63d2c9626fe
 	github.com/openshift/machine-config-operator v0.0.1-0.20230807154212-886c5c3fc7a9
+	github.com/openshift/ptp-operator v0.0.0-20231220185604-29113b41981b
 	github.com/operator-framework/api v0.20.0
 	gonum.org/v1/gonum v0.14.0
 	gopkg.in/yaml.v2 v2.4.0
 	k8s.io/api v0.28.4
 	k8s.io/apimachinery v0.29.0
 	k8s.io/client-go v12.0.0+incompatible
+	k8s.io/kubectl v0.28.3
 	k8s.io/kubernetes v1.28.2
 	k8s.io/utils v0.0.0-20230726121419-3b25d923346b
 	open-cluster-management.io/config-policy-controller v0.12.0
 	open-cluster-management.io/governance-policy-propagator v0.12.0
 	open-cluster-management.io/multicloud-operators-subscription v0.11.0
+	sigs.k8s.io/controller-runtime v0.16.3
 )
 
 require (
@@ -55,7 +61,6 @@ require (
 	github.com/aws/aws-sdk-go v1.45.17 // indirect
 	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/blang/semver v3.5.1+incompatible // indirect
-	github.com/blang/semver/v4 v4.0.0 // indirect
 	github.com/bugsnag/panicwrap v1.3.4 // indirect
 	github.com/cespare/xxhash/v2 v2.2.0 // indirect
 	github.com/chai2010/gettext-go v1.0.2 // indirect
@@ -122,8 +127,6 @@ require (
 	github.com/monochromegane/go-gitignore v0.0.0-20200626010858-205db1a8cc00 // indirect
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 	github.com/nmstate/kubernetes-nmstate/api v0.0.0-20231116153922-80c6e01df02e // indirect
-	github.com/openshift-kni/lifecycle-agent v0.0.0-20240109211418-4489c4a1eb46 // indirect
-	github.com/openshift/api v3.9.1-0.20190916204813-cdbe64fb0c91+incompatible // indirect
 	github.com/openshift/assisted-service/api v0.0.0 // indirect
 	github.com/openshift/assisted-service/models v0.0.0 // indirect
 	github.com/openshift/client-go v0.0.1 // indirect
@@ -132,7 +135,6 @@ require (
 	github.com/openshift/hive/apis v0.0.0-20220222213051-def9088fdb5a // indirect
 	github.com/openshift/library-go v0.0.0-20231027143522-b8cd45d2d2c8 // indirect
 	github.com/openshift/local-storage-operator v0.0.0-20231220121151-4e580bd14c46 // indirect
-	github.com/openshift/ptp-operator v0.0.0-20231220185604-29113b41981b // indirect
 	github.com/operator-framework/operator-lifecycle-manager v0.26.0 // indirect
 	github.com/operator-framework/operator-registry v1.30.1 // indirect
 	github.com/peterbourgon/diskv v2.0.1+incompatible // indirect
@@ -181,9 +183,7 @@ require (
 	k8s.io/klog/v2 v2.110.1 // indirect
 	k8s.io/kube-aggregator v0.28.2 // indirect
 	k8s.io/kube-openapi v0.0.0-20231010175941-2dd684a91f00 // indirect
-	k8s.io/kubectl v0.28.3 // indirect
 	k8s.io/kubelet v0.27.7 // indirect
-	sigs.k8s.io/controller-runtime v0.16.3 // indirect
 	sigs.k8s.io/json v0.0.0-20221116044647-bc3834ca7abd // indirect
 	sigs.k8s.io/kube-storage-version-migrator v0.0.6-0.20230721195810-5c8923c5ff96 // indirect
 	sigs.k8s.io/kustomize/api v0.13.5-0.20230601165947-6ce0bf390ce3 // indirect
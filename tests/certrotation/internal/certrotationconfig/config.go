@@ -0,0 +1,83 @@
+package certrotationconfig
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/config"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// PathToDefaultCertRotationParamsFile path to config file with default certificate rotation parameters.
+	PathToDefaultCertRotationParamsFile = "./default.yaml"
+)
+
+// CertRotationConfig type keeps certificate rotation configuration.
+type CertRotationConfig struct {
+	*config.GeneralConfig
+	RecoveryTimeout string `yaml:"certrotation_recovery_timeout" envconfig:"ECO_CERTROTATION_RECOVERY_TIMEOUT"`
+	MinValidity     string `yaml:"certrotation_min_validity" envconfig:"ECO_CERTROTATION_MIN_VALIDITY"`
+	ApproveCSRs     bool   `yaml:"certrotation_approve_csrs" envconfig:"ECO_CERTROTATION_APPROVE_CSRS"`
+}
+
+// NewCertRotationConfig returns instance of CertRotationConfig config type.
+func NewCertRotationConfig() *CertRotationConfig {
+	log.Print("Creating new CertRotationConfig struct")
+
+	var certRotationConf CertRotationConfig
+	certRotationConf.GeneralConfig = config.NewConfig()
+
+	_, filename, _, _ := runtime.Caller(0)
+	baseDir := filepath.Dir(filename)
+	confFile := filepath.Join(baseDir, PathToDefaultCertRotationParamsFile)
+	err := readFile(&certRotationConf, confFile)
+
+	if err != nil {
+		log.Printf("Error to read config file %s", confFile)
+
+		return nil
+	}
+
+	err = readEnv(&certRotationConf)
+
+	if err != nil {
+		log.Print("Error to read environment variables")
+
+		return nil
+	}
+
+	return &certRotationConf
+}
+
+func readFile(certRotationConfig *CertRotationConfig, cfgFile string) error {
+	openedCfgFile, err := os.Open(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = openedCfgFile.Close()
+	}()
+
+	decoder := yaml.NewDecoder(openedCfgFile)
+	err = decoder.Decode(&certRotationConfig)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func readEnv(certRotationConfig *CertRotationConfig) error {
+	err := envconfig.Process("", certRotationConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package certrotationhelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/certs"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/nodecerts"
+)
+
+const metricMinValidity = "certmetrics_min_validity_hours"
+
+// Inventory is the list of the certificates of the cluster and of its nodes at a point in time.
+type Inventory struct {
+	Time    time.Time                      `json:"time"`
+	Cluster []certs.Certificate            `json:"cluster"`
+	Nodes   map[string][]certs.Certificate `json:"nodes"`
+}
+
+// CollectInventory returns the certificates managed by the cluster and the certificates of the given nodes.
+func CollectInventory(apiClient *clients.Settings, nodeNames []string) (*Inventory, error) {
+	inventory := &Inventory{Time: time.Now(), Nodes: map[string][]certs.Certificate{}}
+
+	var err error
+
+	inventory.Cluster, err = certs.ListManagedCertificates(apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nodeName := range nodeNames {
+		inventory.Nodes[nodeName], err = nodecerts.ListNodeCertificates(nodeName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return inventory, nil
+}
+
+// CheckExpiry returns the certificates of the inventory which are expired or expire within minValidity.
+func (inventory *Inventory) CheckExpiry(minValidity time.Duration) []string {
+	issues := certs.CheckExpiry(inventory.Cluster, minValidity)
+
+	for nodeName, nodeCertificates := range inventory.Nodes {
+		for _, issue := range certs.CheckExpiry(nodeCertificates, minValidity) {
+			issues = append(issues, fmt.Sprintf("node %s %s", nodeName, issue))
+		}
+	}
+
+	return issues
+}
+
+// Metrics returns the min remaining validity metric of the inventory named after the given tag.
+func (inventory *Inventory) Metrics(tag string) map[string]string {
+	var minValidity time.Duration
+
+	first := true
+
+	for _, certificates := range append([][]certs.Certificate{inventory.Cluster}, inventory.nodeCertificates()...) {
+		for _, certificate := range certificates {
+			validity := certificate.NotAfter.Sub(inventory.Time)
+			if first || validity < minValidity {
+				minValidity = validity
+				first = false
+			}
+		}
+	}
+
+	return map[string]string{
+		fmt.Sprintf("%s_%s", metricMinValidity, tag): strconv.FormatFloat(minValidity.Hours(), 'f', 0, 64),
+	}
+}
+
+// String returns the node certificates of the inventory, one per line.
+func (inventory *Inventory) String() string {
+	var builder strings.Builder
+
+	for nodeName, nodeCertificates := range inventory.Nodes {
+		for _, certificate := range nodeCertificates {
+			fmt.Fprintf(&builder, "node %s %s %q expires %s\n", nodeName, certificate.Source, certificate.Subject,
+				certificate.NotAfter.Format(time.RFC3339))
+		}
+	}
+
+	fmt.Fprintf(&builder, "%d certificates managed by the cluster, first expiring", len(inventory.Cluster))
+
+	if len(inventory.Cluster) > 0 {
+		fmt.Fprintf(&builder, " %s at %s", inventory.Cluster[0].Source,
+			inventory.Cluster[0].NotAfter.Format(time.RFC3339))
+	}
+
+	builder.WriteString("\n")
+
+	return builder.String()
+}
+
+// WriteInventory writes the inventory as <name>_certificates.json to dir.
+func WriteInventory(dir, name string, inventory *Inventory) error {
+	glog.V(90).Infof("Writing certificate inventory %s to %s", name, dir)
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, name+"_certificates.json"), content, 0644)
+}
+
+func (inventory *Inventory) nodeCertificates() [][]certs.Certificate {
+	certificates := make([][]certs.Certificate, 0, len(inventory.Nodes))
+
+	for _, nodeCertificates := range inventory.Nodes {
+		certificates = append(certificates, nodeCertificates)
+	}
+
+	return certificates
+}
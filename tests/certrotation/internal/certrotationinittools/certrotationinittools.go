@@ -0,0 +1,21 @@
+package certrotationinittools
+
+import (
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-gosystem/tests/certrotation/internal/certrotationconfig"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/inittools"
+)
+
+var (
+	// APIClient provides API access to cluster.
+	APIClient *clients.Settings
+	// CertRotationTestConfig provides access to certificate rotation system tests configuration parameters.
+	CertRotationTestConfig *certrotationconfig.CertRotationConfig
+)
+
+// init loads all variables automatically when this package is imported. Once package is imported a user has full
+// access to all vars within init function. It is recommended to import this package using dot import.
+func init() {
+	CertRotationTestConfig = certrotationconfig.NewCertRotationConfig()
+	APIClient = inittools.APIClient
+}
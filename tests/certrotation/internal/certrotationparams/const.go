@@ -0,0 +1,18 @@
+package certrotationparams
+
+import "time"
+
+const (
+	// Label represents certificate rotation system tests label that can be used for test cases selection.
+	Label = "certrotation"
+	// LabelCertInventory represents tests labels related to the certificate inventory.
+	LabelCertInventory = "cert-inventory"
+	// LabelKubeletRotation represents tests labels related to the forced kubelet certificate rotation.
+	LabelKubeletRotation = "kubelet-cert-rotation"
+	// ClientSignerName is the signer of the kubelet client certificates.
+	ClientSignerName = "kubernetes.io/kube-apiserver-client-kubelet"
+	// ServingSignerName is the signer of the kubelet serving certificates.
+	ServingSignerName = "kubernetes.io/kubelet-serving"
+	// CSRTimeout is the time given to the CSRs to be handled after a rotation.
+	CSRTimeout = 10 * time.Minute
+)
@@ -0,0 +1,142 @@
+package certrotation_system_test
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-gosystem/tests/certrotation/internal/certrotationhelper"
+	. "github.com/openshift-kni/eco-gosystem/tests/certrotation/internal/certrotationinittools"
+	"github.com/openshift-kni/eco-gosystem/tests/certrotation/internal/certrotationparams"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/nodecerts"
+)
+
+var _ = Describe(
+	"CertificateRotation",
+	Ordered,
+	ContinueOnFailure,
+	Label(certrotationparams.Label), func() {
+		var (
+			nodeNames       []string
+			recoveryTimeout time.Duration
+			minValidity     time.Duration
+		)
+
+		BeforeAll(func() {
+			var err error
+
+			recoveryTimeout, err = time.ParseDuration(CertRotationTestConfig.RecoveryTimeout)
+			Expect(err).ToNot(HaveOccurred(), "invalid certificate rotation recovery timeout")
+
+			minValidity, err = time.ParseDuration(CertRotationTestConfig.MinValidity)
+			Expect(err).ToNot(HaveOccurred(), "invalid certificate min validity")
+
+			By("Retrieve nodes list")
+			nodeList, err := nodes.List(APIClient)
+			Expect(err).ToNot(HaveOccurred(), "Error listing nodes.")
+
+			for _, node := range nodeList {
+				nodeNames = append(nodeNames, node.Definition.Name)
+			}
+		})
+
+		It("Report the certificate inventory before rotation", Label(certrotationparams.LabelCertInventory), func() {
+			reportInventory(nodeNames, minValidity, "beforerotation")
+		})
+
+		It("Verify node recovers from a forced kubelet client certificate rotation",
+			Label(certrotationparams.LabelKubeletRotation), func() {
+				for _, nodeName := range nodeNames {
+					rotateAndValidate(nodeName, nodecerts.KubeletClient, certrotationparams.ClientSignerName,
+						recoveryTimeout, "kubeletclient")
+				}
+			})
+
+		It("Verify node recovers from a forced kubelet serving certificate rotation",
+			Label(certrotationparams.LabelKubeletRotation), func() {
+				for _, nodeName := range nodeNames {
+					rotateAndValidate(nodeName, nodecerts.KubeletServer, certrotationparams.ServingSignerName,
+						recoveryTimeout, "kubeletserver")
+				}
+			})
+
+		It("Report the certificate inventory after rotation", Label(certrotationparams.LabelCertInventory), func() {
+			reportInventory(nodeNames, minValidity, "afterrotation")
+		})
+	})
+
+// reportInventory collects the certificate inventory, writes it to the reports directory, persists its metrics to
+// the ginkgo report and checks that no certificate expires within minValidity.
+func reportInventory(nodeNames []string, minValidity time.Duration, tag string) {
+	By("Collect the certificate inventory")
+	inventory, err := certrotationhelper.CollectInventory(APIClient, nodeNames)
+	Expect(err).ToNot(HaveOccurred(), "error collecting the certificate inventory")
+
+	fmt.Print(inventory.String())
+
+	err = certrotationhelper.WriteInventory(CertRotationTestConfig.ReportsDirAbsPath, tag, inventory)
+	Expect(err).ToNot(HaveOccurred(), "error writing the certificate inventory")
+
+	// Persist certificate metrics to ginkgo report for further processing in pipeline.
+	for metricName, metricValue := range inventory.Metrics(tag) {
+		_, err := fmt.Fprintf(GinkgoWriter, "%s: %s\n", metricName, metricValue)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	Expect(inventory.CheckExpiry(minValidity)).To(BeEmpty(), "certificates expire within %s", minValidity)
+}
+
+// rotateAndValidate forces the rotation of a kubelet certificate of the node, waits for the new certificate and
+// for the node to be ready, persists the rotation time metric to the ginkgo report and verifies that the CSRs of
+// the signer were approved.
+func rotateAndValidate(nodeName, path, signerName string, recoveryTimeout time.Duration, tag string) {
+	previous, err := nodecerts.GetNodeCertificate(nodeName, path)
+	Expect(err).ToNot(HaveOccurred(), "error reading certificate %s of node %s", path, nodeName)
+
+	start := time.Now()
+
+	By(fmt.Sprintf("Force the rotation of %s on node %s", path, nodeName))
+	err = nodecerts.ForceKubeletRotation(nodeName, path)
+	Expect(err).ToNot(HaveOccurred(), "error forcing the rotation of %s", path)
+
+	By("Wait for the certificate to be renewed and the node to be ready")
+	renewed, err := nodecerts.WaitForRenewal(APIClient, nodeName, path, previous.Serial,
+		CertRotationTestConfig.ApproveCSRs, recoveryTimeout)
+	Expect(err).ToNot(HaveOccurred(), "node %s did not recover from the rotation of %s", nodeName, path)
+	// The expiry of the signer CA caps the expiry of the renewed certificate, its serial and issuance identify it.
+	Expect(renewed.Serial).ToNot(Equal(previous.Serial), "certificate %s was not renewed", path)
+	Expect(renewed.NotBefore).To(BeTemporally(">", previous.NotBefore), "renewed certificate issued earlier")
+
+	// Persist rotation time metric to ginkgo report for further processing in pipeline.
+	_, err = fmt.Fprintf(GinkgoWriter, "certmetrics_rotation_seconds_%s_%s: %s\n", nodeName, tag,
+		strconv.FormatFloat(time.Since(start).Seconds(), 'f', 0, 64))
+	Expect(err).ToNot(HaveOccurred())
+
+	By("Wait for the pending CSRs to be handled")
+	_, err = cluster.WaitForNoPendingCSRs(APIClient, CertRotationTestConfig.ApproveCSRs, certrotationparams.CSRTimeout,
+		nodeName)
+	Expect(err).ToNot(HaveOccurred(), "CSRs still pending after the rotation")
+
+	By("Verify the CSRs of the rotation were approved")
+	csrs, err := cluster.ListCSRs(APIClient, start)
+	Expect(err).ToNot(HaveOccurred(), "error listing CSRs")
+
+	var approved int
+
+	for _, csr := range csrs {
+		if csr.Signer != signerName {
+			continue
+		}
+
+		fmt.Fprintf(GinkgoWriter, "CSR %s of %s %s %s\n", csr.Name, csr.Username, csr.Status, csr.Reason)
+		Expect(csr.Status).To(Equal("Approved"), "CSR %s of %s was not approved", csr.Name, csr.Username)
+
+		approved++
+	}
+
+	Expect(approved).ToNot(BeZero(), "no %s CSR approved after the rotation", signerName)
+}
@@ -2,74 +2,31 @@ package ibuclusterinfo
 
 import (
 	"github.com/golang/glog"
-	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
-	"github.com/openshift-kni/eco-goinfra/pkg/olm"
 	. "github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeinittools"
-	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeparams"
-	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/snapshot"
 )
 
-// itemInArray is a dedicated func to check if an item is in an array.
-func itemInArray(item string, array []string) bool {
-	for _, v := range array {
-		if v == item {
-			return true
-		}
-	}
-
-	return false
-}
-
-// SaveClusterInfo is a dedicated func to save cluster info.
-func SaveClusterInfo(upgradeVar *imagebasedupgradeparams.ClusterStruct) error {
-	clusterVersion, err := cluster.GetClusterVersion(TargetSNOAPIClient)
-
-	if err != nil {
-		glog.V(100).Infof("Could not retrieve cluster version")
-
-		return err
-	}
-
-	clusterID, err := cluster.GetClusterID(TargetSNOAPIClient)
+// TakeSnapshot is a dedicated func to take the snapshot of the target SNO and save it under the run ID.
+func TakeSnapshot(name string) (*snapshot.Snapshot, error) {
+	clusterSnapshot, err := snapshot.Take(TargetSNOAPIClient)
 
 	if err != nil {
-		glog.V(100).Infof("Could not retrieve cluster id")
-
-		return err
+		return nil, err
 	}
 
-	csvList, err := olm.ListClusterServiceVersionInAllNamespaces(TargetSNOAPIClient)
+	runID := snapshot.RunID(IBUTestConfig.RunID)
+	path, err := clusterSnapshot.Save(IBUTestConfig.ReportsDirAbsPath, runID, name)
 
 	if err != nil {
-		glog.V(100).Infof("Could not retrieve csv list")
-
-		return err
+		return nil, err
 	}
 
-	var installedCSV []string
-
-	for _, csv := range csvList {
-		if itemInArray(csv.Object.Name, installedCSV) {
-			// Do nothing
-		} else {
-			installedCSV = append(installedCSV, csv.Object.Name)
-		}
-	}
-
-	node, err := nodes.List(TargetSNOAPIClient)
-
-	if err != nil {
-		glog.V(100).Infof("Could not retrieve node list")
-
-		return err
-	}
+	glog.V(100).Infof("Saved snapshot %s of run %s to %s", name, runID, path)
 
-	*upgradeVar = imagebasedupgradeparams.ClusterStruct{
-		Version:   clusterVersion,
-		ID:        clusterID,
-		Operators: installedCSV,
-		NodeName:  node[0].Object.Name,
-	}
+	return clusterSnapshot, nil
+}
 
-	return nil
+// LoadSnapshot is a dedicated func to load a snapshot saved under the run ID.
+func LoadSnapshot(name string) (*snapshot.Snapshot, error) {
+	return snapshot.Load(IBUTestConfig.ReportsDirAbsPath, snapshot.RunID(IBUTestConfig.RunID), name)
 }
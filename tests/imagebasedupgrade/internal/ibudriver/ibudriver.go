@@ -0,0 +1,345 @@
+package ibudriver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/lca"
+	lcav1alpha1 "github.com/openshift-kni/lifecycle-agent/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// MetricStageTime is the ginkgo report metric of the duration of a stage transition.
+	MetricStageTime = "ibumetrics_stage_seconds"
+	// failedReason is the reason set by the lifecycle agent on the conditions of a failed stage.
+	failedReason = "Failed"
+	// updateTimeout bounds the retries of the stage update, e.g. while the API is restarting.
+	updateTimeout = 5 * time.Minute
+	pollInterval  = 10 * time.Second
+)
+
+// StageTiming is the duration of a stage transition of the ImageBasedUpgrade.
+type StageTiming struct {
+	Stage    string        `json:"stage"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Driver moves the ImageBasedUpgrade through its stages and records the duration of every transition.
+type Driver struct {
+	apiClient *clients.Settings
+	name      string
+	Timings   []StageTiming
+}
+
+// NewDriver returns a driver of the ImageBasedUpgrade with the given name.
+func NewDriver(apiClient *clients.Settings, name string) *Driver {
+	return &Driver{apiClient: apiClient, name: name}
+}
+
+// Advance sets the stage of the ImageBasedUpgrade and waits until the lifecycle agent reports it completed. The
+// duration of the transition is recorded, including when it fails. API errors are tolerated while waiting since the
+// node reboots into the new stateroot during the Upgrade and Rollback stages. When the stage fails the reason
+// reported in the ImageBasedUpgrade status is returned.
+func (driver *Driver) Advance(stage lcav1alpha1.ImageBasedUpgradeStage, timeout time.Duration) error {
+	glog.V(90).Infof("Moving imagebasedupgrade %s to stage %s", driver.name, stage)
+
+	start := time.Now()
+
+	err := driver.setStage(stage)
+	if err == nil {
+		err = driver.waitForStage(stage, timeout)
+	}
+
+	driver.Timings = append(driver.Timings, StageTiming{Stage: string(stage), Duration: time.Since(start)})
+
+	if err != nil {
+		return fmt.Errorf("imagebasedupgrade %s failed to reach stage %s: %w", driver.name, stage, err)
+	}
+
+	return nil
+}
+
+// Metrics returns the duration of every stage transition named after its sequence number, starting from 1, and
+// the given tag, e.g. ibumetrics_stage_seconds_3_idle_rollback, so that a stage entered again does not overwrite
+// the duration of the previous transition.
+func (driver *Driver) Metrics(tag string) map[string]string {
+	metrics := make(map[string]string)
+
+	for index, timing := range driver.Timings {
+		metrics[fmt.Sprintf("%s_%d_%s_%s", MetricStageTime, index+1, strings.ToLower(timing.Stage), tag)] =
+			strconv.FormatFloat(timing.Duration.Seconds(), 'f', 0, 64)
+	}
+
+	return metrics
+}
+
+// SetSeedImage sets the seed image and its OCP version, to be set while the ImageBasedUpgrade is Idle.
+func (driver *Driver) SetSeedImage(image, version string) error {
+	return driver.update(func(ibu *lca.ImageBasedUpgradeBuilder) bool {
+		seedImageRef := ibu.Definition.Spec.SeedImageRef
+		if seedImageRef.Image == image && seedImageRef.Version == version {
+			return false
+		}
+
+		ibu.WithSeedImage(image).WithSeedImageVersion(version)
+
+		return true
+	})
+}
+
+// SetOADPContent adds the configmap holding OADP backups and restores to the oadpContent of the ImageBasedUpgrade,
+// to be set while the ImageBasedUpgrade is Idle.
+func (driver *Driver) SetOADPContent(name, namespace string) error {
+	return driver.update(func(ibu *lca.ImageBasedUpgradeBuilder) bool {
+		for _, content := range ibu.Definition.Spec.OADPContent {
+			if content.Name == name && content.Namespace == namespace {
+				return false
+			}
+		}
+
+		ibu.WithOadpContent(name, namespace)
+
+		return true
+	})
+}
+
+// RemoveOADPContent removes the configmap from the oadpContent of the ImageBasedUpgrade, to be removed while the
+// ImageBasedUpgrade is Idle.
+func (driver *Driver) RemoveOADPContent(name, namespace string) error {
+	return driver.update(func(ibu *lca.ImageBasedUpgradeBuilder) bool {
+		var kept []lcav1alpha1.ConfigMapRef
+
+		for _, content := range ibu.Definition.Spec.OADPContent {
+			if content.Name != name || content.Namespace != namespace {
+				kept = append(kept, content)
+			}
+		}
+
+		if len(kept) == len(ibu.Definition.Spec.OADPContent) {
+			return false
+		}
+
+		ibu.Definition.Spec.OADPContent = kept
+
+		return true
+	})
+}
+
+// SetAnnotation sets an annotation of the ImageBasedUpgrade, e.g. to configure the auto-rollback, or removes it
+// when the value is empty.
+func (driver *Driver) SetAnnotation(key, value string) error {
+	return driver.update(func(ibu *lca.ImageBasedUpgradeBuilder) bool {
+		current, found := ibu.Definition.Annotations[key]
+		if current == value && found == (value != "") {
+			return false
+		}
+
+		if value == "" {
+			delete(ibu.Definition.Annotations, key)
+
+			return true
+		}
+
+		if ibu.Definition.Annotations == nil {
+			ibu.Definition.Annotations = make(map[string]string)
+		}
+
+		ibu.Definition.Annotations[key] = value
+
+		return true
+	})
+}
+
+// Get returns the ImageBasedUpgrade read from the cluster.
+func (driver *Driver) Get() (*lcav1alpha1.ImageBasedUpgrade, error) {
+	ibu, err := driver.pull()
+	if err != nil {
+		return nil, err
+	}
+
+	return ibu.Object, nil
+}
+
+// Conditions returns the conditions of the current generation of the ImageBasedUpgrade.
+func (driver *Driver) Conditions() ([]metav1.Condition, error) {
+	ibu, err := driver.pull()
+	if err != nil {
+		return nil, err
+	}
+
+	return currentConditions(ibu.Object), nil
+}
+
+// setStage updates the stage of the ImageBasedUpgrade.
+func (driver *Driver) setStage(stage lcav1alpha1.ImageBasedUpgradeStage) error {
+	err := driver.update(func(ibu *lca.ImageBasedUpgradeBuilder) bool {
+		if ibu.Definition.Spec.Stage == stage {
+			return false
+		}
+
+		ibu.WithStage(string(stage))
+
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set stage %s: %w", stage, err)
+	}
+
+	return nil
+}
+
+// update applies the mutation to the ImageBasedUpgrade read from the cluster, retrying on conflicts and API errors.
+// The mutation returns false when the ImageBasedUpgrade is already up to date.
+func (driver *Driver) update(mutate func(ibu *lca.ImageBasedUpgradeBuilder) bool) error {
+	var lastErr error
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, updateTimeout, true, func(ctx context.Context) (bool, error) {
+			ibu, err := driver.pull()
+			if err != nil {
+				lastErr = err
+				glog.V(90).Infof("Failed to pull imagebasedupgrade %s: %s", driver.name, err)
+
+				return false, nil
+			}
+
+			if !mutate(ibu) {
+				return true, nil
+			}
+
+			_, err = ibu.Update()
+			if err != nil {
+				lastErr = err
+				glog.V(90).Infof("Failed to update imagebasedupgrade %s: %s", driver.name, err)
+
+				return false, nil
+			}
+
+			return true, nil
+		})
+	if err != nil {
+		return lastErr
+	}
+
+	return nil
+}
+
+// waitForStage waits until the conditions of the current generation report the stage completed or failed.
+func (driver *Driver) waitForStage(stage lcav1alpha1.ImageBasedUpgradeStage, timeout time.Duration) error {
+	var (
+		stageErr error
+		last     string
+	)
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			ibu, err := driver.pull()
+			if err != nil {
+				glog.V(90).Infof("Failed to pull imagebasedupgrade %s: %s", driver.name, err)
+
+				return false, nil
+			}
+
+			if ibu.Object.Spec.Stage != stage {
+				return false, nil
+			}
+
+			conditions := currentConditions(ibu.Object)
+			last = describeConditions(conditions)
+
+			if failure := stageFailure(stage, conditions); failure != "" {
+				stageErr = errors.New(failure)
+
+				return true, nil
+			}
+
+			return stageCompleted(stage, conditions), nil
+		})
+	if err != nil {
+		return fmt.Errorf("stage %s not completed within %s, conditions: %s: %w", stage, timeout, last, err)
+	}
+
+	return stageErr
+}
+
+// pull returns the ImageBasedUpgrade read from the cluster. PullImageBasedUpgrade does not report the API errors
+// other than not found, which leave the builder without object.
+func (driver *Driver) pull() (*lca.ImageBasedUpgradeBuilder, error) {
+	ibu, err := lca.PullImageBasedUpgrade(driver.apiClient, driver.name)
+	if err != nil {
+		return nil, err
+	}
+
+	if ibu.Object == nil {
+		return nil, fmt.Errorf("imagebasedupgrade %s could not be read", driver.name)
+	}
+
+	return ibu, nil
+}
+
+// currentConditions returns the conditions observed for the current generation of the ImageBasedUpgrade, the
+// others being left from the previous stage.
+func currentConditions(ibu *lcav1alpha1.ImageBasedUpgrade) []metav1.Condition {
+	var conditions []metav1.Condition
+
+	for _, condition := range ibu.Status.Conditions {
+		if condition.ObservedGeneration == ibu.Generation {
+			conditions = append(conditions, condition)
+		}
+	}
+
+	return conditions
+}
+
+// stageCompleted tells whether the conditions report the stage completed, Idle being reported by its own
+// condition and the other stages by their Completed condition.
+func stageCompleted(stage lcav1alpha1.ImageBasedUpgradeStage, conditions []metav1.Condition) bool {
+	conditionType := string(stage) + "Completed"
+	if stage == lcav1alpha1.Stages.Idle {
+		conditionType = string(lcav1alpha1.Stages.Idle)
+	}
+
+	for _, condition := range conditions {
+		if condition.Type == conditionType && condition.Status == metav1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stageFailure returns the reason of the failure of the stage, or an empty string when the conditions report no
+// failure.
+func stageFailure(stage lcav1alpha1.ImageBasedUpgradeStage, conditions []metav1.Condition) string {
+	for _, condition := range conditions {
+		if !strings.HasPrefix(condition.Type, string(stage)) || condition.Status == metav1.ConditionTrue {
+			continue
+		}
+
+		if strings.HasSuffix(condition.Reason, failedReason) {
+			return fmt.Sprintf("%s %s: %s", condition.Type, condition.Reason, condition.Message)
+		}
+	}
+
+	return ""
+}
+
+// describeConditions summarizes the conditions for the error reported on timeout.
+func describeConditions(conditions []metav1.Condition) string {
+	var descriptions []string
+
+	for _, condition := range conditions {
+		descriptions = append(descriptions, fmt.Sprintf("%s=%s (%s: %s)", condition.Type, condition.Status,
+			condition.Reason, condition.Message))
+	}
+
+	return "[" + strings.Join(descriptions, ", ") + "]"
+}
@@ -0,0 +1,382 @@
+package ibureadiness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/lca"
+	"github.com/openshift-kni/eco-goinfra/pkg/olm"
+	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/ibuseedgen"
+	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeparams"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cmd"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/version"
+	lcav1alpha1 "github.com/openshift-kni/lifecycle-agent/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	lcaCSVPattern  = "lifecycle-agent"
+	oadpCSVPattern = "oadp-operator"
+	// globalPullSecret is the pull secret used by the nodes, in the openshift-config namespace.
+	globalPullSecret   = "pull-secret"
+	globalPullSecretNS = "openshift-config"
+	// varPath is the file system holding the stateroots and the container images.
+	varPath = "/var"
+	gib     = 1 << 30
+)
+
+// Severity tells whether a finding prevents the upgrade.
+type Severity string
+
+const (
+	// Blocking findings make the upgrade fail, e.g. a non-Idle ImageBasedUpgrade.
+	Blocking Severity = "blocking"
+	// Warning findings may make the upgrade fail or take longer, e.g. a registry without credentials.
+	Warning Severity = "warning"
+)
+
+// Finding is a precondition of the upgrade not met by the target cluster.
+type Finding struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// Options are the parameters of the readiness checks.
+type Options struct {
+	SeedImage string
+	// SeedImageVersion is the version set in the ImageBasedUpgrade, compared to the version of the seed image.
+	SeedImageVersion string
+	// AuthFile is the registry auth file used to inspect the seed image.
+	AuthFile string
+	// MinLCAVersion is the minimal version of the lifecycle agent CSV.
+	MinLCAVersion string
+	// MinVarFree and RecommendedVarFree are the free space of /var, in bytes, below which a blocking and a warning
+	// finding are reported.
+	MinVarFree         int64
+	RecommendedVarFree int64
+	// RequireOADP reports a missing OADP operator as blocking, as a warning otherwise.
+	RequireOADP bool
+}
+
+// Report is the list of findings of the readiness checks.
+type Report struct {
+	Findings []Finding `json:"findings"`
+}
+
+// Run runs every readiness check against the target cluster. A check which cannot run is reported as a blocking
+// finding, so the report is always complete. The seed image set in the ImageBasedUpgrade is checked when none is
+// given.
+func Run(apiClient *clients.Settings, options Options) *Report {
+	report := &Report{}
+
+	if options.SeedImage == "" {
+		ibu, err := lca.PullImageBasedUpgrade(apiClient, imagebasedupgradeparams.ImagebasedupgradeCrName)
+		if err == nil && ibu.Object != nil {
+			options.SeedImage = ibu.Object.Spec.SeedImageRef.Image
+			options.SeedImageVersion = ibu.Object.Spec.SeedImageRef.Version
+		}
+	}
+
+	checks := []struct {
+		name  string
+		check func(*clients.Settings, Options, *Report)
+	}{
+		{"disk", checkDiskSpace},
+		{"lca", checkLCAVersion},
+		{"seed", checkSeedImage},
+		{"pullsecret", checkPullSecret},
+		{"oadp", checkOADP},
+		{"stage", checkStage},
+	}
+
+	for _, check := range checks {
+		glog.V(90).Infof("Running readiness check %s", check.name)
+		check.check(apiClient, options, report)
+	}
+
+	return report
+}
+
+// Blocking returns the blocking findings, one message per finding.
+func (report *Report) Blocking() []string {
+	return report.messages(Blocking)
+}
+
+// Warnings returns the warning findings, one message per finding.
+func (report *Report) Warnings() []string {
+	return report.messages(Warning)
+}
+
+// String returns every finding, one per line.
+func (report *Report) String() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "Readiness: %d blocking, %d warning findings\n", len(report.Blocking()),
+		len(report.Warnings()))
+
+	for _, finding := range report.Findings {
+		fmt.Fprintf(&builder, "%s %s: %s\n", strings.ToUpper(string(finding.Severity)), finding.Check, finding.Message)
+	}
+
+	return builder.String()
+}
+
+func (report *Report) add(check string, severity Severity, format string, args ...interface{}) {
+	report.Findings = append(report.Findings, Finding{Check: check, Severity: severity,
+		Message: fmt.Sprintf(format, args...)})
+}
+
+func (report *Report) messages(severity Severity) []string {
+	var messages []string
+
+	for _, finding := range report.Findings {
+		if finding.Severity == severity {
+			messages = append(messages, fmt.Sprintf("%s: %s", finding.Check, finding.Message))
+		}
+	}
+
+	return messages
+}
+
+// checkDiskSpace checks the free space of /var on every node through the node executor, the seed image being
+// pulled and unpacked into a new stateroot during Prep.
+func checkDiskSpace(apiClient *clients.Settings, options Options, report *Report) {
+	nodeList, err := apiClient.CoreV1Interface.Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		report.add("disk", Blocking, "failed to list nodes: %s", err)
+
+		return
+	}
+
+	for _, node := range nodeList.Items {
+		output, err := cmd.ExecCmdWithClient(apiClient,
+			[]string{"chroot", "/rootfs", "df", "--output=avail", "-B1", varPath}, node.Name)
+		if err != nil {
+			report.add("disk", Blocking, "failed to read the free space of %s on node %s: %s", varPath, node.Name, err)
+
+			continue
+		}
+
+		fields := strings.Fields(output)
+		if len(fields) == 0 {
+			report.add("disk", Blocking, "empty df output for %s on node %s", varPath, node.Name)
+
+			continue
+		}
+
+		free, err := strconv.ParseInt(fields[len(fields)-1], 10, 64)
+		if err != nil {
+			report.add("disk", Blocking, "invalid df output %q for %s on node %s", output, varPath, node.Name)
+
+			continue
+		}
+
+		switch {
+		case free < options.MinVarFree:
+			report.add("disk", Blocking, "node %s has %dGiB free on %s, %dGiB required", node.Name, free/gib,
+				varPath, options.MinVarFree/gib)
+		case free < options.RecommendedVarFree:
+			report.add("disk", Warning, "node %s has %dGiB free on %s, %dGiB recommended", node.Name, free/gib,
+				varPath, options.RecommendedVarFree/gib)
+		}
+	}
+}
+
+// checkLCAVersion checks that the lifecycle agent is installed, succeeded and at least of the minimal version.
+func checkLCAVersion(apiClient *clients.Settings, options Options, report *Report) {
+	csvs, err := olm.ListClusterServiceVersionWithNamePattern(apiClient, lcaCSVPattern,
+		imagebasedupgradeparams.ImagebasedupgradeCrNamespace)
+	if err != nil || len(csvs) == 0 {
+		report.add("lca", Blocking, "lifecycle agent CSV not found in namespace %s: %v",
+			imagebasedupgradeparams.ImagebasedupgradeCrNamespace, err)
+
+		return
+	}
+
+	csv := csvs[0].Object
+
+	if csv.Status.Phase != "Succeeded" {
+		report.add("lca", Blocking, "CSV %s is %s: %s", csv.Name, csv.Status.Phase, csv.Status.Message)
+	}
+
+	if options.MinLCAVersion == "" {
+		return
+	}
+
+	constraint, err := version.ParseConstraint(">=" + options.MinLCAVersion)
+	if err != nil {
+		report.add("lca", Blocking, "invalid minimal lifecycle agent version %s: %s", options.MinLCAVersion, err)
+
+		return
+	}
+
+	if !constraint.Check(csv.Spec.Version.String()) {
+		report.add("lca", Blocking, "CSV %s version %s is older than %s", csv.Name, csv.Spec.Version.String(),
+			options.MinLCAVersion)
+	}
+}
+
+// checkSeedImage checks that the OCP version labelled on the seed image matches the version set in the
+// ImageBasedUpgrade and is newer than the version of the target cluster.
+func checkSeedImage(apiClient *clients.Settings, options Options, report *Report) {
+	if options.SeedImage == "" {
+		report.add("seed", Blocking, "no seed image configured")
+
+		return
+	}
+
+	seedVersion, err := ibuseedgen.GetSeedImageVersion(options.SeedImage, options.AuthFile)
+	if err != nil {
+		report.add("seed", Blocking, "%s", err)
+
+		return
+	}
+
+	if options.SeedImageVersion != "" && options.SeedImageVersion != seedVersion {
+		report.add("seed", Blocking, "seed image %s is of version %s, not %s", options.SeedImage, seedVersion,
+			options.SeedImageVersion)
+	}
+
+	targetVersion, err := cluster.GetClusterVersion(apiClient)
+	if err != nil {
+		report.add("seed", Blocking, "failed to get the target cluster version: %s", err)
+
+		return
+	}
+
+	seed, seedErr := version.Parse(seedVersion)
+	target, targetErr := version.Parse(targetVersion)
+
+	if seedErr != nil || targetErr != nil {
+		report.add("seed", Warning, "cannot compare seed version %s with target version %s", seedVersion,
+			targetVersion)
+
+		return
+	}
+
+	switch {
+	case seed.LT(target):
+		report.add("seed", Blocking, "seed version %s is older than target version %s", seedVersion, targetVersion)
+	case seed.EQ(target):
+		report.add("seed", Warning, "seed version %s is the target version", seedVersion)
+	}
+}
+
+// checkPullSecret checks that the pull secret referenced by the ImageBasedUpgrade exists and that the target
+// cluster holds credentials for the registry of the seed image.
+func checkPullSecret(apiClient *clients.Settings, options Options, report *Report) {
+	if options.SeedImage == "" {
+		return
+	}
+
+	registry, _, _ := strings.Cut(options.SeedImage, "/")
+
+	ibu, err := lca.PullImageBasedUpgrade(apiClient, imagebasedupgradeparams.ImagebasedupgradeCrName)
+	if err == nil && ibu.Object != nil && ibu.Object.Spec.SeedImageRef.PullSecretRef != nil {
+		name := ibu.Object.Spec.SeedImageRef.PullSecretRef.Name
+
+		secret, err := apiClient.Secrets(imagebasedupgradeparams.ImagebasedupgradeCrNamespace).Get(
+			context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			report.add("pullsecret", Blocking, "seed image pull secret %s: %s", name, err)
+
+			return
+		}
+
+		if !hasRegistryAuth(secret, registry) {
+			report.add("pullsecret", Blocking, "seed image pull secret %s has no credentials for %s", name, registry)
+		}
+
+		return
+	}
+
+	secret, err := apiClient.Secrets(globalPullSecretNS).Get(context.TODO(), globalPullSecret, metav1.GetOptions{})
+	if err != nil {
+		report.add("pullsecret", Blocking, "global pull secret: %s", err)
+
+		return
+	}
+
+	if !hasRegistryAuth(secret, registry) {
+		report.add("pullsecret", Warning, "neither the ImageBasedUpgrade nor the global pull secret hold "+
+			"credentials for %s", registry)
+	}
+}
+
+// checkOADP checks that the OADP operator, which backs up and restores the workloads, is installed.
+func checkOADP(apiClient *clients.Settings, options Options, report *Report) {
+	severity := Warning
+	if options.RequireOADP {
+		severity = Blocking
+	}
+
+	csvs, err := olm.ListClusterServiceVersionWithNamePattern(apiClient, oadpCSVPattern,
+		imagebasedupgradeparams.OADPNamespace)
+	if err != nil || len(csvs) == 0 {
+		report.add("oadp", severity, "OADP operator CSV not found in namespace %s: %v",
+			imagebasedupgradeparams.OADPNamespace, err)
+
+		return
+	}
+
+	if csv := csvs[0].Object; csv.Status.Phase != "Succeeded" {
+		report.add("oadp", severity, "CSV %s is %s: %s", csv.Name, csv.Status.Phase, csv.Status.Message)
+	}
+}
+
+// checkStage checks that the ImageBasedUpgrade exists and is Idle, Prep being refused in any other stage.
+func checkStage(apiClient *clients.Settings, _ Options, report *Report) {
+	ibu, err := lca.PullImageBasedUpgrade(apiClient, imagebasedupgradeparams.ImagebasedupgradeCrName)
+	if err != nil || ibu.Object == nil {
+		report.add("stage", Blocking, "imagebasedupgrade %s not found: %v",
+			imagebasedupgradeparams.ImagebasedupgradeCrName, err)
+
+		return
+	}
+
+	if ibu.Object.Spec.Stage != lcav1alpha1.Stages.Idle {
+		report.add("stage", Blocking, "imagebasedupgrade is in stage %s", ibu.Object.Spec.Stage)
+
+		return
+	}
+
+	for _, condition := range ibu.Object.Status.Conditions {
+		if condition.Type == string(lcav1alpha1.Stages.Idle) && condition.Status != metav1.ConditionTrue {
+			report.add("stage", Blocking, "imagebasedupgrade is not Idle yet: %s: %s", condition.Reason,
+				condition.Message)
+		}
+	}
+}
+
+// hasRegistryAuth tells whether the docker config of the secret holds credentials for the registry. The keys of the
+// docker config may be scoped to a repository or prefixed with a scheme, e.g. quay.io/org or https://quay.io, and are
+// matched on their host.
+func hasRegistryAuth(secret *corev1.Secret, registry string) bool {
+	var dockerConfig struct {
+		Auths map[string]json.RawMessage `json:"auths"`
+	}
+
+	if json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &dockerConfig) != nil {
+		return false
+	}
+
+	for key := range dockerConfig.Auths {
+		if _, host, found := strings.Cut(key, "://"); found {
+			key = host
+		}
+
+		if host, _, _ := strings.Cut(key, "/"); host == registry {
+			return true
+		}
+	}
+
+	return false
+}
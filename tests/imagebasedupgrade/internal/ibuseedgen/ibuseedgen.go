@@ -0,0 +1,261 @@
+package ibuseedgen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/lca"
+	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeparams"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// completedCondition is set to True by the lifecycle agent once the seed image is pushed.
+	completedCondition = "SeedGenCompleted"
+	failedReason       = "Failed"
+	// seedAuthKey and hubKubeconfigKey are the keys of the seed generation secret read by the lifecycle agent.
+	seedAuthKey      = "seedAuth"
+	hubKubeconfigKey = "hubKubeconfig"
+	// seedClusterInfoLabel is the label of the seed image describing the seed cluster.
+	seedClusterInfoLabel = "com.openshift.lifecycle-agent.seed_cluster_info"
+	deleteTimeout        = 5 * time.Minute
+	pollInterval         = 10 * time.Second
+)
+
+// Options are the parameters of the seed generation.
+type Options struct {
+	SeedImage   string
+	RecertImage string
+	// AuthFile is the registry auth file, in the containers auth.json format, granting push access to SeedImage.
+	AuthFile string
+	// HubKubeconfig is the optional kubeconfig of the hub the seed SNO is managed by, used by the lifecycle agent
+	// to detach the seed SNO from the hub during the generation.
+	HubKubeconfig string
+}
+
+// Generate creates the seed generation secret and the SeedGenerator CR on the seed SNO, replacing the ones left by a
+// previous generation, and waits until the seed image is pushed. API errors are tolerated while waiting since the
+// seed SNO services are stopped during the generation. When the generation fails the reason reported in the
+// SeedGenerator status is returned.
+func Generate(apiClient *clients.Settings, options Options, timeout time.Duration) error {
+	err := createSecret(apiClient, options)
+	if err != nil {
+		return err
+	}
+
+	err = deleteSeedGenerator(apiClient)
+	if err != nil {
+		return err
+	}
+
+	glog.V(90).Infof("Generating seed image %s", options.SeedImage)
+
+	_, err = lca.NewSeedGeneratorBuilder(apiClient, imagebasedupgradeparams.SeedGeneratorName).
+		WithSeedImage(options.SeedImage).
+		WithRecertImage(options.RecertImage).
+		Create()
+	if err != nil {
+		return fmt.Errorf("failed to create seedgenerator: %w", err)
+	}
+
+	return waitForCompletion(apiClient, timeout)
+}
+
+// VerifySeedImage checks with skopeo that the seed image was pushed to the registry and returns its reference by
+// digest.
+func VerifySeedImage(image, authFile string) (string, error) {
+	inspect, err := inspectImage(image, authFile)
+	if err != nil {
+		return "", err
+	}
+
+	if inspect.Digest == "" {
+		return "", fmt.Errorf("no digest reported for seed image %s", image)
+	}
+
+	return repository(image) + "@" + inspect.Digest, nil
+}
+
+// GetSeedImageVersion returns the OCP version of the seed cluster recorded in the labels of the seed image.
+func GetSeedImageVersion(image, authFile string) (string, error) {
+	inspect, err := inspectImage(image, authFile)
+	if err != nil {
+		return "", err
+	}
+
+	clusterInfo, found := inspect.Labels[seedClusterInfoLabel]
+	if !found {
+		return "", fmt.Errorf("seed image %s has no %s label", image, seedClusterInfoLabel)
+	}
+
+	var seedClusterInfo struct {
+		OCPVersion string `json:"seed_cluster_ocp_version"`
+	}
+
+	err = json.Unmarshal([]byte(clusterInfo), &seedClusterInfo)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s label of seed image %s: %w", seedClusterInfoLabel, image, err)
+	}
+
+	if seedClusterInfo.OCPVersion == "" {
+		return "", fmt.Errorf("no OCP version in the %s label of seed image %s", seedClusterInfoLabel, image)
+	}
+
+	return seedClusterInfo.OCPVersion, nil
+}
+
+// imageInspect is the part of the skopeo inspect output used by the seed image checks.
+type imageInspect struct {
+	Digest string            `json:"Digest"`
+	Labels map[string]string `json:"Labels"`
+}
+
+// inspectImage inspects the image in the registry with skopeo.
+func inspectImage(image, authFile string) (*imageInspect, error) {
+	args := []string{"inspect", "--no-tags"}
+
+	if authFile != "" {
+		args = append(args, "--authfile", authFile)
+	}
+
+	output, err := exec.Command("skopeo", append(args, "docker://"+image)...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("seed image %s not found: %s", image, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+
+		return nil, fmt.Errorf("failed to inspect seed image %s: %w", image, err)
+	}
+
+	var inspect imageInspect
+
+	err = json.Unmarshal(output, &inspect)
+	if err != nil {
+		return nil, fmt.Errorf("invalid skopeo output for seed image %s: %w", image, err)
+	}
+
+	return &inspect, nil
+}
+
+// repository strips the tag or digest of an image reference, e.g. registry:5000/org/seed:4.14 is
+// registry:5000/org/seed.
+func repository(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if tag := strings.LastIndex(image, ":"); tag > strings.LastIndex(image, "/") {
+		return image[:tag]
+	}
+
+	return image
+}
+
+// createSecret creates or updates the seed generation secret with the content of the auth and hub kubeconfig files.
+func createSecret(apiClient *clients.Settings, options Options) error {
+	data := make(map[string][]byte)
+
+	seedAuth, err := os.ReadFile(options.AuthFile)
+	if err != nil {
+		return fmt.Errorf("failed to read registry auth file: %w", err)
+	}
+
+	data[seedAuthKey] = seedAuth
+
+	if options.HubKubeconfig != "" {
+		hubKubeconfig, err := os.ReadFile(options.HubKubeconfig)
+		if err != nil {
+			return fmt.Errorf("failed to read hub kubeconfig: %w", err)
+		}
+
+		data[hubKubeconfigKey] = hubKubeconfig
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      imagebasedupgradeparams.SeedGenSecretName,
+			Namespace: imagebasedupgradeparams.ImagebasedupgradeCrNamespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+
+	secrets := apiClient.Secrets(imagebasedupgradeparams.ImagebasedupgradeCrNamespace)
+
+	existing, err := secrets.Get(context.TODO(), secret.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = secrets.Create(context.TODO(), secret, metav1.CreateOptions{})
+	} else if err == nil {
+		existing.Data = data
+		_, err = secrets.Update(context.TODO(), existing, metav1.UpdateOptions{})
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to create secret %s: %w", secret.Name, err)
+	}
+
+	return nil
+}
+
+// deleteSeedGenerator removes the SeedGenerator CR of a previous generation, whose spec cannot be modified.
+func deleteSeedGenerator(apiClient *clients.Settings) error {
+	seedGenerator := lca.NewSeedGeneratorBuilder(apiClient, imagebasedupgradeparams.SeedGeneratorName)
+	if !seedGenerator.Exists() {
+		return nil
+	}
+
+	_, err := seedGenerator.Delete()
+	if err != nil {
+		return err
+	}
+
+	return wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, deleteTimeout, true, func(ctx context.Context) (bool, error) {
+			return !seedGenerator.Exists(), nil
+		})
+}
+
+// waitForCompletion waits until the SeedGenerator reports the seed image pushed or the generation failed.
+func waitForCompletion(apiClient *clients.Settings, timeout time.Duration) error {
+	var genErr error
+
+	seedGenerator := lca.NewSeedGeneratorBuilder(apiClient, imagebasedupgradeparams.SeedGeneratorName)
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			object, err := seedGenerator.Get()
+			if err != nil {
+				glog.V(90).Infof("Failed to get seedgenerator: %s", err)
+
+				return false, nil
+			}
+
+			for _, condition := range object.Status.Conditions {
+				if condition.Reason == failedReason && condition.Status != metav1.ConditionTrue {
+					genErr = fmt.Errorf("seed generation failed: %s: %s", condition.Type, condition.Message)
+
+					return true, nil
+				}
+
+				if condition.Type == completedCondition && condition.Status == metav1.ConditionTrue {
+					return true, nil
+				}
+			}
+
+			return false, nil
+		})
+	if err != nil {
+		return fmt.Errorf("seed image not generated within %s: %w", timeout, err)
+	}
+
+	return genErr
+}
@@ -0,0 +1,572 @@
+package ibuworkload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/deployment"
+	"github.com/openshift-kni/eco-goinfra/pkg/namespace"
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	routev1 "github.com/openshift/api/route/v1"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// appName is the name of every object of the application.
+	appName = "ibu-app"
+	// httpPort is the port served by the httpd image.
+	httpPort = 8080
+	// dataMountPath is the mount path of the PVC, under the document root of the httpd image.
+	dataMountPath = "/var/www/html/data"
+	dataFileName  = "payload"
+	dataSizeMB    = 16
+	volumeSize    = "1Gi"
+	// applyWaveAnnotation orders the restores applied by the lifecycle agent after the upgrade.
+	applyWaveAnnotation = "lca.openshift.io/apply-wave"
+	deleteTimeout       = 5 * time.Minute
+	pollInterval        = 15 * time.Second
+	httpTimeout         = 30 * time.Second
+)
+
+var routeGVR = schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+
+// oadpContentTemplate is the Backup of the application namespace run by the lifecycle agent before the upgrade
+// and its Restore run after it. The PVC data is backed up by the file system backup of the node agent.
+const oadpContentTemplate = `apiVersion: velero.io/v1
+kind: Backup
+metadata:
+  name: %[1]s
+  namespace: %[3]s
+  labels:
+    velero.io/storage-location: default
+spec:
+  includedNamespaces:
+  - %[2]s
+  includedNamespaceScopedResources:
+  - configmaps
+  - secrets
+  - persistentvolumeclaims
+  - deployments
+  - services
+  - routes.route.openshift.io
+  excludedClusterScopedResources:
+  - persistentVolumes
+  defaultVolumesToFsBackup: true
+---
+apiVersion: velero.io/v1
+kind: Restore
+metadata:
+  name: %[1]s
+  namespace: %[3]s
+  labels:
+    velero.io/storage-location: default
+  annotations:
+    %[4]s: "1"
+spec:
+  backupName: %[1]s
+`
+
+// App is the application deployed before the upgrade and the state expected to be restored after it.
+type App struct {
+	Namespace  string            `json:"namespace"`
+	ConfigData map[string]string `json:"configData"`
+	SecretData map[string]string `json:"secretData"`
+	// Checksum is the checksum of the data written to the PVC.
+	Checksum  string `json:"checksum"`
+	RouteHost string `json:"routeHost"`
+}
+
+// Deploy creates the application in a fresh namespace: a ConfigMap, a Secret, a PVC filled with random data, a
+// Deployment serving the data over http and its Service and Route. The default StorageClass is used when
+// storageClass is empty.
+func Deploy(apiClient *clients.Settings, nsname, image, storageClass string, timeout time.Duration) (*App, error) {
+	glog.V(90).Infof("Deploying application %s in namespace %s", appName, nsname)
+
+	err := recreateNamespace(apiClient, nsname)
+	if err != nil {
+		return nil, err
+	}
+
+	app := &App{
+		Namespace:  nsname,
+		ConfigData: map[string]string{"message": "preserved across image based upgrade", "token": rand.String(16)},
+		SecretData: map[string]string{"password": rand.String(24)},
+	}
+
+	deployers := []struct {
+		name   string
+		deploy func() error
+	}{
+		{"configmap", func() error { return createConfigMap(apiClient, app) }},
+		{"secret", func() error { return createSecret(apiClient, app) }},
+		{"pvc", func() error { return createPVC(apiClient, app, storageClass) }},
+		{"deployment", func() error { return createDeployment(apiClient, app, image, timeout) }},
+		{"service", func() error { return createService(apiClient, app) }},
+		{"route", func() error { return createRoute(apiClient, app) }},
+	}
+
+	for _, deployer := range deployers {
+		err = deployer.deploy()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s of application %s: %w", deployer.name, appName, err)
+		}
+	}
+
+	appPod, err := getPod(apiClient, nsname)
+	if err != nil {
+		return nil, err
+	}
+
+	dataFile := dataMountPath + "/" + dataFileName
+
+	_, err = appPod.ExecCommand([]string{"/bin/sh", "-c", fmt.Sprintf(
+		"dd if=/dev/urandom of=%s bs=1M count=%d conv=fsync status=none && sync", dataFile, dataSizeMB)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write data to PVC %s: %w", appName, err)
+	}
+
+	app.Checksum, err = getChecksum(appPod)
+	if err != nil {
+		return nil, err
+	}
+
+	app.RouteHost, err = waitForRoute(apiClient, nsname, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+// ConfigureBackup creates the ConfigMap holding the OADP Backup and Restore of the application namespace, to be
+// referenced by the oadpContent of the ImageBasedUpgrade.
+func ConfigureBackup(apiClient *clients.Settings, app *App, name, oadpNamespace string) error {
+	_, err := apiClient.Namespaces().Get(context.TODO(), oadpNamespace, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("OADP namespace %s not found, is the OADP operator installed: %w", oadpNamespace, err)
+	}
+
+	err = apiClient.ConfigMaps(oadpNamespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: oadpNamespace},
+		Data: map[string]string{
+			"backup_restore_" + app.Namespace + ".yaml": fmt.Sprintf(
+				oadpContentTemplate, appName, app.Namespace, oadpNamespace, applyWaveAnnotation),
+		},
+	}
+
+	glog.V(90).Infof("Creating OADP content configmap %s in namespace %s", name, oadpNamespace)
+
+	_, err = apiClient.ConfigMaps(oadpNamespace).Create(context.TODO(), configMap, metav1.CreateOptions{})
+
+	return err
+}
+
+// Verify checks once that the objects of the application hold their original data, that the PVC data checksum did
+// not change and that the data is served by the Service endpoints and the Route.
+func Verify(apiClient *clients.Settings, app *App) error {
+	checks := []struct {
+		name  string
+		check func(*clients.Settings, *App) error
+	}{
+		{"configmap", verifyConfigMap},
+		{"secret", verifySecret},
+		{"pvc", verifyPVC},
+		{"endpoints", verifyEndpoints},
+		{"route", verifyRoute},
+	}
+
+	var issues []string
+
+	for _, check := range checks {
+		if err := check.check(apiClient, app); err != nil {
+			issues = append(issues, fmt.Sprintf("%s: %s", check.name, err))
+		}
+	}
+
+	if len(issues) > 0 {
+		return errors.New(strings.Join(issues, "; "))
+	}
+
+	return nil
+}
+
+// WaitForRestore waits until the application is verified, the restore running asynchronously after the upgrade.
+// The issues found by the last verification are returned on timeout.
+func WaitForRestore(apiClient *clients.Settings, app *App, timeout time.Duration) error {
+	var lastErr error
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			lastErr = Verify(apiClient, app)
+			if lastErr != nil {
+				glog.V(90).Infof("Application %s not restored yet: %s", appName, lastErr)
+			}
+
+			return lastErr == nil, nil
+		})
+	if err != nil {
+		return fmt.Errorf("application %s not restored within %s: %w", appName, timeout, lastErr)
+	}
+
+	return nil
+}
+
+// Delete removes the application namespace and the OADP content ConfigMap.
+func Delete(apiClient *clients.Settings, nsname, oadpContentName, oadpNamespace string) error {
+	err := apiClient.ConfigMaps(oadpNamespace).Delete(context.TODO(), oadpContentName, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	appNamespace := namespace.NewBuilder(apiClient, nsname)
+	if !appNamespace.Exists() {
+		return nil
+	}
+
+	return appNamespace.DeleteAndWait(deleteTimeout)
+}
+
+func recreateNamespace(apiClient *clients.Settings, nsname string) error {
+	appNamespace := namespace.NewBuilder(apiClient, nsname)
+
+	if appNamespace.Exists() {
+		err := appNamespace.DeleteAndWait(deleteTimeout)
+		if err != nil {
+			return fmt.Errorf("failed to delete namespace %s: %w", nsname, err)
+		}
+	}
+
+	_, err := appNamespace.Create()
+	if err != nil {
+		return fmt.Errorf("failed to create namespace %s: %w", nsname, err)
+	}
+
+	return nil
+}
+
+func createConfigMap(apiClient *clients.Settings, app *App) error {
+	_, err := apiClient.ConfigMaps(app.Namespace).Create(context.TODO(), &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: appName, Namespace: app.Namespace},
+		Data:       app.ConfigData,
+	}, metav1.CreateOptions{})
+
+	return err
+}
+
+func createSecret(apiClient *clients.Settings, app *App) error {
+	_, err := apiClient.Secrets(app.Namespace).Create(context.TODO(), &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: appName, Namespace: app.Namespace},
+		StringData: app.SecretData,
+	}, metav1.CreateOptions{})
+
+	return err
+}
+
+func createPVC(apiClient *clients.Settings, app *App, storageClass string) error {
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: appName, Namespace: app.Namespace},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse(volumeSize)},
+			},
+		},
+	}
+
+	if storageClass != "" {
+		pvc.Spec.StorageClassName = &storageClass
+	}
+
+	_, err := apiClient.PersistentVolumeClaims(app.Namespace).Create(context.TODO(), pvc, metav1.CreateOptions{})
+
+	return err
+}
+
+// createDeployment creates the httpd Deployment mounting the PVC, the ConfigMap and the Secret. Local volumes bind
+// on first consumer, so the PVC is bound once the pod is scheduled.
+func createDeployment(apiClient *clients.Settings, app *App, image string, timeout time.Duration) error {
+	container := &v1.Container{
+		Name:  appName,
+		Image: image,
+		Ports: []v1.ContainerPort{{ContainerPort: httpPort}},
+		VolumeMounts: []v1.VolumeMount{
+			{Name: "data", MountPath: dataMountPath},
+			{Name: "config", MountPath: "/etc/" + appName + "/config", ReadOnly: true},
+			{Name: "secret", MountPath: "/etc/" + appName + "/secret", ReadOnly: true},
+		},
+	}
+
+	appDeployment := deployment.NewBuilder(apiClient, appName, app.Namespace, map[string]string{"app": appName},
+		container).
+		WithVolume(v1.Volume{Name: "data", VolumeSource: v1.VolumeSource{
+			PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: appName}}}).
+		WithVolume(v1.Volume{Name: "config", VolumeSource: v1.VolumeSource{
+			ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: appName}}}}).
+		WithVolume(v1.Volume{Name: "secret", VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{SecretName: appName}}})
+
+	_, err := appDeployment.CreateAndWaitUntilReady(timeout)
+
+	return err
+}
+
+func createService(apiClient *clients.Settings, app *App) error {
+	_, err := apiClient.Services(app.Namespace).Create(context.TODO(), &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: appName, Namespace: app.Namespace},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{"app": appName},
+			Ports:    []v1.ServicePort{{Port: httpPort, TargetPort: intstr.FromInt(httpPort)}},
+		},
+	}, metav1.CreateOptions{})
+
+	return err
+}
+
+func createRoute(apiClient *clients.Settings, app *App) error {
+	route := &routev1.Route{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "route.openshift.io/v1", Kind: "Route"},
+		ObjectMeta: metav1.ObjectMeta{Name: appName, Namespace: app.Namespace},
+		Spec: routev1.RouteSpec{
+			To:   routev1.RouteTargetReference{Kind: "Service", Name: appName},
+			Port: &routev1.RoutePort{TargetPort: intstr.FromInt(httpPort)},
+		},
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(route)
+	if err != nil {
+		return err
+	}
+
+	_, err = apiClient.Resource(routeGVR).Namespace(app.Namespace).Create(
+		context.TODO(), &unstructured.Unstructured{Object: content}, metav1.CreateOptions{})
+
+	return err
+}
+
+// waitForRoute waits until the Route is admitted by the ingress controller and returns its host.
+func waitForRoute(apiClient *clients.Settings, nsname string, timeout time.Duration) (string, error) {
+	var host string
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			var err error
+
+			host, err = getAdmittedRouteHost(apiClient, nsname)
+			if err != nil {
+				glog.V(100).Infof("Route %s not admitted yet: %s", appName, err)
+			}
+
+			return err == nil, nil
+		})
+	if err != nil {
+		return "", fmt.Errorf("route %s not admitted within %s: %w", appName, timeout, err)
+	}
+
+	return host, nil
+}
+
+func getAdmittedRouteHost(apiClient *clients.Settings, nsname string) (string, error) {
+	object, err := apiClient.Resource(routeGVR).Namespace(nsname).Get(context.TODO(), appName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	var route routev1.Route
+
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, &route)
+	if err != nil {
+		return "", err
+	}
+
+	for _, ingress := range route.Status.Ingress {
+		for _, condition := range ingress.Conditions {
+			if condition.Type == routev1.RouteAdmitted && condition.Status == v1.ConditionTrue {
+				return ingress.Host, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("route %s not admitted", appName)
+}
+
+func getPod(apiClient *clients.Settings, nsname string) (*pod.Builder, error) {
+	pods, err := pod.List(apiClient, nsname, metav1.ListOptions{LabelSelector: "app=" + appName})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, appPod := range pods {
+		if appPod.Object.Status.Phase == v1.PodRunning && appPod.Object.DeletionTimestamp == nil {
+			return appPod, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no running pod of application %s", appName)
+}
+
+func getChecksum(appPod *pod.Builder) (string, error) {
+	dataFile := dataMountPath + "/" + dataFileName
+
+	output, err := appPod.ExecCommand([]string{"sha256sum", dataFile})
+	if err != nil {
+		return "", fmt.Errorf("failed to compute checksum of %s: %w", dataFile, err)
+	}
+
+	fields := strings.Fields(output.String())
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty sha256sum output for %s", dataFile)
+	}
+
+	return fields[0], nil
+}
+
+func verifyConfigMap(apiClient *clients.Settings, app *App) error {
+	configMap, err := apiClient.ConfigMaps(app.Namespace).Get(context.TODO(), appName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	return compareData(app.ConfigData, configMap.Data)
+}
+
+func verifySecret(apiClient *clients.Settings, app *App) error {
+	secret, err := apiClient.Secrets(app.Namespace).Get(context.TODO(), appName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	data := make(map[string]string)
+	for key, value := range secret.Data {
+		data[key] = string(value)
+	}
+
+	return compareData(app.SecretData, data)
+}
+
+func verifyPVC(apiClient *clients.Settings, app *App) error {
+	pvc, err := apiClient.PersistentVolumeClaims(app.Namespace).Get(context.TODO(), appName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if pvc.Status.Phase != v1.ClaimBound {
+		return fmt.Errorf("pvc is %s", pvc.Status.Phase)
+	}
+
+	appPod, err := getPod(apiClient, app.Namespace)
+	if err != nil {
+		return err
+	}
+
+	checksum, err := getChecksum(appPod)
+	if err != nil {
+		return err
+	}
+
+	if checksum != app.Checksum {
+		return fmt.Errorf("data checksum %s differs from %s", checksum, app.Checksum)
+	}
+
+	return nil
+}
+
+func verifyEndpoints(apiClient *clients.Settings, app *App) error {
+	endpoints, err := apiClient.Endpoints(app.Namespace).Get(context.TODO(), appName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return nil
+		}
+	}
+
+	return errors.New("no ready endpoint")
+}
+
+// verifyRoute downloads the PVC data through the Route and compares its checksum.
+func verifyRoute(apiClient *clients.Settings, app *App) error {
+	host, err := getAdmittedRouteHost(apiClient, app.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if host != app.RouteHost {
+		return fmt.Errorf("route host %s differs from %s", host, app.RouteHost)
+	}
+
+	url := fmt.Sprintf("http://%s/data/%s", host, dataFileName)
+	httpClient := &http.Client{Timeout: httpTimeout}
+
+	request, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %s", url, response.Status)
+	}
+
+	hash := sha256.New()
+
+	_, err = io.Copy(hash, response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+
+	if checksum := hex.EncodeToString(hash.Sum(nil)); checksum != app.Checksum {
+		return fmt.Errorf("checksum %s of %s differs from %s", checksum, url, app.Checksum)
+	}
+
+	return nil
+}
+
+// compareData returns the keys of the expected data missing or holding another value.
+func compareData(expected, actual map[string]string) error {
+	var differences []string
+
+	for key, value := range expected {
+		if actualValue, found := actual[key]; !found {
+			differences = append(differences, key+" missing")
+		} else if actualValue != value {
+			differences = append(differences, key+" changed")
+		}
+	}
+
+	if len(differences) > 0 {
+		return errors.New(strings.Join(differences, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package imagebasedupgradeconfig
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/config"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// PathToDefaultIBUParamsFile path to config file with default image based upgrade parameters.
+	PathToDefaultIBUParamsFile = "./default.yaml"
+)
+
+// IBUConfig type keeps image based upgrade configuration.
+type IBUConfig struct {
+	*config.GeneralConfig
+	GenerateSeed     bool   `yaml:"ibu_generate_seed" envconfig:"ECO_IBU_GENERATE_SEED"`
+	SeedImage        string `yaml:"ibu_seed_image" envconfig:"ECO_IBU_SEED_IMAGE"`
+	SeedImageVersion string `yaml:"ibu_seed_image_version" envconfig:"ECO_IBU_SEED_IMAGE_VERSION"`
+	RecertImage      string `yaml:"ibu_recert_image" envconfig:"ECO_IBU_RECERT_IMAGE"`
+	PullSecretFile   string `yaml:"ibu_pull_secret_file" envconfig:"ECO_IBU_PULL_SECRET_FILE"`
+	SeedGenTimeout   string `yaml:"ibu_seed_gen_timeout" envconfig:"ECO_IBU_SEED_GEN_TIMEOUT"`
+	WorkloadImage    string `yaml:"ibu_workload_image" envconfig:"ECO_IBU_WORKLOAD_IMAGE"`
+	StorageClass     string `yaml:"ibu_storage_class" envconfig:"ECO_IBU_STORAGE_CLASS"`
+	MinLCAVersion    string `yaml:"ibu_min_lca_version" envconfig:"ECO_IBU_MIN_LCA_VERSION"`
+	ValidateUpgrade  bool   `yaml:"ibu_validate_upgrade" envconfig:"ECO_IBU_VALIDATE_UPGRADE"`
+}
+
+// NewIBUConfig returns instance of IBUConfig config type.
+func NewIBUConfig() *IBUConfig {
+	log.Print("Creating new IBUConfig struct")
+
+	var ibuConf IBUConfig
+	ibuConf.GeneralConfig = config.NewConfig()
+
+	_, filename, _, _ := runtime.Caller(0)
+	baseDir := filepath.Dir(filename)
+	confFile := filepath.Join(baseDir, PathToDefaultIBUParamsFile)
+	err := readFile(&ibuConf, confFile)
+
+	if err != nil {
+		log.Printf("Error to read config file %s", confFile)
+
+		return nil
+	}
+
+	err = readEnv(&ibuConf)
+
+	if err != nil {
+		log.Print("Error to read environment variables")
+
+		return nil
+	}
+
+	return &ibuConf
+}
+
+func readFile(ibuConfig *IBUConfig, cfgFile string) error {
+	openedCfgFile, err := os.Open(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = openedCfgFile.Close()
+	}()
+
+	decoder := yaml.NewDecoder(openedCfgFile)
+	err = decoder.Decode(&ibuConfig)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func readEnv(ibuConfig *IBUConfig) error {
+	err := envconfig.Process("", ibuConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
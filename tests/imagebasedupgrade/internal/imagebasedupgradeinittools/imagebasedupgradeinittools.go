@@ -5,6 +5,7 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeconfig"
 	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeparams"
 	"github.com/openshift-kni/eco-gosystem/tests/internal/inittools"
 )
@@ -18,11 +19,14 @@ var (
 	SeedSNOAPIClient *clients.Settings
 	// TargetSNOAPIClient is the api client to the target SNO cluster.
 	TargetSNOAPIClient *clients.Settings
+	// IBUTestConfig provides access to image based upgrade system tests configuration parameters.
+	IBUTestConfig *imagebasedupgradeconfig.IBUConfig
 )
 
 // init loads all variables automatically when this package is imported. Once package is imported a user has full
 // access to all vars within init function. It is recommended to import this package using dot import.
 func init() {
+	IBUTestConfig = imagebasedupgradeconfig.NewIBUConfig()
 	SeedHubAPIClient = inittools.APIClient
 	TargetHubAPIClient = inittools.APIClient
 	SeedSNOAPIClient = DefineAPIClient(imagebasedupgradeparams.SeedSNOKubeEnvKey)
@@ -1,5 +1,7 @@
 package imagebasedupgradeparams
 
+import "time"
+
 const (
 	// SeedHubKubeEnvKey is the hub's kubeconfig env var.
 	SeedHubKubeEnvKey string = "KUBECONFIG_SEED_HUB"
@@ -13,4 +15,66 @@ const (
 	ImagebasedupgradeCrName string = "upgrade"
 	// ImagebasedupgradeCrNamespace is the Imagebasedupgrade CR namespace.
 	ImagebasedupgradeCrNamespace string = "openshift-lifecycle-agent"
+	// PreUpgradeSnapshotName is the name of the cluster snapshot saved before the upgrade.
+	PreUpgradeSnapshotName string = "ibu_preupgrade"
+	// PostUpgradeSnapshotName is the name of the cluster snapshot saved after the upgrade.
+	PostUpgradeSnapshotName string = "ibu_postupgrade"
+	// PreRollbackSnapshotName is the name of the cluster snapshot saved before each rollback scenario.
+	PreRollbackSnapshotName string = "ibu_prerollback"
+	// PostRollbackSnapshotName is the prefix of the names of the cluster snapshots saved after each rollback.
+	PostRollbackSnapshotName string = "ibu_postrollback"
+	// SeedGeneratorName is the name of the SeedGenerator CR expected by the lifecycle agent.
+	SeedGeneratorName string = "seedimage"
+	// SeedGenSecretName is the name of the secret holding the seed generation credentials.
+	SeedGenSecretName string = "seedgen"
+	// InitMonitorTimeoutAnnotation sets the time, in seconds, given to the upgraded cluster to be ready after the
+	// reboot into the new stateroot before the lifecycle agent rolls it back.
+	InitMonitorTimeoutAnnotation string = "auto-rollback-on-failure.lca.openshift.io/init-monitor-timeout"
+	// InjectedInitMonitorTimeout is an init monitor timeout too short for the upgrade to complete, which triggers the
+	// auto-rollback.
+	InjectedInitMonitorTimeout string = "10"
+	// WorkloadNamespace is the namespace of the application preserved across the upgrade.
+	WorkloadNamespace string = "ibu-workload"
+	// OADPNamespace is the namespace of the OADP operator.
+	OADPNamespace string = "openshift-adp"
+	// OADPContentName is the name of the configmap holding the OADP backup and restore of the application,
+	// referenced by the ImageBasedUpgrade CR.
+	OADPContentName string = "ibu-oadp-content"
+	// InvalidSeedImage is a seed image which cannot be pulled, which fails the Prep stage.
+	InvalidSeedImage string = "quay.io/openshift-kni/eco-gosystem-invalid-seed:none"
+)
+
+const (
+	// PrepTimeout is the time to wait for the Prep stage to complete, including the seed image pull.
+	PrepTimeout = 45 * time.Minute
+	// UpgradeTimeout is the time to wait for the Upgrade stage to complete, including the reboot into the new
+	// stateroot.
+	UpgradeTimeout = 60 * time.Minute
+	// RollbackTimeout is the time to wait for the Rollback stage to complete, including the reboot into the
+	// original stateroot.
+	RollbackTimeout = 45 * time.Minute
+	// AutoRollbackTimeout is the time to wait for the lifecycle agent to roll an upgrade back on its own, including
+	// the reboots into the new and the original stateroots.
+	AutoRollbackTimeout = 90 * time.Minute
+	// RestoreTimeout is the time to wait for the workloads to be ready again after a rollback.
+	RestoreTimeout = 15 * time.Minute
+	// WorkloadTimeout is the time to wait for the application to be ready after its creation.
+	WorkloadTimeout = 10 * time.Minute
+	// WorkloadRestoreTimeout is the time to wait for the application to be restored after the upgrade.
+	WorkloadRestoreTimeout = 20 * time.Minute
+	// ACMRegistrationTimeout is the time to wait for the target SNO to be registered and reporting to the hub again
+	// after the upgrade.
+	ACMRegistrationTimeout = 20 * time.Minute
+	// ACMMaxLeaseAge is the max time since the last renewal of the lease of the target SNO on the hub.
+	ACMMaxLeaseAge = 5 * time.Minute
+	// IdleTimeout is the time to wait for the ImageBasedUpgrade to be back to Idle.
+	IdleTimeout = 20 * time.Minute
+)
+
+const (
+	// MinVarFree is the free space of /var, in bytes, required on the target SNO to unpack the seed image during
+	// Prep.
+	MinVarFree int64 = 50 << 30
+	// RecommendedVarFree is the free space of /var, in bytes, below which a warning is reported.
+	RecommendedVarFree int64 = 100 << 30
 )
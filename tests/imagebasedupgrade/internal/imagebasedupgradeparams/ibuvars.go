@@ -1,17 +1,11 @@
 package imagebasedupgradeparams
 
-// ClusterStruct is a struct that holds the cluster version and id.
-type ClusterStruct struct {
-	Version   string
-	ID        string
-	Operators []string
-	NodeName  string
-}
+import "github.com/openshift-kni/eco-gosystem/tests/internal/snapshot"
 
 var (
-	// PreUpgradeClusterInfo holds the cluster info pre upgrade.
-	PreUpgradeClusterInfo = ClusterStruct{}
+	// PreUpgradeSnapshot holds the cluster snapshot pre upgrade.
+	PreUpgradeSnapshot *snapshot.Snapshot
 
-	// PostUpgradeClusterInfo holds the cluster info post upgrade.
-	PostUpgradeClusterInfo = ClusterStruct{}
+	// PostUpgradeSnapshot holds the cluster snapshot post upgrade.
+	PostUpgradeSnapshot *snapshot.Snapshot
 )
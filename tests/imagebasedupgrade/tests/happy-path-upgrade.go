@@ -1,68 +1,206 @@
 package tests
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/openshift-kni/eco-goinfra/pkg/lca"
 	"github.com/openshift-kni/eco-goinfra/pkg/polarion"
 	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/ibuclusterinfo"
-	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeinittools"
+	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/ibudriver"
+	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/ibuseedgen"
+	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/ibuworkload"
+	. "github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeinittools"
 	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeparams"
 	ibuvalidations "github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/validations"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/acm"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
+	lcav1alpha1 "github.com/openshift-kni/lifecycle-agent/api/v1alpha1"
 )
 
-// IbuCr is a dedicated var to use and act on it.
-var IbuCr = lca.NewImageBasedUpgradeBuilder(
-	imagebasedupgradeinittools.TargetSNOAPIClient, imagebasedupgradeparams.ImagebasedupgradeCrName)
-
 var _ = Describe(
 	"HappyPathUpgrade",
 	Ordered,
 	ContinueOnFailure,
 	Label("HappyPathUpgrade"), func() {
+		var (
+			seedImage, seedImageVersion string
+			app                         *ibuworkload.App
+		)
+
 		BeforeAll(func() {
+			if IBUTestConfig.ValidateUpgrade {
+				Skip("Only validating the upgrade of a previous run")
+			}
+
+			seedImage = IBUTestConfig.SeedImage
+			seedImageVersion = IBUTestConfig.SeedImageVersion
+
 			By("Generating seed image", func() {
-				// Test Automation Code Implementation is to be done.
+				if !IBUTestConfig.GenerateSeed {
+					glog.V(100).Infof("Seed generation disabled, using seed image %s", seedImage)
+
+					return
+				}
+
+				Expect(seedImage).ToNot(BeEmpty(), "No seed image configured")
+
+				timeout, err := time.ParseDuration(IBUTestConfig.SeedGenTimeout)
+				Expect(err).ToNot(HaveOccurred(), "invalid seed generation timeout")
+
+				if seedImageVersion == "" {
+					seedImageVersion, err = cluster.GetClusterVersion(SeedSNOAPIClient)
+					Expect(err).ToNot(HaveOccurred(), "Failed to get the seed cluster version")
+				}
+
+				var hubKubeconfig string
+				if SeedHubAPIClient != nil {
+					hubKubeconfig = SeedHubAPIClient.KubeconfigPath
+				}
+
+				err = ibuseedgen.Generate(SeedSNOAPIClient, ibuseedgen.Options{
+					SeedImage:     seedImage,
+					RecertImage:   IBUTestConfig.RecertImage,
+					AuthFile:      IBUTestConfig.PullSecretFile,
+					HubKubeconfig: hubKubeconfig,
+				}, timeout)
+				Expect(err).ToNot(HaveOccurred(), "Failed to generate seed image")
+
+				seedImage, err = ibuseedgen.VerifySeedImage(seedImage, IBUTestConfig.PullSecretFile)
+				Expect(err).ToNot(HaveOccurred(), "Seed image was not pushed")
 			})
 
-			By("Saving pre upgrade cluster info", func() {
-				err := ibuclusterinfo.SaveClusterInfo(&imagebasedupgradeparams.PreUpgradeClusterInfo)
-				Expect(err).ToNot(HaveOccurred(), "Failed to save pre upgrade cluster info")
+			By("Deploying the application preserved across the upgrade in Target SNO", func() {
+				var err error
+
+				app, err = ibuworkload.Deploy(TargetSNOAPIClient, imagebasedupgradeparams.WorkloadNamespace,
+					IBUTestConfig.WorkloadImage, IBUTestConfig.StorageClass, imagebasedupgradeparams.WorkloadTimeout)
+				Expect(err).ToNot(HaveOccurred(), "Failed to deploy the application")
 			})
-		})
 
-		AfterAll(func() {
-			// Rollback to pre-upgrade state and reset PolicyGenTemplate.
+			By("Configuring the OADP backup of the application in Target SNO", func() {
+				err := ibuworkload.ConfigureBackup(TargetSNOAPIClient, app, imagebasedupgradeparams.OADPContentName,
+					imagebasedupgradeparams.OADPNamespace)
+				Expect(err).ToNot(HaveOccurred(), "Failed to configure the OADP backup of the application")
+			})
+
+			By("Saving pre upgrade cluster snapshot", func() {
+				var err error
+
+				imagebasedupgradeparams.PreUpgradeSnapshot, err = ibuclusterinfo.TakeSnapshot(
+					imagebasedupgradeparams.PreUpgradeSnapshotName)
+				Expect(err).ToNot(HaveOccurred(), "Failed to save pre upgrade cluster snapshot")
+			})
 		})
 
 		It("End to end upgrade happy path", polarion.ID("68954"), Label("HappyPathUpgrade"), func() {
+			driver := ibudriver.NewDriver(TargetSNOAPIClient,
+				imagebasedupgradeparams.ImagebasedupgradeCrName)
+
+			// Persist stage metrics to ginkgo report for further processing in pipeline, even when a stage fails.
+			DeferCleanup(func() {
+				for metricName, metricValue := range driver.Metrics("happypath") {
+					_, err := fmt.Fprintf(GinkgoWriter, "%s: %s\n", metricName, metricValue)
+					Expect(err).ToNot(HaveOccurred())
+				}
+			})
 
 			By("Checking ImageBasedUpgrade CR exists with Idle stage in Target SNO", func() {
-				crExists := IbuCr.Exists()
-				Expect(crExists).To(BeTrue())
+				ibu, err := driver.Get()
+				Expect(err).ToNot(HaveOccurred(), "Failed to get the ImageBasedUpgrade")
+				Expect(ibu.Spec.Stage).To(Equal(lcav1alpha1.Stages.Idle), "ImageBasedUpgrade is not Idle")
+			})
+
+			if seedImage != "" {
+				By("Updating ImageBasedUpgrade CR with the seed image in Target SNO", func() {
+					err := driver.SetSeedImage(seedImage, seedImageVersion)
+					Expect(err).ToNot(HaveOccurred(), "Failed to set the seed image")
+				})
+			}
+
+			By("Updating ImageBasedUpgrade CR with the OADP content in Target SNO", func() {
+				err := driver.SetOADPContent(imagebasedupgradeparams.OADPContentName,
+					imagebasedupgradeparams.OADPNamespace)
+				Expect(err).ToNot(HaveOccurred(), "Failed to set the OADP content")
+			})
+
+			By("Running the pre-upgrade readiness checks on Target SNO", func() {
+				checkReadiness(seedImage, seedImageVersion, true)
 			})
 
 			By("Updating ImageBasedUpgrade CR with Prep stage in Target SNO", func() {
-				IbuCr.WithStage("Prep")
+				err := driver.Advance(lcav1alpha1.Stages.Prep, imagebasedupgradeparams.PrepTimeout)
+				Expect(err).ToNot(HaveOccurred(), "Prep stage failed")
 			})
 
+			var upgradeCompleted time.Time
+
 			By("Updating ImageBasedUpgrade CR with Upgrade stage in Target SNO", func() {
-				IbuCr.WithStage("Upgrade")
+				err := driver.Advance(lcav1alpha1.Stages.Upgrade, imagebasedupgradeparams.UpgradeTimeout)
+				Expect(err).ToNot(HaveOccurred(), "Upgrade stage failed")
+
+				upgradeCompleted = time.Now()
+			})
+
+			By("Verifying the application was restored in Target SNO", func() {
+				err := ibuworkload.WaitForRestore(TargetSNOAPIClient, app, imagebasedupgradeparams.WorkloadRestoreTimeout)
+				Expect(err).ToNot(HaveOccurred(), "Application was not restored")
 			})
 
-			By(" Verifying target SNO cluster ACM registration post upgrade", func() {
+			By("Saving post upgrade cluster snapshot", func() {
+				var err error
 
+				imagebasedupgradeparams.PostUpgradeSnapshot, err = ibuclusterinfo.TakeSnapshot(
+					imagebasedupgradeparams.PostUpgradeSnapshotName)
+				Expect(err).ToNot(HaveOccurred(), "Failed to save post upgrade cluster snapshot")
 			})
 
-			By("Updating ImageBasedUpgrade CR with Idle stage in Target SNO", func() {
-				IbuCr.WithStage("Idle")
+			By("Verifying target SNO cluster ACM registration post upgrade", func() {
+				Expect(TargetHubAPIClient).ToNot(BeNil(), "No api client to the target hub cluster")
+
+				clusterName, err := acm.FindManagedCluster(TargetHubAPIClient,
+					imagebasedupgradeparams.PostUpgradeSnapshot.ClusterID)
+				Expect(err).ToNot(HaveOccurred(), "Failed to find the target SNO managed cluster")
 
+				report, err := acm.WaitForRegistration(TargetHubAPIClient, clusterName, acm.Options{
+					MaxLeaseAge:     imagebasedupgradeparams.ACMMaxLeaseAge,
+					ComplianceSince: upgradeCompleted,
+				}, imagebasedupgradeparams.ACMRegistrationTimeout)
+				if report != nil {
+					fmt.Print(report.String())
+				}
+
+				Expect(err).ToNot(HaveOccurred(), "Target SNO is not registered to the hub")
 			})
-		})
 
-		err := ibuclusterinfo.SaveClusterInfo(&imagebasedupgradeparams.PostUpgradeClusterInfo)
-		Expect(err).ToNot(HaveOccurred(), "Failed to save post upgrade cluster info")
+			By("Updating ImageBasedUpgrade CR with Idle stage in Target SNO", func() {
+				err := driver.Advance(lcav1alpha1.Stages.Idle, imagebasedupgradeparams.IdleTimeout)
+				Expect(err).ToNot(HaveOccurred(), "Idle stage failed")
+			})
+		})
 
+		// The validations are declared in the ordered container so that they run after the upgrade.
 		ibuvalidations.PostUpgradeValidations()
 
+		AfterAll(func() {
+			// The application restored by the upgrade of a previous run is left to its validation.
+			if IBUTestConfig.ValidateUpgrade {
+				return
+			}
+
+			By("Deleting the application and its OADP content in Target SNO", func() {
+				err := ibuworkload.Delete(TargetSNOAPIClient, imagebasedupgradeparams.WorkloadNamespace,
+					imagebasedupgradeparams.OADPContentName, imagebasedupgradeparams.OADPNamespace)
+				Expect(err).ToNot(HaveOccurred(), "Failed to delete the application")
+			})
+
+			By("Removing the OADP content from ImageBasedUpgrade CR in Target SNO", func() {
+				err := ibudriver.NewDriver(TargetSNOAPIClient, imagebasedupgradeparams.ImagebasedupgradeCrName).
+					RemoveOADPContent(imagebasedupgradeparams.OADPContentName, imagebasedupgradeparams.OADPNamespace)
+				Expect(err).ToNot(HaveOccurred(), "Failed to remove the OADP content")
+			})
+		})
 	})
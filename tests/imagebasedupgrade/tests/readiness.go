@@ -0,0 +1,39 @@
+package tests
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/ibureadiness"
+	. "github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeinittools"
+	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeparams"
+)
+
+// The readiness checks run standalone to qualify a target SNO before an upgrade, without changing it.
+var _ = Describe(
+	"Readiness",
+	Ordered,
+	ContinueOnFailure,
+	Label("Readiness"), func() {
+		It("Verifies the target SNO is ready for an image based upgrade", Label("Readiness"), func() {
+			checkReadiness(IBUTestConfig.SeedImage, IBUTestConfig.SeedImageVersion, false)
+		})
+	})
+
+// checkReadiness runs the readiness checks against the target SNO, prints the findings and fails on the blocking
+// ones. An empty seed image checks the seed image set in the ImageBasedUpgrade CR.
+func checkReadiness(seedImage, seedImageVersion string, requireOADP bool) {
+	report := ibureadiness.Run(TargetSNOAPIClient, ibureadiness.Options{
+		SeedImage:          seedImage,
+		SeedImageVersion:   seedImageVersion,
+		AuthFile:           IBUTestConfig.PullSecretFile,
+		MinLCAVersion:      IBUTestConfig.MinLCAVersion,
+		MinVarFree:         imagebasedupgradeparams.MinVarFree,
+		RecommendedVarFree: imagebasedupgradeparams.RecommendedVarFree,
+		RequireOADP:        requireOADP,
+	})
+
+	fmt.Print(report.String())
+	Expect(report.Blocking()).To(BeEmpty(), "Target SNO is not ready for the upgrade")
+}
@@ -0,0 +1,219 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/ibuclusterinfo"
+	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/ibudriver"
+	. "github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeinittools"
+	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeparams"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/snapshot"
+	lcav1alpha1 "github.com/openshift-kni/lifecycle-agent/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe(
+	"Rollback",
+	Ordered,
+	ContinueOnFailure,
+	Label("Rollback"), func() {
+		var (
+			seedImageVersion    string
+			preRollbackSnapshot *snapshot.Snapshot
+		)
+
+		BeforeAll(func() {
+			if IBUTestConfig.ValidateUpgrade {
+				Skip("Only validating the upgrade of a previous run")
+			}
+
+			if IBUTestConfig.SeedImage == "" {
+				Skip("No seed image configured for the rollback scenarios")
+			}
+
+			seedImageVersion = IBUTestConfig.SeedImageVersion
+			if seedImageVersion == "" {
+				var err error
+
+				seedImageVersion, err = cluster.GetClusterVersion(SeedSNOAPIClient)
+				Expect(err).ToNot(HaveOccurred(), "Failed to get the seed cluster version")
+			}
+		})
+
+		// Each scenario starts from an Idle ImageBasedUpgrade CR and is compared with the state of the target SNO
+		// right before it, so that it does not depend on the specs run before it in the suite.
+		BeforeEach(func() {
+			By("Checking ImageBasedUpgrade CR is Idle in Target SNO", func() {
+				validateIdle(newDriver())
+			})
+
+			By("Saving pre rollback cluster snapshot", func() {
+				var err error
+
+				preRollbackSnapshot, err = ibuclusterinfo.TakeSnapshot(imagebasedupgradeparams.PreRollbackSnapshotName)
+				Expect(err).ToNot(HaveOccurred(), "Failed to save pre rollback cluster snapshot")
+			})
+		})
+
+		It("Rollback after a failed Prep", Label("PrepFailureRollback"), func() {
+			driver := newDriver()
+
+			By("Updating ImageBasedUpgrade CR with an invalid seed image in Target SNO", func() {
+				err := driver.SetSeedImage(imagebasedupgradeparams.InvalidSeedImage, seedImageVersion)
+				Expect(err).ToNot(HaveOccurred(), "Failed to set the seed image")
+			})
+
+			By("Verifying the Prep stage fails", func() {
+				err := driver.Advance(lcav1alpha1.Stages.Prep, imagebasedupgradeparams.PrepTimeout)
+				Expect(err).To(MatchError(ContainSubstring("Failed")), "Prep stage did not fail")
+			})
+
+			By("Updating ImageBasedUpgrade CR with Idle stage in Target SNO", func() {
+				err := driver.Advance(lcav1alpha1.Stages.Idle, imagebasedupgradeparams.IdleTimeout)
+				Expect(err).ToNot(HaveOccurred(), "Idle stage failed")
+			})
+
+			validateRollback(driver, preRollbackSnapshot, "prepfailure")
+		})
+
+		It("Manual rollback from the Upgrade stage", Label("ManualRollback"), func() {
+			driver := newDriver()
+
+			err := upgrade(driver, seedImageVersion, imagebasedupgradeparams.UpgradeTimeout)
+			Expect(err).ToNot(HaveOccurred(), "Upgrade stage failed")
+
+			By("Updating ImageBasedUpgrade CR with Rollback stage in Target SNO", func() {
+				err := driver.Advance(lcav1alpha1.Stages.Rollback, imagebasedupgradeparams.RollbackTimeout)
+				Expect(err).ToNot(HaveOccurred(), "Rollback stage failed")
+			})
+
+			By("Updating ImageBasedUpgrade CR with Idle stage in Target SNO", func() {
+				err := driver.Advance(lcav1alpha1.Stages.Idle, imagebasedupgradeparams.IdleTimeout)
+				Expect(err).ToNot(HaveOccurred(), "Idle stage failed")
+			})
+
+			validateRollback(driver, preRollbackSnapshot, "manual")
+		})
+
+		It("Auto-rollback on upgrade failure", Label("AutoRollback"), func() {
+			driver := newDriver()
+
+			By("Injecting an upgrade failure through a too short init monitor timeout", func() {
+				err := driver.SetAnnotation(imagebasedupgradeparams.InitMonitorTimeoutAnnotation,
+					imagebasedupgradeparams.InjectedInitMonitorTimeout)
+				Expect(err).ToNot(HaveOccurred(), "Failed to annotate the ImageBasedUpgrade CR")
+			})
+
+			DeferCleanup(func() {
+				err := driver.SetAnnotation(imagebasedupgradeparams.InitMonitorTimeoutAnnotation, "")
+				Expect(err).ToNot(HaveOccurred(), "Failed to remove the ImageBasedUpgrade CR annotation")
+			})
+
+			upgradeErr := upgrade(driver, seedImageVersion, imagebasedupgradeparams.AutoRollbackTimeout)
+
+			By("Verifying the upgrade was rolled back", func() {
+				Expect(upgradeErr).To(MatchError(ContainSubstring("Failed")), "Upgrade was not rolled back")
+			})
+
+			By("Updating ImageBasedUpgrade CR with Idle stage in Target SNO", func() {
+				err := driver.Advance(lcav1alpha1.Stages.Idle, imagebasedupgradeparams.IdleTimeout)
+				Expect(err).ToNot(HaveOccurred(), "Idle stage failed")
+			})
+
+			validateRollback(driver, preRollbackSnapshot, "auto")
+		})
+	})
+
+// newDriver returns a driver of the ImageBasedUpgrade CR of the target SNO.
+func newDriver() *ibudriver.Driver {
+	return ibudriver.NewDriver(TargetSNOAPIClient, imagebasedupgradeparams.ImagebasedupgradeCrName)
+}
+
+// upgrade sets the configured seed image and moves the ImageBasedUpgrade through the Prep and Upgrade stages. The
+// error of the Upgrade stage is returned for the scenarios expecting it to fail.
+func upgrade(driver *ibudriver.Driver, seedImageVersion string, upgradeTimeout time.Duration) error {
+	By("Updating ImageBasedUpgrade CR with the seed image in Target SNO", func() {
+		err := driver.SetSeedImage(IBUTestConfig.SeedImage, seedImageVersion)
+		Expect(err).ToNot(HaveOccurred(), "Failed to set the seed image")
+	})
+
+	By("Updating ImageBasedUpgrade CR with Prep stage in Target SNO", func() {
+		err := driver.Advance(lcav1alpha1.Stages.Prep, imagebasedupgradeparams.PrepTimeout)
+		Expect(err).ToNot(HaveOccurred(), "Prep stage failed")
+	})
+
+	var err error
+
+	By("Updating ImageBasedUpgrade CR with Upgrade stage in Target SNO", func() {
+		err = driver.Advance(lcav1alpha1.Stages.Upgrade, upgradeTimeout)
+	})
+
+	return err
+}
+
+// validateRollback verifies that the ImageBasedUpgrade CR is Idle and that the snapshot of the target SNO is back to
+// the pre-upgrade one according to the rollback rules, and persists the stage metrics.
+func validateRollback(driver *ibudriver.Driver, preRollbackSnapshot *snapshot.Snapshot, tag string) {
+	// Persist stage metrics to ginkgo report for further processing in pipeline.
+	for metricName, metricValue := range driver.Metrics("rollback" + tag) {
+		_, err := fmt.Fprintf(GinkgoWriter, "%s: %s\n", metricName, metricValue)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	By("Verifying ImageBasedUpgrade CR ends Idle in Target SNO", func() {
+		validateIdle(driver)
+	})
+
+	By("Verifying the cluster is back to its pre-upgrade state", func() {
+		var report *snapshot.Report
+
+		// The workloads are ready again a while after the node rebooted into its previous stateroot.
+		Eventually(func() ([]string, error) {
+			postRollbackSnapshot, err := ibuclusterinfo.TakeSnapshot(
+				imagebasedupgradeparams.PostRollbackSnapshotName + "_" + tag)
+			if err != nil {
+				return nil, err
+			}
+
+			report, err = snapshot.Diff(preRollbackSnapshot, postRollbackSnapshot, snapshot.RollbackRules())
+			if err != nil {
+				return nil, err
+			}
+
+			return report.Failures(), nil
+		}, imagebasedupgradeparams.RestoreTimeout, 30*time.Second).Should(BeEmpty(), func() string {
+			if report == nil {
+				return "Failed to compare cluster snapshots"
+			}
+
+			return "Cluster was not restored to its pre-upgrade state\n" + report.String()
+		})
+
+		fmt.Print(report.String())
+	})
+}
+
+// validateIdle verifies that the ImageBasedUpgrade CR reports the Idle condition and no stage in progress.
+func validateIdle(driver *ibudriver.Driver) {
+	conditions, err := driver.Conditions()
+	Expect(err).ToNot(HaveOccurred(), "Failed to get the ImageBasedUpgrade CR conditions")
+
+	var idle bool
+
+	for _, condition := range conditions {
+		if condition.Type == string(lcav1alpha1.Stages.Idle) {
+			idle = condition.Status == metav1.ConditionTrue
+		}
+
+		if strings.HasSuffix(condition.Type, "InProgress") {
+			Expect(condition.Status).ToNot(Equal(metav1.ConditionTrue), "%s: %s", condition.Type, condition.Message)
+		}
+	}
+
+	Expect(idle).To(BeTrue(), "ImageBasedUpgrade CR is not Idle")
+}
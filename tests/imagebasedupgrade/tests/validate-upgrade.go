@@ -0,0 +1,28 @@
+package tests
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeinittools"
+	ibuvalidations "github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/validations"
+)
+
+// The post upgrade validations run against the snapshots saved by a previous invocation under the configured run
+// ID, e.g. once an upgrade interrupted by an executor restart completed. They only run when requested since the
+// upgrade scenarios of the same invocation would replace the snapshots.
+var _ = Describe(
+	"ValidateUpgrade",
+	Ordered,
+	ContinueOnFailure,
+	Label("ValidateUpgrade"), func() {
+		BeforeAll(func() {
+			if !IBUTestConfig.ValidateUpgrade {
+				Skip("Upgrade validation not requested, please set the ECO_IBU_VALIDATE_UPGRADE env var")
+			}
+
+			if IBUTestConfig.RunID == "" {
+				Skip("No run ID configured to load the upgrade snapshots, please set the ECO_RUN_ID env var")
+			}
+		})
+
+		ibuvalidations.PostUpgradeValidations()
+	})
@@ -1,14 +1,19 @@
 package ibuvalidations
 
 import (
+	"fmt"
+
+	"github.com/blang/semver/v4"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/openshift-kni/eco-goinfra/pkg/pod"
 	"github.com/openshift-kni/eco-goinfra/pkg/polarion"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/ibuclusterinfo"
 	. "github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeinittools"
 	"github.com/openshift-kni/eco-gosystem/tests/imagebasedupgrade/internal/imagebasedupgradeparams"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/snapshot"
 )
 
 // PostUpgradeValidations is a dedicated func to run post upgrade test validations.
@@ -17,38 +22,87 @@ func PostUpgradeValidations() {
 		"PostIBUValidations",
 		Ordered,
 		Label("PostIBUValidations"), func() {
+			BeforeAll(func() {
+				var err error
+
+				if imagebasedupgradeparams.PreUpgradeSnapshot == nil {
+					By("Loading pre upgrade cluster snapshot", func() {
+						imagebasedupgradeparams.PreUpgradeSnapshot, err = ibuclusterinfo.LoadSnapshot(
+							imagebasedupgradeparams.PreUpgradeSnapshotName)
+						Expect(err).ToNot(HaveOccurred(), "Failed to load pre upgrade cluster snapshot")
+					})
+				}
+
+				if imagebasedupgradeparams.PostUpgradeSnapshot == nil {
+					By("Loading or saving post upgrade cluster snapshot", func() {
+						imagebasedupgradeparams.PostUpgradeSnapshot, err = ibuclusterinfo.LoadSnapshot(
+							imagebasedupgradeparams.PostUpgradeSnapshotName)
+						if err != nil {
+							imagebasedupgradeparams.PostUpgradeSnapshot, err = ibuclusterinfo.TakeSnapshot(
+								imagebasedupgradeparams.PostUpgradeSnapshotName)
+						}
+
+						Expect(err).ToNot(HaveOccurred(), "Failed to save post upgrade cluster snapshot")
+					})
+				}
+			})
+
 			It("Validate Cluster version", polarion.ID("99998"), Label("ValidateClusterVersion"), func() {
 				By("Validate upgraded cluster version reports correct version", func() {
-					Expect(imagebasedupgradeparams.PreUpgradeClusterInfo.Version).
-						ToNot(Equal(imagebasedupgradeparams.PostUpgradeClusterInfo.Version), "Cluster version hasn't changed")
+					Expect(imagebasedupgradeparams.PreUpgradeSnapshot.Version).
+						ToNot(Equal(imagebasedupgradeparams.PostUpgradeSnapshot.Version), "Cluster version hasn't changed")
 				})
 			})
 
 			It("Validate Cluster ID", polarion.ID("99999"), Label("ValidateClusterID"), func() {
 				By("Validate cluster ID remains the same", func() {
-					Expect(imagebasedupgradeparams.PreUpgradeClusterInfo.ID).
-						To(Equal(imagebasedupgradeparams.PostUpgradeClusterInfo.ID), "Cluster ID has changed")
+					Expect(imagebasedupgradeparams.PreUpgradeSnapshot.ClusterID).
+						To(Equal(imagebasedupgradeparams.PostUpgradeSnapshot.ClusterID), "Cluster ID has changed")
 				})
 			})
 
 			It("Validate CSV versions", polarion.ID("99999"), Label("ValidateCSV"), func() {
-				By("Validate CSVs were upgraded", func() {
-					for _, preUpgradeOperator := range imagebasedupgradeparams.PreUpgradeClusterInfo.Operators {
-						for _, postUpgradeOperator := range imagebasedupgradeparams.PostUpgradeClusterInfo.Operators {
-							Expect(preUpgradeOperator).ToNot(Equal(postUpgradeOperator), "Operator %s was not upgraded", preUpgradeOperator)
-						}
+				By("Validate no CSV is missing or downgraded", func() {
+					for operator, preUpgradeVersion := range imagebasedupgradeparams.PreUpgradeSnapshot.CSVs {
+						postUpgradeVersion, found := imagebasedupgradeparams.PostUpgradeSnapshot.CSVs[operator]
+						Expect(found).To(BeTrue(), "Operator %s is missing after the upgrade", operator)
+
+						preVersion, err := semver.ParseTolerant(preUpgradeVersion)
+						Expect(err).ToNot(HaveOccurred(), "Invalid version of operator %s", operator)
+
+						postVersion, err := semver.ParseTolerant(postUpgradeVersion)
+						Expect(err).ToNot(HaveOccurred(), "Invalid version of operator %s", operator)
+
+						Expect(postVersion.LT(preVersion)).To(BeFalse(), "Operator %s was downgraded from %s to %s",
+							operator, preUpgradeVersion, postUpgradeVersion)
 					}
 				})
 			})
 
+			It("Validate cluster snapshot", Label("ValidateSnapshot"), func() {
+				By("Validate the cluster state changes are the ones expected from an upgrade", func() {
+					// The PVCs restored by OADP after the upgrade are bound to newly provisioned volumes.
+					rules := append(snapshot.UpgradeRules(), snapshot.Rule{Path: "pvBindings", Expect: snapshot.MayChange})
+
+					report, err := snapshot.Diff(imagebasedupgradeparams.PreUpgradeSnapshot,
+						imagebasedupgradeparams.PostUpgradeSnapshot, rules)
+					Expect(err).ToNot(HaveOccurred(), "Failed to compare cluster snapshots")
+
+					fmt.Print(report.String())
+					Expect(report.Failures()).To(BeEmpty(), "Unexpected cluster state changes")
+				})
+			})
+
 			It("Validate no pods using seed name", polarion.ID("99999"), Label("ValidatePodsSeedName"), func() {
 				By("Validate no pods are using seed's name", func() {
 					podList, err := pod.ListInAllNamespaces(TargetSNOAPIClient, v1.ListOptions{})
 					Expect(err).ToNot(HaveOccurred(), "Failed to list pods")
 
-					for _, pod := range podList {
-						Expect(pod.Object.Name).ToNot(ContainSubstring(imagebasedupgradeparams.PreUpgradeClusterInfo.NodeName),
-							"Pod %s is using seed's name", pod.Object.Name)
+					for nodeName := range imagebasedupgradeparams.PreUpgradeSnapshot.Nodes {
+						for _, pod := range podList {
+							Expect(pod.Object.Name).ToNot(ContainSubstring(nodeName), "Pod %s is using seed's name",
+								pod.Object.Name)
+						}
 					}
 				})
 			})
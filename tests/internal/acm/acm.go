@@ -0,0 +1,353 @@
+package acm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// clusterIDClaim is the cluster claim of a ManagedCluster holding the ID of the OpenShift cluster.
+	clusterIDClaim = "id.openshift.io"
+	// leaseName is the lease renewed on the hub by the registration agent of the managed cluster, in the namespace of
+	// the cluster.
+	leaseName    = "managed-cluster-lease"
+	pollInterval = 15 * time.Second
+)
+
+var (
+	managedClusterGVR = schema.GroupVersionResource{
+		Group: "cluster.open-cluster-management.io", Version: "v1", Resource: "managedclusters"}
+	managedClusterAddOnGVR = schema.GroupVersionResource{
+		Group: "addon.open-cluster-management.io", Version: "v1alpha1", Resource: "managedclusteraddons"}
+	// managedClusterConditions are the conditions of a ManagedCluster registered and reachable by the hub.
+	managedClusterConditions = []string{
+		"HubAcceptedManagedCluster", "ManagedClusterJoined", "ManagedClusterConditionAvailable"}
+)
+
+// Options are the parameters of the registration checks.
+type Options struct {
+	// MaxLeaseAge is the max time since the last renewal of the lease of the cluster.
+	MaxLeaseAge time.Duration
+	// ComplianceSince is the time after which the policies must report their compliance, e.g. the end of an
+	// upgrade. Policy compliance is not checked when zero.
+	ComplianceSince time.Time
+}
+
+// Check is the result of a registration check.
+type Check struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+// namedCheck is a registration check run by RunChecks.
+type namedCheck struct {
+	name  string
+	check func() error
+}
+
+// Report holds the results of the registration checks of a managed cluster.
+type Report struct {
+	Cluster string  `json:"cluster"`
+	Checks  []Check `json:"checks"`
+}
+
+// FindManagedCluster returns the name of the ManagedCluster of the hub whose cluster ID claim matches the ID of the
+// OpenShift cluster.
+func FindManagedCluster(hubAPIClient *clients.Settings, clusterID string) (string, error) {
+	managedClusters, err := hubAPIClient.Resource(managedClusterGVR).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	for _, managedCluster := range managedClusters.Items {
+		claims, _, _ := unstructured.NestedSlice(managedCluster.Object, "status", "clusterClaims")
+
+		for _, claim := range claims {
+			claimMap, ok := claim.(map[string]interface{})
+			if ok && claimMap["name"] == clusterIDClaim && claimMap["value"] == clusterID {
+				return managedCluster.GetName(), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no managedcluster found with cluster ID %s", clusterID)
+}
+
+// CheckManagedCluster checks that the ManagedCluster is accepted, joined and available.
+func CheckManagedCluster(hubAPIClient *clients.Settings, clusterName string) error {
+	managedCluster, err := hubAPIClient.Resource(managedClusterGVR).Get(context.TODO(), clusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	conditions, err := getConditions(managedCluster)
+	if err != nil {
+		return err
+	}
+
+	return checkConditions(conditions, managedClusterConditions)
+}
+
+// CheckAddons checks that the klusterlet addons of the cluster are available and not degraded.
+func CheckAddons(hubAPIClient *clients.Settings, clusterName string) error {
+	addons, err := hubAPIClient.Resource(managedClusterAddOnGVR).Namespace(clusterName).List(
+		context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	if len(addons.Items) == 0 {
+		return errors.New("no managedclusteraddon found")
+	}
+
+	var issues []string
+
+	for _, addon := range addons.Items {
+		conditions, err := getConditions(&addon)
+		if err != nil {
+			return err
+		}
+
+		if err := checkConditions(conditions, []string{"Available"}); err != nil {
+			issues = append(issues, fmt.Sprintf("addon %s: %s", addon.GetName(), err))
+
+			continue
+		}
+
+		if degraded := findCondition(conditions, "Degraded"); degraded != nil && degraded.Status == metav1.ConditionTrue {
+			issues = append(issues, fmt.Sprintf("addon %s: Degraded %s: %s", addon.GetName(), degraded.Reason,
+				degraded.Message))
+		}
+	}
+
+	if len(issues) > 0 {
+		return errors.New(strings.Join(issues, "; "))
+	}
+
+	return nil
+}
+
+// CheckLease checks that the lease of the cluster was renewed within maxAge.
+func CheckLease(hubAPIClient *clients.Settings, clusterName string, maxAge time.Duration) error {
+	lease, err := hubAPIClient.K8sClient.CoordinationV1().Leases(clusterName).Get(
+		context.TODO(), leaseName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if lease.Spec.RenewTime == nil {
+		return fmt.Errorf("lease %s never renewed", leaseName)
+	}
+
+	if age := time.Since(lease.Spec.RenewTime.Time); age > maxAge {
+		return fmt.Errorf("lease %s renewed %s ago, more than %s", leaseName, age.Round(time.Second), maxAge)
+	}
+
+	return nil
+}
+
+// CheckPolicyCompliance checks that every policy replicated to the cluster reported its compliance after the given
+// time.
+func CheckPolicyCompliance(hubAPIClient *clients.Settings, clusterName string, since time.Time) error {
+	policyList := &policiesv1.PolicyList{}
+
+	err := hubAPIClient.List(context.TODO(), policyList, runtimeclient.InNamespace(clusterName))
+	if err != nil {
+		return err
+	}
+
+	var issues []string
+
+	for _, policy := range policyList.Items {
+		if policy.Status.ComplianceState == "" {
+			issues = append(issues, fmt.Sprintf("policy %s: no compliance reported", policy.Name))
+
+			continue
+		}
+
+		if last := lastComplianceReport(&policy); last.Before(since) {
+			issues = append(issues, fmt.Sprintf("policy %s: %s last reported at %s", policy.Name,
+				policy.Status.ComplianceState, last.Format(time.RFC3339)))
+		}
+	}
+
+	if len(issues) > 0 {
+		return errors.New(strings.Join(issues, "; "))
+	}
+
+	return nil
+}
+
+// WaitForRegistration waits until all the registration checks of the cluster pass and returns the report of the
+// last run of the checks. API errors are reported as check failures and tolerated while waiting.
+func WaitForRegistration(hubAPIClient *clients.Settings, clusterName string, options Options,
+	timeout time.Duration) (*Report, error) {
+	var report *Report
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			report = RunChecks(hubAPIClient, clusterName, options)
+
+			return len(report.Failures()) == 0, nil
+		})
+	if err != nil {
+		return report, fmt.Errorf("managedcluster %s not registered within %s: %w", clusterName, timeout, err)
+	}
+
+	return report, nil
+}
+
+// RunChecks runs the registration checks of the cluster once.
+func RunChecks(hubAPIClient *clients.Settings, clusterName string, options Options) *Report {
+	checks := []namedCheck{
+		{"managedcluster", func() error { return CheckManagedCluster(hubAPIClient, clusterName) }},
+		{"addons", func() error { return CheckAddons(hubAPIClient, clusterName) }},
+		{"lease", func() error { return CheckLease(hubAPIClient, clusterName, options.MaxLeaseAge) }},
+	}
+
+	if !options.ComplianceSince.IsZero() {
+		checks = append(checks, namedCheck{"compliance", func() error {
+			return CheckPolicyCompliance(hubAPIClient, clusterName, options.ComplianceSince)
+		}})
+	}
+
+	report := &Report{Cluster: clusterName}
+
+	for _, check := range checks {
+		result := Check{Name: check.name}
+
+		if err := check.check(); err != nil {
+			glog.V(90).Infof("Check %s of managedcluster %s failed: %s", check.name, clusterName, err)
+
+			result.Error = err.Error()
+		}
+
+		report.Checks = append(report.Checks, result)
+	}
+
+	return report
+}
+
+// Failures returns the failed checks, one message per check.
+func (report *Report) Failures() []string {
+	var failures []string
+
+	for _, check := range report.Checks {
+		if check.Error != "" {
+			failures = append(failures, fmt.Sprintf("%s: %s", check.Name, check.Error))
+		}
+	}
+
+	return failures
+}
+
+// String returns the result of every check, one per line.
+func (report *Report) String() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "Registration of managedcluster %s:\n", report.Cluster)
+
+	for _, check := range report.Checks {
+		if check.Error == "" {
+			fmt.Fprintf(&builder, "PASS %s\n", check.Name)
+
+			continue
+		}
+
+		fmt.Fprintf(&builder, "FAIL %s: %s\n", check.Name, check.Error)
+	}
+
+	return builder.String()
+}
+
+func getConditions(object *unstructured.Unstructured) ([]metav1.Condition, error) {
+	rawConditions, _, err := unstructured.NestedSlice(object.Object, "status", "conditions")
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []metav1.Condition
+
+	for _, rawCondition := range rawConditions {
+		conditionMap, ok := rawCondition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		var condition metav1.Condition
+
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(conditionMap, &condition)
+		if err != nil {
+			return nil, err
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	return conditions, nil
+}
+
+// checkConditions returns the conditions missing or not True, with their reason and message.
+func checkConditions(conditions []metav1.Condition, expected []string) error {
+	var issues []string
+
+	for _, conditionType := range expected {
+		condition := findCondition(conditions, conditionType)
+
+		switch {
+		case condition == nil:
+			issues = append(issues, fmt.Sprintf("%s missing", conditionType))
+		case condition.Status != metav1.ConditionTrue:
+			issues = append(issues, fmt.Sprintf("%s=%s %s: %s", conditionType, condition.Status, condition.Reason,
+				condition.Message))
+		}
+	}
+
+	if len(issues) > 0 {
+		return errors.New(strings.Join(issues, "; "))
+	}
+
+	return nil
+}
+
+func findCondition(conditions []metav1.Condition, conditionType string) *metav1.Condition {
+	for index := range conditions {
+		if conditions[index].Type == conditionType {
+			return &conditions[index]
+		}
+	}
+
+	return nil
+}
+
+// lastComplianceReport returns the time of the most recent compliance event of the templates of the policy.
+func lastComplianceReport(policy *policiesv1.Policy) time.Time {
+	var last time.Time
+
+	for _, details := range policy.Status.Details {
+		if details == nil {
+			continue
+		}
+
+		for _, history := range details.History {
+			if history.LastTimestamp.Time.After(last) {
+				last = history.LastTimestamp.Time
+			}
+		}
+	}
+
+	return last
+}
@@ -0,0 +1,61 @@
+package bmc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// PowerState is the chassis power state reported by a BMC.
+type PowerState string
+
+const (
+	// PowerOn is reported when the host is powered on.
+	PowerOn PowerState = "on"
+	// PowerOff is reported when the host is powered off.
+	PowerOff PowerState = "off"
+)
+
+const pollInterval = 10 * time.Second
+
+// Backend controls the power of a host through its BMC. The BMC is reached from the test runner since the
+// cluster cannot run commands while its single node is powered off.
+type Backend interface {
+	// Host returns the address of the BMC.
+	Host() string
+	// PowerOn powers the host on.
+	PowerOn() error
+	// PowerOff powers the host off immediately, without a graceful shutdown of the OS.
+	PowerOff() error
+	// PowerStatus returns the current power state of the host.
+	PowerStatus() (PowerState, error)
+}
+
+// WaitForPowerState waits until the BMC reports the given power state. BMC errors are tolerated while waiting
+// since the BMC may be busy while the host powers off or on.
+func WaitForPowerState(backend Backend, state PowerState, timeout time.Duration) error {
+	var lastState PowerState
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			var err error
+
+			lastState, err = backend.PowerStatus()
+			if err != nil {
+				glog.V(90).Infof("Failed to get power status from BMC %s: %s", backend.Host(), err)
+
+				return false, nil
+			}
+
+			return lastState == state, nil
+		})
+	if err != nil {
+		return fmt.Errorf("BMC %s did not report power %s within %s, last state %q: %w", backend.Host(), state,
+			timeout, lastState, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package bmc
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/config"
+)
+
+// IPMI is the BMC backend running ipmitool over the lanplus interface from the test runner.
+type IPMI struct {
+	host     string
+	user     string
+	password string
+}
+
+// NewIPMI returns the IPMI backend of a BMC.
+func NewIPMI(host, user, password string) *IPMI {
+	return &IPMI{host: host, user: user, password: password}
+}
+
+// NewIPMIFromConfig returns the IPMI backend of the first BMC host of the configuration.
+func NewIPMIFromConfig(conf *config.GeneralConfig) (*IPMI, error) {
+	host, _, _ := strings.Cut(conf.BmcHosts, ",")
+	if host == "" {
+		return nil, fmt.Errorf("no BMC host configured, please set the BMC_HOSTS environment variable")
+	}
+
+	return NewIPMI(strings.TrimSpace(host), conf.BmcUser, conf.BmcPassword), nil
+}
+
+// Host returns the address of the BMC.
+func (ipmi *IPMI) Host() string {
+	return ipmi.host
+}
+
+// PowerOn powers the host on.
+func (ipmi *IPMI) PowerOn() error {
+	_, err := ipmi.run("chassis", "power", "on")
+
+	return err
+}
+
+// PowerOff powers the host off immediately.
+func (ipmi *IPMI) PowerOff() error {
+	_, err := ipmi.run("chassis", "power", "off")
+
+	return err
+}
+
+// PowerStatus returns the current power state of the host, parsed from e.g. "Chassis Power is on".
+func (ipmi *IPMI) PowerStatus() (PowerState, error) {
+	output, err := ipmi.run("chassis", "power", "status")
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(output)
+	if len(fields) == 0 || !strings.Contains(output, "Chassis Power is") {
+		return "", fmt.Errorf("unexpected ipmitool power status output %q", output)
+	}
+
+	return PowerState(fields[len(fields)-1]), nil
+}
+
+// run executes ipmitool against the BMC. The password is passed through the environment so that it does not
+// show in the process list.
+func (ipmi *IPMI) run(args ...string) (string, error) {
+	glog.V(90).Infof("Running ipmitool %v against BMC %s", args, ipmi.host)
+
+	cmdToExec := exec.Command("ipmitool", append([]string{"-I", "lanplus", "-H", ipmi.host, "-U", ipmi.user, "-E"},
+		args...)...)
+	cmdToExec.Env = append(os.Environ(), "IPMI_PASSWORD="+ipmi.password)
+
+	output, err := cmdToExec.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("ipmitool %v failed against BMC %s: %w\n%s", args, ipmi.host, err, output)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
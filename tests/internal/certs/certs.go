@@ -0,0 +1,83 @@
+package certs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// notAfterAnnotation is set by the OpenShift certificate controllers on the secrets holding the certificates they
+// manage, e.g. the kube-apiserver-to-kubelet-signer.
+const notAfterAnnotation = "auth.openshift.io/certificate-not-after"
+
+// Certificate is a certificate of the cluster or of a node and its validity.
+type Certificate struct {
+	Source    string    `json:"source"`
+	Subject   string    `json:"subject,omitempty"`
+	Serial    string    `json:"serial,omitempty"`
+	NotBefore time.Time `json:"notBefore,omitempty"`
+	NotAfter  time.Time `json:"notAfter"`
+}
+
+// ListManagedCertificates returns the certificates of the secrets annotated by the OpenShift certificate
+// controllers, sorted by expiry.
+func ListManagedCertificates(apiClient *clients.Settings) ([]Certificate, error) {
+	secretList, err := apiClient.Secrets("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var certificates []Certificate
+
+	for _, secret := range secretList.Items {
+		notAfter, found := secret.Annotations[notAfterAnnotation]
+		if !found {
+			continue
+		}
+
+		expiry, err := time.Parse(time.RFC3339, notAfter)
+		if err != nil {
+			glog.V(90).Infof("Invalid %s annotation on secret %s/%s: %s", notAfterAnnotation, secret.Namespace,
+				secret.Name, err)
+
+			continue
+		}
+
+		certificates = append(certificates, Certificate{
+			Source:   fmt.Sprintf("secret %s/%s", secret.Namespace, secret.Name),
+			NotAfter: expiry,
+		})
+	}
+
+	SortByExpiry(certificates)
+
+	return certificates, nil
+}
+
+// CheckExpiry returns the certificates which are expired or expire within minValidity.
+func CheckExpiry(certificates []Certificate, minValidity time.Duration) []string {
+	var issues []string
+
+	deadline := time.Now().Add(minValidity)
+
+	for _, certificate := range certificates {
+		if certificate.NotAfter.Before(deadline) {
+			issues = append(issues, fmt.Sprintf("%s expires at %s", certificate.Source,
+				certificate.NotAfter.Format(time.RFC3339)))
+		}
+	}
+
+	return issues
+}
+
+// SortByExpiry sorts the certificates by expiry, the first to expire first.
+func SortByExpiry(certificates []Certificate) {
+	sort.SliceStable(certificates, func(i, j int) bool {
+		return certificates[i].NotAfter.Before(certificates[j].NotAfter)
+	})
+}
@@ -0,0 +1,276 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/olm"
+	"github.com/openshift-kni/eco-goinfra/pkg/sriov"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/bmc"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Topology is the layout of the nodes of a cluster.
+type Topology string
+
+const (
+	// TopologySNO is a single-node cluster.
+	TopologySNO Topology = "SNO"
+	// TopologyCompact is a multi-node cluster whose control plane nodes are also the workers.
+	TopologyCompact Topology = "compact"
+	// TopologyStandard is a cluster with dedicated worker nodes.
+	TopologyStandard Topology = "standard"
+)
+
+const (
+	nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+	sriovNamespace      = "openshift-sriov-network-operator"
+)
+
+// NodeResources are the allocatable resources of a node.
+type NodeResources struct {
+	// CPUs is the number of allocatable CPUs.
+	CPUs int64
+	// Hugepages is the allocatable hugepages memory in bytes by page size, e.g. 1Gi.
+	Hugepages map[string]int64
+}
+
+// Capabilities describe what a cluster offers to the tests.
+type Capabilities struct {
+	Topology Topology
+	// NodeRoles are the roles of the nodes by node name, e.g. master and worker.
+	NodeRoles map[string][]string
+	// Resources are the allocatable resources by node name.
+	Resources map[string]NodeResources
+	// Operators are the versions of the installed operators by CSV name without version, e.g. sriov-network-operator.
+	Operators map[string]string
+	// SRIOVDeviceTypes are the device types of the SR-IOV policies, e.g. netdevice or vfio-pci.
+	SRIOVDeviceTypes []string
+	// RTKernel is true when every node runs the realtime kernel.
+	RTKernel bool
+	// BMC is true when the BMC of the configuration answers to a power status request.
+	BMC bool
+
+	bmcChecked bool
+}
+
+var (
+	capabilitiesMutex sync.Mutex
+	capabilitiesCache = make(map[*clients.Settings]*Capabilities)
+)
+
+// GetCapabilities returns the capabilities of the cluster, discovered on the first call for the client and cached
+// for the rest of the suite. The BMC is checked on the first call with a configuration.
+func GetCapabilities(apiClient *clients.Settings, conf *config.GeneralConfig) (*Capabilities, error) {
+	capabilitiesMutex.Lock()
+	defer capabilitiesMutex.Unlock()
+
+	if capabilities, found := capabilitiesCache[apiClient]; found {
+		if conf != nil && !capabilities.bmcChecked {
+			capabilities.BMC, capabilities.bmcChecked = discoverBMC(conf), true
+		}
+
+		return capabilities, nil
+	}
+
+	capabilities, err := Discover(apiClient, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilitiesCache[apiClient] = capabilities
+
+	return capabilities, nil
+}
+
+// Discover inspects the nodes, the operators and the SR-IOV policies of the cluster and the BMC of the
+// configuration. The BMC is considered unavailable when the configuration is nil.
+func Discover(apiClient *clients.Settings, conf *config.GeneralConfig) (*Capabilities, error) {
+	if apiClient == nil {
+		return nil, fmt.Errorf("provided client was not defined")
+	}
+
+	capabilities := &Capabilities{
+		NodeRoles: make(map[string][]string),
+		Resources: make(map[string]NodeResources),
+	}
+
+	nodeList, err := apiClient.CoreV1Interface.Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	capabilities.discoverNodes(nodeList.Items)
+
+	capabilities.Operators, err = discoverOperators(apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities.SRIOVDeviceTypes = discoverSRIOVDeviceTypes(apiClient)
+	capabilities.BMC, capabilities.bmcChecked = discoverBMC(conf), conf != nil
+
+	glog.V(90).Infof("Discovered cluster capabilities: %s", capabilities)
+
+	return capabilities, nil
+}
+
+// String returns a summary of the capabilities.
+func (capabilities *Capabilities) String() string {
+	return fmt.Sprintf("topology %s, %d nodes, %d operators, SR-IOV device types %v, RT kernel %t, BMC %t",
+		capabilities.Topology, len(capabilities.NodeRoles), len(capabilities.Operators),
+		capabilities.SRIOVDeviceTypes, capabilities.RTKernel, capabilities.BMC)
+}
+
+func (capabilities *Capabilities) discoverNodes(nodes []corev1.Node) {
+	controlPlanes, workers, dedicatedWorkers := 0, 0, 0
+	capabilities.RTKernel = len(nodes) > 0
+
+	for _, node := range nodes {
+		var roles []string
+
+		for label := range node.Labels {
+			if role, found := strings.CutPrefix(label, nodeRoleLabelPrefix); found {
+				roles = append(roles, role)
+			}
+		}
+
+		sort.Strings(roles)
+		capabilities.NodeRoles[node.Name] = roles
+
+		controlPlane := contains(roles, "master") || contains(roles, "control-plane")
+		worker := contains(roles, "worker")
+
+		switch {
+		case controlPlane && worker:
+			controlPlanes++
+			workers++
+		case controlPlane:
+			controlPlanes++
+		case worker:
+			workers++
+			dedicatedWorkers++
+		}
+
+		resources := NodeResources{
+			CPUs:      node.Status.Allocatable.Cpu().Value(),
+			Hugepages: make(map[string]int64),
+		}
+
+		for name, quantity := range node.Status.Allocatable {
+			if size, found := strings.CutPrefix(string(name), corev1.ResourceHugePagesPrefix); found {
+				resources.Hugepages[size] = quantity.Value()
+			}
+		}
+
+		capabilities.Resources[node.Name] = resources
+		capabilities.RTKernel = capabilities.RTKernel && strings.Contains(node.Status.NodeInfo.KernelVersion, ".rt")
+	}
+
+	switch {
+	case len(nodes) == 1:
+		capabilities.Topology = TopologySNO
+	case dedicatedWorkers == 0 && workers > 0:
+		capabilities.Topology = TopologyCompact
+	default:
+		capabilities.Topology = TopologyStandard
+	}
+
+	glog.V(90).Infof("Found %d control plane nodes and %d workers, %d of them dedicated",
+		controlPlanes, workers, dedicatedWorkers)
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// discoverOperators returns the versions of the operators by CSV name without version, skipping the CSVs copied
+// by OLM to the watched namespaces.
+func discoverOperators(apiClient *clients.Settings) (map[string]string, error) {
+	csvs, err := olm.ListClusterServiceVersionInAllNamespaces(apiClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CSVs: %w", err)
+	}
+
+	operators := make(map[string]string)
+
+	for _, csv := range csvs {
+		if csv.Object.IsCopied() {
+			continue
+		}
+
+		operators[CSVBaseName(csv.Object.Name)] = csv.Object.Spec.Version.String()
+	}
+
+	return operators, nil
+}
+
+// CSVBaseName returns the name of the CSV without its version suffix, e.g. sriov-network-operator for
+// sriov-network-operator.v4.14.0-202311021650.
+func CSVBaseName(name string) string {
+	baseName, _, _ := strings.Cut(name, ".v")
+
+	return baseName
+}
+
+// discoverSRIOVDeviceTypes returns the device types of the SR-IOV policies. The SR-IOV operator being optional,
+// a failure to list the policies means that no SR-IOV device is available.
+func discoverSRIOVDeviceTypes(apiClient *clients.Settings) []string {
+	policies, err := sriov.ListPolicy(apiClient, sriovNamespace)
+	if err != nil {
+		glog.V(90).Infof("No SR-IOV policy found: %v", err)
+
+		return nil
+	}
+
+	var deviceTypes []string
+
+	for _, policy := range policies {
+		deviceType := policy.Object.Spec.DeviceType
+		if deviceType == "" {
+			deviceType = "netdevice"
+		}
+
+		if !contains(deviceTypes, deviceType) {
+			deviceTypes = append(deviceTypes, deviceType)
+		}
+	}
+
+	sort.Strings(deviceTypes)
+
+	return deviceTypes
+}
+
+func discoverBMC(conf *config.GeneralConfig) bool {
+	if conf == nil {
+		return false
+	}
+
+	ipmi, err := bmc.NewIPMIFromConfig(conf)
+	if err != nil {
+		glog.V(90).Infof("No BMC available: %v", err)
+
+		return false
+	}
+
+	if _, err := ipmi.PowerStatus(); err != nil {
+		glog.V(90).Infof("BMC %s not reachable: %v", ipmi.Host(), err)
+
+		return false
+	}
+
+	return true
+}
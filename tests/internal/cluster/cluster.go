@@ -3,6 +3,7 @@ package cluster
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
 
@@ -24,44 +25,63 @@ func CheckClustersPresent(clients []*clients.Settings) error {
 	return nil
 }
 
-// GetClusterName extracts the cluster name from provided kubeconfig. It assumes the there's exactly 1 cluster.
+// GetClusterName extracts the cluster name from the API server URL of the current context of the provided
+// kubeconfig, e.g. local-cluster for https://api.local-cluster.karmalabs.local:6443. The name field of the
+// kubeconfig cluster is not always the cluster name, hence the server URL is used instead.
 func GetClusterName(kubeconfigEnvVar string) (string, error) {
 	kubeFilePath, present := os.LookupEnv(kubeconfigEnvVar)
 	if !present {
 		return "", fmt.Errorf("can not load api client. Please check '%s' env var", kubeconfigEnvVar)
 	}
 
-	rawConfig, _ := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+	rawConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
 		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeFilePath},
 		&clientcmd.ConfigOverrides{
 			CurrentContext: "",
 		}).RawConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load kubeconfig %s: %w", kubeFilePath, err)
+	}
+
+	clusterKey := ""
+
+	if kubeContext, found := rawConfig.Contexts[rawConfig.CurrentContext]; found {
+		clusterKey = kubeContext.Cluster
+	} else if len(rawConfig.Clusters) == 1 {
+		for key := range rawConfig.Clusters {
+			clusterKey = key
+		}
+	}
+
+	kubeCluster, found := rawConfig.Clusters[clusterKey]
+	if !found {
+		return "", fmt.Errorf("no cluster found for the current context of kubeconfig %s", kubeFilePath)
+	}
+
+	clusterName, err := clusterNameFromServer(kubeCluster.Server)
+	if err != nil {
+		return "", err
+	}
+
+	glog.V(100).Infof("cluster name: %s", clusterName)
+
+	return clusterName, nil
+}
+
+// clusterNameFromServer returns the cluster name of an API server URL, the label following api or api-int.
+func clusterNameFromServer(server string) (string, error) {
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return "", fmt.Errorf("invalid API server URL %q: %w", server, err)
+	}
 
-	for _, cluster := range rawConfig.Clusters {
-		// The cluster data looks like this:
-		/*
-			    "clusters": [
-					{
-						"name": "local-cluster",
-						"cluster": {
-							"server": "https://api.local-cluster.karmalabs.local:6443",
-							"certificate-authority-data": "DATA+OMITTED"
-						}
-					}
-				],
-		*/
-		// However the "name" field is not always correct so it is more consistent to instead
-		// parse the name out from the server url
-		splits := strings.Split(cluster.Server, ".")
-
-		clusterName := splits[1]
-
-		glog.V(100).Infof("cluster name: ", clusterName)
-
-		return clusterName, nil
+	labels := strings.Split(serverURL.Hostname(), ".")
+	if len(labels) < 3 || (labels[0] != "api" && labels[0] != "api-int") || labels[1] == "" {
+		return "", fmt.Errorf("can not find the cluster name in API server URL %q, expected https://api.<name>.<domain>",
+			server)
 	}
 
-	return "", fmt.Errorf("can not load api client. Please check '%s' env var", kubeconfigEnvVar)
+	return labels[1], nil
 }
 
 // GetClusterVersion can be used to get the Openshift version from the provided cluster.
@@ -76,9 +96,8 @@ func GetClusterVersion(apiClient *clients.Settings) (string, error) {
 		return "", err
 	}
 
-	histories := clusterVersion.Object.Status.History
-	for i := len(histories) - 1; i >= 0; i-- {
-		history := histories[i]
+	// The history is ordered from the most recent update, so the first completed entry is the current version.
+	for _, history := range clusterVersion.Object.Status.History {
 		if history.State == "Completed" {
 			return history.Version, nil
 		}
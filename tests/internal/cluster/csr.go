@@ -0,0 +1,226 @@
+package cluster
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// ManualApprovalReason is the reason of the approval condition of the CSRs approved by the system tests.
+	ManualApprovalReason = "EcoSystemTestsApprove"
+	csrPollInterval      = 15 * time.Second
+	nodeUserPrefix       = "system:node:"
+	nodeBootstrapperUser = "system:serviceaccount:openshift-machine-config-operator:node-bootstrapper"
+)
+
+// kubeletSigners are the signers of the kubelet client and serving certificates.
+var kubeletSigners = []string{
+	certificatesv1.KubeAPIServerClientKubeletSignerName,
+	certificatesv1.KubeletServingSignerName,
+}
+
+// CSR is the status of a CertificateSigningRequest.
+type CSR struct {
+	Name     string    `json:"name"`
+	Signer   string    `json:"signer"`
+	Username string    `json:"username"`
+	Created  time.Time `json:"created"`
+	// Status is the type of the last condition, Approved, Denied or Failed, or Pending without condition.
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ListCSRs returns the CertificateSigningRequests created after the given time.
+func ListCSRs(apiClient *clients.Settings, since time.Time) ([]CSR, error) {
+	csrList, err := apiClient.K8sClient.CertificatesV1().CertificateSigningRequests().List(
+		context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var csrs []CSR
+
+	for _, csr := range csrList.Items {
+		if csr.CreationTimestamp.Time.Before(since.Truncate(time.Second)) {
+			continue
+		}
+
+		status := CSR{
+			Name:     csr.Name,
+			Signer:   csr.Spec.SignerName,
+			Username: csr.Spec.Username,
+			Created:  csr.CreationTimestamp.Time,
+			Status:   "Pending",
+		}
+
+		if count := len(csr.Status.Conditions); count > 0 {
+			status.Status = string(csr.Status.Conditions[count-1].Type)
+			status.Reason = csr.Status.Conditions[count-1].Reason
+		}
+
+		csrs = append(csrs, status)
+	}
+
+	return csrs, nil
+}
+
+// ListPendingCSRs returns the names of the pending kubelet CertificateSigningRequests of the nodes, see IsNodeCSR,
+// or of all the pending CertificateSigningRequests when no node is given. A CSR is pending when it is neither
+// approved, denied nor failed.
+func ListPendingCSRs(apiClient *clients.Settings, nodeNames ...string) ([]string, error) {
+	csrList, err := apiClient.K8sClient.CertificatesV1().CertificateSigningRequests().List(
+		context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []string
+
+	for index := range csrList.Items {
+		csr := &csrList.Items[index]
+
+		if len(csr.Status.Conditions) == 0 && (len(nodeNames) == 0 || IsNodeCSR(csr, nodeNames...)) {
+			pending = append(pending, csr.Name)
+		}
+	}
+
+	return pending, nil
+}
+
+// IsNodeCSR returns true when the CertificateSigningRequest is a kubelet client or serving certificate request of
+// one of the nodes, requested by the node itself or by the node bootstrapper for the node.
+func IsNodeCSR(csr *certificatesv1.CertificateSigningRequest, nodeNames ...string) bool {
+	if !contains(kubeletSigners, csr.Spec.SignerName) {
+		return false
+	}
+
+	for _, nodeName := range nodeNames {
+		nodeUser := nodeUserPrefix + nodeName
+
+		if csr.Spec.Username != nodeUser && csr.Spec.Username != nodeBootstrapperUser {
+			continue
+		}
+
+		if commonName, err := csrCommonName(csr.Spec.Request); err == nil && commonName == nodeUser {
+			return true
+		}
+	}
+
+	return false
+}
+
+func csrCommonName(request []byte) (string, error) {
+	block, _ := pem.Decode(request)
+	if block == nil {
+		return "", fmt.Errorf("no PEM block found in the certificate request")
+	}
+
+	certificateRequest, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return "", err
+	}
+
+	return certificateRequest.Subject.CommonName, nil
+}
+
+// ApproveCSR approves a CertificateSigningRequest, as done with oc adm certificate approve.
+func ApproveCSR(apiClient *clients.Settings, name string) error {
+	csr, err := apiClient.K8sClient.CertificatesV1().CertificateSigningRequests().Get(
+		context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Type:           certificatesv1.CertificateApproved,
+		Status:         corev1.ConditionTrue,
+		Reason:         ManualApprovalReason,
+		Message:        "Approved by the system tests",
+		LastUpdateTime: metav1.Now(),
+	})
+
+	glog.V(90).Infof("Approving CSR %s of %s", name, csr.Spec.Username)
+
+	_, err = apiClient.K8sClient.CertificatesV1().CertificateSigningRequests().UpdateApproval(
+		context.TODO(), name, csr, metav1.UpdateOptions{})
+
+	return err
+}
+
+// ApprovePendingCSRs approves the pending kubelet CertificateSigningRequests of the nodes, or all the pending
+// CertificateSigningRequests when no node is given, and returns the names of the approved ones. The other pending
+// requests are left to the cluster.
+func ApprovePendingCSRs(apiClient *clients.Settings, nodeNames ...string) ([]string, error) {
+	pending, err := ListPendingCSRs(apiClient, nodeNames...)
+	if err != nil {
+		return nil, err
+	}
+
+	return approveCSRs(apiClient, pending), nil
+}
+
+// WaitForNoPendingCSRs waits until no kubelet CertificateSigningRequest of the nodes, or no CertificateSigningRequest
+// at all when no node is given, is pending. When approve is true these requests are approved, as documented after a
+// cluster restart, otherwise they are expected to be handled by the cluster machine approver. The names of the
+// approved requests are returned. API errors are tolerated while waiting.
+func WaitForNoPendingCSRs(apiClient *clients.Settings, approve bool, timeout time.Duration,
+	nodeNames ...string) ([]string, error) {
+	var (
+		approved []string
+		pending  []string
+	)
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), csrPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			var err error
+
+			pending, err = ListPendingCSRs(apiClient, nodeNames...)
+			if err != nil {
+				glog.V(90).Infof("Failed to list CSRs: %s", err)
+
+				return false, nil
+			}
+
+			if len(pending) == 0 {
+				return true, nil
+			}
+
+			if approve {
+				approved = append(approved, approveCSRs(apiClient, pending)...)
+			}
+
+			return false, nil
+		})
+	if err != nil {
+		return approved, fmt.Errorf("CSRs %v still pending after %s: %w", pending, timeout, err)
+	}
+
+	return approved, nil
+}
+
+func approveCSRs(apiClient *clients.Settings, names []string) []string {
+	var approved []string
+
+	for _, name := range names {
+		err := ApproveCSR(apiClient, name)
+		if err != nil {
+			glog.V(90).Infof("Failed to approve CSR %s: %s", name, err)
+
+			continue
+		}
+
+		approved = append(approved, name)
+	}
+
+	return approved
+}
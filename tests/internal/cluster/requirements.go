@@ -0,0 +1,156 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/config"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/version"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Requirement is a capability a spec requires from the cluster.
+type Requirement struct {
+	// Description tells what is required, e.g. topology SNO.
+	Description string
+	satisfied   func(capabilities *Capabilities) bool
+}
+
+// RequiresTopology requires one of the given topologies.
+func RequiresTopology(topologies ...Topology) Requirement {
+	names := make([]string, 0, len(topologies))
+
+	for _, topology := range topologies {
+		names = append(names, string(topology))
+	}
+
+	return Requirement{
+		Description: "topology " + strings.Join(names, " or "),
+		satisfied: func(capabilities *Capabilities) bool {
+			for _, topology := range topologies {
+				if capabilities.Topology == topology {
+					return true
+				}
+			}
+
+			return false
+		},
+	}
+}
+
+// RequiresOperator requires an installed operator, named after its CSV without version, whose version satisfies
+// the constraint expression, e.g. RequiresOperator("lifecycle-agent", ">=4.14"). An empty expression accepts any
+// version. It panics when the expression is invalid.
+func RequiresOperator(name, expression string) Requirement {
+	var constraint *version.Constraint
+
+	description := "operator " + name
+
+	if expression != "" {
+		description += " " + expression
+		constraint = version.MustParseConstraint(expression)
+	}
+
+	return Requirement{
+		Description: description,
+		satisfied: func(capabilities *Capabilities) bool {
+			operatorVersion, found := capabilities.Operators[name]
+
+			return found && (constraint == nil || constraint.Check(operatorVersion))
+		},
+	}
+}
+
+// RequiresSRIOV requires an SR-IOV policy of the device type, e.g. netdevice or vfio-pci.
+func RequiresSRIOV(deviceType string) Requirement {
+	return Requirement{
+		Description: "SR-IOV " + deviceType + " devices",
+		satisfied: func(capabilities *Capabilities) bool {
+			return contains(capabilities.SRIOVDeviceTypes, deviceType)
+		},
+	}
+}
+
+// RequiresRTKernel requires the realtime kernel on every node.
+func RequiresRTKernel() Requirement {
+	return Requirement{
+		Description: "realtime kernel",
+		satisfied: func(capabilities *Capabilities) bool {
+			return capabilities.RTKernel
+		},
+	}
+}
+
+// RequiresBMC requires a reachable BMC in the configuration.
+func RequiresBMC() Requirement {
+	return Requirement{
+		Description: "BMC",
+		satisfied: func(capabilities *Capabilities) bool {
+			return capabilities.BMC
+		},
+	}
+}
+
+// RequiresCPUs requires a node with at least the given number of allocatable CPUs.
+func RequiresCPUs(cpus int64) Requirement {
+	return Requirement{
+		Description: fmt.Sprintf("node with %d CPUs", cpus),
+		satisfied: func(capabilities *Capabilities) bool {
+			for _, resources := range capabilities.Resources {
+				if resources.CPUs >= cpus {
+					return true
+				}
+			}
+
+			return false
+		},
+	}
+}
+
+// RequiresHugepages requires a node with at least the given allocatable hugepages memory of the page size,
+// e.g. RequiresHugepages("1Gi", "4Gi"). It panics when the quantity is invalid.
+func RequiresHugepages(pageSize, quantity string) Requirement {
+	minimum := resource.MustParse(quantity)
+	bytes := minimum.Value()
+
+	return Requirement{
+		Description: fmt.Sprintf("node with %s of %s hugepages", quantity, pageSize),
+		satisfied: func(capabilities *Capabilities) bool {
+			for _, resources := range capabilities.Resources {
+				if resources.Hugepages[pageSize] >= bytes {
+					return true
+				}
+			}
+
+			return false
+		},
+	}
+}
+
+// Unsatisfied returns the descriptions of the requirements the capabilities do not satisfy.
+func (capabilities *Capabilities) Unsatisfied(requirements ...Requirement) []string {
+	var unsatisfied []string
+
+	for _, requirement := range requirements {
+		if !requirement.satisfied(capabilities) {
+			unsatisfied = append(unsatisfied, requirement.Description)
+		}
+	}
+
+	return unsatisfied
+}
+
+// RequireCapabilities skips the current spec when the cluster does not satisfy the requirements, e.g.
+// RequireCapabilities(APIClient, conf, RequiresTopology(TopologySNO), RequiresBMC()). The capabilities are
+// discovered once per suite, the BMC from the configuration which may be nil when no BMC is required.
+func RequireCapabilities(apiClient *clients.Settings, conf *config.GeneralConfig, requirements ...Requirement) {
+	capabilities, err := GetCapabilities(apiClient, conf)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred(), "Failed to discover the cluster capabilities")
+
+	if unsatisfied := capabilities.Unsatisfied(requirements...); len(unsatisfied) > 0 {
+		ginkgo.Skip(fmt.Sprintf("cluster does not provide %s (%s)", strings.Join(unsatisfied, ", "), capabilities))
+	}
+}
@@ -0,0 +1,143 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/clusterversion"
+	"github.com/openshift-kni/eco-goinfra/pkg/mco"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	configv1 "github.com/openshift/api/config/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	stabilityPollInterval = 15 * time.Second
+	// clusterVersionFailing is the ClusterVersion condition reporting a failing update.
+	clusterVersionFailing configv1.ClusterStatusConditionType = "Failing"
+)
+
+// CheckStability returns the reasons why the cluster is not stable: ClusterVersion progressing or failing,
+// ClusterOperators not available, progressing or degraded, MachineConfigPools not updated or degraded and nodes
+// not ready. An empty list means the cluster is stable.
+func CheckStability(apiClient *clients.Settings) ([]string, error) {
+	var issues []string
+
+	clusterVersion, err := clusterversion.Pull(apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, condition := range clusterVersion.Object.Status.Conditions {
+		if (condition.Type == configv1.OperatorProgressing || condition.Type == clusterVersionFailing) &&
+			condition.Status == configv1.ConditionTrue {
+			issues = append(issues, fmt.Sprintf("ClusterVersion %s: %s", condition.Type, condition.Message))
+		}
+	}
+
+	clusterOperators, err := apiClient.ClusterOperators().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, clusterOperator := range clusterOperators.Items {
+		issues = append(issues, checkOperator(&clusterOperator)...)
+	}
+
+	pools, err := mco.ListMCP(apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pool := range pools {
+		if !pool.IsInCondition(mcov1.MachineConfigPoolUpdated) {
+			issues = append(issues, fmt.Sprintf("MachineConfigPool %s not updated", pool.Object.Name))
+		}
+
+		if pool.IsInCondition(mcov1.MachineConfigPoolDegraded) {
+			issues = append(issues, fmt.Sprintf("MachineConfigPool %s degraded", pool.Object.Name))
+		}
+	}
+
+	nodeList, err := nodes.List(apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, node := range nodeList {
+		ready, err := node.IsReady()
+		if err != nil || !ready {
+			issues = append(issues, fmt.Sprintf("node %s not ready", node.Object.Name))
+		}
+	}
+
+	return issues, nil
+}
+
+// WaitForStability is the cluster stability gate: it waits until the cluster has been stable for stableDuration
+// in a row. The issues seen last are returned on timeout. API errors are tolerated while waiting.
+func WaitForStability(apiClient *clients.Settings, stableDuration, timeout time.Duration) error {
+	var (
+		stableSince time.Time
+		lastIssues  []string
+	)
+
+	glog.V(90).Infof("Waiting for the cluster to be stable for %s", stableDuration)
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), stabilityPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			issues, err := CheckStability(apiClient)
+			if err != nil {
+				glog.V(90).Infof("Failed to check cluster stability: %s", err)
+
+				stableSince = time.Time{}
+
+				return false, nil
+			}
+
+			if len(issues) > 0 {
+				glog.V(90).Infof("Cluster not stable: %v", issues)
+
+				lastIssues = issues
+				stableSince = time.Time{}
+
+				return false, nil
+			}
+
+			if stableSince.IsZero() {
+				stableSince = time.Now()
+			}
+
+			return time.Since(stableSince) >= stableDuration, nil
+		})
+	if err != nil {
+		return fmt.Errorf("cluster not stable for %s within %s, last issues %v: %w", stableDuration, timeout,
+			lastIssues, err)
+	}
+
+	return nil
+}
+
+func checkOperator(clusterOperator *configv1.ClusterOperator) []string {
+	expected := map[configv1.ClusterStatusConditionType]configv1.ConditionStatus{
+		configv1.OperatorAvailable:   configv1.ConditionTrue,
+		configv1.OperatorProgressing: configv1.ConditionFalse,
+		configv1.OperatorDegraded:    configv1.ConditionFalse,
+	}
+
+	var issues []string
+
+	for _, condition := range clusterOperator.Status.Conditions {
+		if status, found := expected[condition.Type]; found && condition.Status != status {
+			issues = append(issues, fmt.Sprintf("ClusterOperator %s %s=%s: %s", clusterOperator.Name, condition.Type,
+				condition.Status, condition.Message))
+		}
+	}
+
+	return issues
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
 	"github.com/openshift-kni/eco-goinfra/pkg/pod"
 	. "github.com/openshift-kni/eco-gosystem/tests/internal/inittools"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,16 +14,25 @@ import (
 
 // ExecCmd executes a command on a node.
 func ExecCmd(cmdToExec []string, nodeName string) (string, error) {
+	return ExecCmdWithClient(APIClient, cmdToExec, nodeName)
+}
+
+// ExecCmdWithClient executes a command on a node of the cluster of the given api client, e.g. a spoke cluster.
+func ExecCmdWithClient(apiClient *clients.Settings, cmdToExec []string, nodeName string) (string, error) {
 	listOptions := metav1.ListOptions{
 		FieldSelector: fields.SelectorFromSet(fields.Set{"spec.nodeName": nodeName}).String(),
 		LabelSelector: labels.SelectorFromSet(labels.Set{"k8s-app": GeneralConfig.MCOConfigDaemonName}).String(),
 	}
 
-	mcPodList, err := pod.List(APIClient, GeneralConfig.MCONamespace, listOptions)
+	mcPodList, err := pod.List(apiClient, GeneralConfig.MCONamespace, listOptions)
 	if err != nil {
 		return "", err
 	}
 
+	if len(mcPodList) == 0 {
+		return "", fmt.Errorf("no %s pod found on node %s", GeneralConfig.MCOConfigDaemonName, nodeName)
+	}
+
 	glog.V(90).Infof("Exec cmd %v on pod %s", cmdToExec, mcPodList[0].Definition.Name)
 	buf, err := mcPodList[0].ExecCommand(cmdToExec)
 
@@ -20,6 +20,7 @@ const (
 // GeneralConfig type keeps general configuration.
 type GeneralConfig struct {
 	ReportsDirAbsPath      string `yaml:"reports_dump_dir" envconfig:"ECO_REPORTS_DUMP_DIR"`
+	RunID                  string `yaml:"run_id" envconfig:"ECO_RUN_ID"`
 	VerboseLevel           string `yaml:"verbose_level" envconfig:"ECO_VERBOSE_LEVEL"`
 	DumpFailedTests        bool   `yaml:"dump_failed_tests" envconfig:"ECO_DUMP_FAILED_TESTS"`
 	PolarionReport         bool   `yaml:"polarion_report" envconfig:"ECO_POLARION_REPORT"`
@@ -38,6 +39,13 @@ type GeneralConfig struct {
 	BmcUser                string `yaml:"bmc_user" envconfig:"BMC_USER"`
 	BmcPassword            string `yaml:"bmc_password" envconfig:"BMC_PASSWORD"`
 	StressngTestImage      string `yaml:"stressng_test_image" envconfig:"STRESSNG_TEST_IMAGE"`
+	LatencyTestImage       string `yaml:"latency_test_image" envconfig:"ECO_SYSTEM_TESTS_LATENCY_IMAGE"`
+	LatencyTestDuration    string `yaml:"latency_test_duration" envconfig:"ECO_SYSTEM_TESTS_LATENCY_DURATION"`
+	LatencyTestCPUs        string `yaml:"latency_test_cpus" envconfig:"ECO_SYSTEM_TESTS_LATENCY_CPUS"`
+	CyclictestMaxLatency   string `yaml:"cyclictest_max_latency" envconfig:"ECO_SYSTEM_TESTS_CYCLICTEST_MAX_LATENCY"`
+	OslatMaxLatency        string `yaml:"oslat_max_latency" envconfig:"ECO_SYSTEM_TESTS_OSLAT_MAX_LATENCY"`
+	HwlatdetectMaxLatency  string `yaml:"hwlatdetect_max_latency" envconfig:"ECO_SYSTEM_TESTS_HWLATDETECT_MAX_LATENCY"`
+	LatencyTolerance       string `yaml:"latency_tolerance" envconfig:"ECO_SYSTEM_TESTS_LATENCY_TOLERANCE"`
 }
 
 // NewConfig returns instance of GeneralConfig config type.
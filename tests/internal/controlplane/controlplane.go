@@ -0,0 +1,233 @@
+package controlplane
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/disruption"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/prober"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// MetricRecoveryTime is the name of the component recovery time metric.
+	MetricRecoveryTime = "ranmetrics_controlplane_recovery_seconds"
+	// MetricAPIUnavailability is the name of the API unavailability metric.
+	MetricAPIUnavailability = "ranmetrics_controlplane_api_unavailable_seconds"
+	// ProbeInterval is the interval between two API probes.
+	ProbeInterval = 500 * time.Millisecond
+	// minReadyTimeout bounds the wait for the component readiness when its container recovery used most of the
+	// timeout.
+	minReadyTimeout = time.Minute
+	pollInterval    = 10 * time.Second
+)
+
+// Component is a control plane component running as a static pod.
+type Component struct {
+	Name      string
+	Namespace string
+	PodLabel  string
+	Container string
+}
+
+var (
+	// Etcd is the etcd static pod.
+	Etcd = Component{
+		Name: "etcd", Namespace: "openshift-etcd", PodLabel: "app=etcd", Container: "etcd",
+	}
+	// KubeAPIServer is the kube-apiserver static pod.
+	KubeAPIServer = Component{
+		Name: "kube-apiserver", Namespace: "openshift-kube-apiserver", PodLabel: "app=openshift-kube-apiserver",
+		Container: "kube-apiserver",
+	}
+	// KubeControllerManager is the kube-controller-manager static pod.
+	KubeControllerManager = Component{
+		Name: "kube-controller-manager", Namespace: "openshift-kube-controller-manager",
+		PodLabel: "app=kube-controller-manager", Container: "kube-controller-manager",
+	}
+	// Components lists the control plane components that can be restarted.
+	Components = []Component{Etcd, KubeAPIServer, KubeControllerManager}
+)
+
+// SLO is the recovery objective of a component. A zero value disables the check.
+type SLO struct {
+	MaxRecoveryTime      time.Duration
+	MaxAPIUnavailability time.Duration
+}
+
+// Result is the outcome of a component restart.
+type Result struct {
+	Component string `json:"component"`
+	Node      string `json:"node"`
+	// RecoveryTime is the time from the restart to the component pod being ready again.
+	RecoveryTime time.Duration `json:"recoveryTime"`
+	// APIUnavailability is the total time the API did not answer during the recovery.
+	APIUnavailability time.Duration   `json:"apiUnavailability"`
+	Outages           []prober.Window `json:"outages"`
+}
+
+// Metrics returns the recovery time and API unavailability metrics of the result named after the given tag.
+func (result *Result) Metrics(tag string) map[string]string {
+	return map[string]string{
+		fmt.Sprintf("%s_%s_%s", MetricRecoveryTime, result.Component, tag): strconv.FormatFloat(
+			result.RecoveryTime.Seconds(), 'f', 0, 64),
+		fmt.Sprintf("%s_%s_%s", MetricAPIUnavailability, result.Component, tag): strconv.FormatFloat(
+			result.APIUnavailability.Seconds(), 'f', 0, 64),
+	}
+}
+
+// CheckSLO returns an error listing the recovery objectives the result does not meet.
+func (result *Result) CheckSLO(slo SLO) error {
+	var violations []string
+
+	if slo.MaxRecoveryTime > 0 && result.RecoveryTime > slo.MaxRecoveryTime {
+		violations = append(violations, fmt.Sprintf("recovery time %s above %s", result.RecoveryTime,
+			slo.MaxRecoveryTime))
+	}
+
+	if slo.MaxAPIUnavailability > 0 && result.APIUnavailability > slo.MaxAPIUnavailability {
+		violations = append(violations, fmt.Sprintf("API unavailability %s above %s", result.APIUnavailability,
+			slo.MaxAPIUnavailability))
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%s on node %s does not meet its SLO: %v", result.Component, result.Node, violations)
+	}
+
+	return nil
+}
+
+// RestartComponent forcibly restarts the static pod container of a component on a node while probing the API,
+// waits for the component to recover and returns the recovery measurements. The recovery time is measured from the
+// stop of the container until the component is ready with a new container.
+func RestartComponent(apiClient *clients.Settings, component Component, nodeName string,
+	timeout time.Duration) (*Result, error) {
+	result := &Result{Component: component.Name, Node: nodeName}
+	containerRestart := disruption.RestartContainer(apiClient, nodeName, component.Container)
+	apiProbe := prober.New("kube-apiserver", prober.APICheck(apiClient), ProbeInterval).WithTimeout(2 * time.Second)
+
+	previousContainerIDs, err := getContainerIDs(apiClient, component, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	apiProbe.Start()
+
+	err = containerRestart.Inject()
+	if err != nil {
+		apiProbe.Stop()
+
+		return nil, err
+	}
+
+	start := time.Now()
+
+	_, err = containerRestart.WaitForRecovery(timeout)
+	if err == nil {
+		readyTimeout := timeout - time.Since(start)
+		if readyTimeout < minReadyTimeout {
+			readyTimeout = minReadyTimeout
+		}
+
+		err = WaitForComponentReady(apiClient, component, nodeName, previousContainerIDs, readyTimeout)
+	}
+
+	result.RecoveryTime = time.Since(containerRestart.RestoreTime())
+	probeResult := apiProbe.Stop()
+	result.Outages = probeResult.Windows
+	result.APIUnavailability = probeResult.Downtime
+
+	glog.V(90).Infof("%s on node %s recovered after %s, API unavailable for %s", component.Name, nodeName,
+		result.RecoveryTime, result.APIUnavailability)
+
+	return result, err
+}
+
+// WaitForComponentReady waits until the static pod of a component is ready on a node. When previousContainerIDs,
+// keyed by pod name, are given the component container must also have been replaced so that the readiness reported
+// before a restart is not accepted. API errors are tolerated while waiting since the API may be recovering.
+func WaitForComponentReady(apiClient *clients.Settings, component Component, nodeName string,
+	previousContainerIDs map[string]string, timeout time.Duration) error {
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			podsList, err := listComponentPods(apiClient, component, nodeName)
+			if err != nil {
+				glog.V(90).Infof("Failed to list %s pods: %s", component.Name, err)
+
+				return false, nil
+			}
+
+			if len(podsList) == 0 {
+				return false, nil
+			}
+
+			for _, componentPod := range podsList {
+				if !isPodReady(componentPod.Object) {
+					return false, nil
+				}
+
+				previousContainerID, found := previousContainerIDs[componentPod.Object.Name]
+				if found && getContainerID(componentPod.Object, component.Container) == previousContainerID {
+					return false, nil
+				}
+			}
+
+			return true, nil
+		})
+	if err != nil {
+		return fmt.Errorf("%s pod on node %s not ready within %s: %w", component.Name, nodeName, timeout, err)
+	}
+
+	return nil
+}
+
+// getContainerIDs returns the ID of the component container of its pods on the node, keyed by pod name.
+func getContainerIDs(apiClient *clients.Settings, component Component, nodeName string) (map[string]string, error) {
+	podsList, err := listComponentPods(apiClient, component, nodeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s pods: %w", component.Name, err)
+	}
+
+	containerIDs := make(map[string]string)
+
+	for _, componentPod := range podsList {
+		containerIDs[componentPod.Object.Name] = getContainerID(componentPod.Object, component.Container)
+	}
+
+	return containerIDs, nil
+}
+
+func listComponentPods(apiClient *clients.Settings, component Component, nodeName string) ([]*pod.Builder, error) {
+	return pod.List(apiClient, component.Namespace, metav1.ListOptions{
+		LabelSelector: component.PodLabel,
+		FieldSelector: fields.SelectorFromSet(fields.Set{"spec.nodeName": nodeName}).String(),
+	})
+}
+
+func getContainerID(componentPod *v1.Pod, container string) string {
+	for _, status := range componentPod.Status.ContainerStatuses {
+		if status.Name == container {
+			return status.ContainerID
+		}
+	}
+
+	return ""
+}
+
+func isPodReady(componentPod *v1.Pod) bool {
+	for _, condition := range componentPod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,456 @@
+package disruption
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cmd"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// Kubelet is the kubelet systemd service.
+	Kubelet = "kubelet"
+	// Crio is the CRI-O systemd service.
+	Crio = "crio"
+	// MaxDowntime is the longest time a service or an interface is allowed to be kept down.
+	MaxDowntime = 10 * time.Minute
+	// injectDelay delays the disruptive commands so that the exec session returns before kubelet, CRI-O or the
+	// network go down.
+	injectDelay  = 2 * time.Second
+	pollInterval = 10 * time.Second
+	// nodeLeaseNamespace is the namespace of the leases renewed by kubelet.
+	nodeLeaseNamespace = "kube-node-lease"
+)
+
+// Disruption is an action disrupting a node service paired with the waiter of its recovery.
+type Disruption struct {
+	apiClient   *clients.Settings
+	Node        string
+	Description string
+	start       time.Time
+	bootID      string
+	restoreAt   time.Time
+	inject      func() error
+	recovered   func() (bool, error)
+}
+
+// StopService stops a systemd service of the node for the given downtime. The start of the service and of the
+// active services requiring it, e.g. kubelet which requires CRI-O and is stopped along with it, is scheduled on the
+// node with a transient systemd timer before the service is stopped, so they are restored even though the node
+// cannot be reached while kubelet or CRI-O are down. The node is recovered once all these services are active again
+// and kubelet renewed the node lease.
+func StopService(apiClient *clients.Settings, nodeName, service string, downtime time.Duration) *Disruption {
+	disruption := &Disruption{
+		apiClient:   apiClient,
+		Node:        nodeName,
+		Description: fmt.Sprintf("stop %s for %s", service, downtime),
+	}
+
+	units := []string{service}
+
+	disruption.inject = func() error {
+		if err := checkDowntime(downtime); err != nil {
+			return err
+		}
+
+		dependents, err := getActiveDependents(apiClient, nodeName, service)
+		if err != nil {
+			return fmt.Errorf("failed to list the services requiring %s: %w", service, err)
+		}
+
+		units = append([]string{service}, dependents...)
+
+		err = schedule(apiClient, nodeName, downtime+injectDelay,
+			append([]string{"systemctl", "start"}, units...)...)
+		if err != nil {
+			return fmt.Errorf("failed to schedule %v start: %w", units, err)
+		}
+
+		disruption.restoreAt = time.Now().Add(downtime + injectDelay)
+
+		return schedule(apiClient, nodeName, injectDelay, "systemctl", "stop", service)
+	}
+
+	disruption.recovered = func() (bool, error) {
+		active, err := areServicesActive(apiClient, nodeName, units...)
+		if err != nil || !active {
+			return false, err
+		}
+
+		return isLeaseRenewedSince(apiClient, nodeName, disruption.restoreAt)
+	}
+
+	return disruption
+}
+
+// RestartService restarts a systemd service of the node.
+func RestartService(apiClient *clients.Settings, nodeName, service string) *Disruption {
+	disruption := &Disruption{
+		apiClient:   apiClient,
+		Node:        nodeName,
+		Description: fmt.Sprintf("restart %s", service),
+	}
+
+	disruption.inject = func() error {
+		disruption.restoreAt = time.Now().Add(injectDelay)
+
+		return schedule(apiClient, nodeName, injectDelay, "systemctl", "restart", service)
+	}
+
+	disruption.recovered = func() (bool, error) {
+		return isServiceActive(apiClient, nodeName, service)
+	}
+
+	return disruption
+}
+
+// KillProcess sends a signal, e.g. KILL or TERM, to the host processes with the given name. The processes are
+// expected to be restarted by systemd or kubelet.
+func KillProcess(apiClient *clients.Settings, nodeName, process, signal string) *Disruption {
+	disruption := &Disruption{
+		apiClient:   apiClient,
+		Node:        nodeName,
+		Description: fmt.Sprintf("kill -%s %s", signal, process),
+	}
+
+	disruption.inject = func() error {
+		disruption.restoreAt = time.Now()
+
+		_, err := cmd.ExecCmdWithClient(apiClient, []string{"chroot", "/rootfs", "pkill", "-" + signal, "-x", process},
+			nodeName)
+		if err != nil {
+			return fmt.Errorf("failed to kill process %s: %w", process, err)
+		}
+
+		return nil
+	}
+
+	disruption.recovered = func() (bool, error) {
+		output, err := cmd.ExecCmdWithClient(apiClient, []string{"chroot", "/rootfs", "pgrep", "-x", process},
+			nodeName)
+		if err != nil {
+			return false, err
+		}
+
+		return strings.TrimSpace(output) != "", nil
+	}
+
+	return disruption
+}
+
+// RestartContainer stops the running CRI-O containers with the given name, e.g. the etcd static pod container, and
+// lets kubelet start them again. The stop is scheduled on the node so that it also works for the containers
+// serving the exec session, e.g. kube-apiserver. Once the containers are restarted the restore time is set to the
+// time the first of them actually stopped, as reported by CRI-O.
+func RestartContainer(apiClient *clients.Settings, nodeName, container string) *Disruption {
+	disruption := &Disruption{
+		apiClient:   apiClient,
+		Node:        nodeName,
+		Description: fmt.Sprintf("restart container %s", container),
+	}
+
+	var stoppedIDs []string
+
+	disruption.inject = func() error {
+		var err error
+
+		stoppedIDs, err = getRunningContainers(apiClient, nodeName, container)
+		if err != nil {
+			return err
+		}
+
+		if len(stoppedIDs) == 0 {
+			return fmt.Errorf("no running container %s found", container)
+		}
+
+		disruption.restoreAt = time.Now().Add(injectDelay)
+
+		return schedule(apiClient, nodeName, injectDelay, append([]string{"crictl", "stop"}, stoppedIDs...)...)
+	}
+
+	disruption.recovered = func() (bool, error) {
+		containerIDs, err := getRunningContainers(apiClient, nodeName, container)
+		if err != nil {
+			return false, err
+		}
+
+		for _, containerID := range containerIDs {
+			for _, stoppedID := range stoppedIDs {
+				if containerID == stoppedID {
+					return false, nil
+				}
+			}
+		}
+
+		if len(containerIDs) == 0 {
+			return false, nil
+		}
+
+		// The stopped containers may already be removed, the scheduled stop time is kept then.
+		stoppedAt, err := getFirstFinishedAt(apiClient, nodeName, stoppedIDs)
+		if err != nil {
+			glog.V(90).Infof("Failed to get the stop time of container %s: %s", container, err)
+
+			return true, nil
+		}
+
+		disruption.restoreAt = stoppedAt
+
+		return true, nil
+	}
+
+	return disruption
+}
+
+// InterfaceDown takes a host interface down for the given downtime. The interface is brought up by a transient
+// systemd timer scheduled before it goes down, so it is restored even if the node cannot be reached through it.
+func InterfaceDown(apiClient *clients.Settings, nodeName, iface string, downtime time.Duration) *Disruption {
+	disruption := &Disruption{
+		apiClient:   apiClient,
+		Node:        nodeName,
+		Description: fmt.Sprintf("interface %s down for %s", iface, downtime),
+	}
+
+	disruption.inject = func() error {
+		if err := checkDowntime(downtime); err != nil {
+			return err
+		}
+
+		_, err := cmd.ExecCmdWithClient(apiClient, []string{"chroot", "/rootfs", "ip", "link", "show", iface},
+			nodeName)
+		if err != nil {
+			return fmt.Errorf("interface %s not found: %w", iface, err)
+		}
+
+		err = schedule(apiClient, nodeName, downtime+injectDelay, "ip", "link", "set", iface, "up")
+		if err != nil {
+			return fmt.Errorf("failed to schedule interface %s up: %w", iface, err)
+		}
+
+		disruption.restoreAt = time.Now().Add(downtime + injectDelay)
+
+		return schedule(apiClient, nodeName, injectDelay, "ip", "link", "set", iface, "down")
+	}
+
+	disruption.recovered = func() (bool, error) {
+		output, err := cmd.ExecCmdWithClient(apiClient, []string{"cat", "/sys/class/net/" + iface + "/operstate"},
+			nodeName)
+		if err != nil {
+			return false, err
+		}
+
+		return strings.TrimSpace(output) == "up", nil
+	}
+
+	return disruption
+}
+
+// Inject records the node boot ID and runs the disruptive action.
+func (disruption *Disruption) Inject() error {
+	node, err := nodes.Pull(disruption.apiClient, disruption.Node)
+	if err != nil {
+		return err
+	}
+
+	disruption.bootID = node.Object.Status.NodeInfo.BootID
+	disruption.start = time.Now()
+
+	glog.V(90).Infof("Injecting disruption on node %s: %s", disruption.Node, disruption.Description)
+
+	return disruption.inject()
+}
+
+// RestoreTime returns the time the disrupted service was restored, from which its recovery is measured.
+func (disruption *Disruption) RestoreTime() time.Time {
+	return disruption.restoreAt
+}
+
+// WaitForRecovery waits until the disrupted service is restored and the node reports Ready again, and returns the
+// time from the restoration to the recovery. An error is returned if the node was rebooted in the meantime. API and
+// exec errors are tolerated while waiting since the node may be unreachable.
+func (disruption *Disruption) WaitForRecovery(timeout time.Duration) (time.Duration, error) {
+	if disruption.start.IsZero() {
+		return 0, fmt.Errorf("disruption %s was not injected", disruption.Description)
+	}
+
+	// Let the node be disrupted for the whole downtime before checking its recovery.
+	time.Sleep(time.Until(disruption.restoreAt))
+
+	var rebooted bool
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			node, err := nodes.Pull(disruption.apiClient, disruption.Node)
+			if err != nil {
+				glog.V(90).Infof("Failed to pull node %s: %s", disruption.Node, err)
+
+				return false, nil
+			}
+
+			if node.Object.Status.NodeInfo.BootID != disruption.bootID {
+				rebooted = true
+
+				return false, fmt.Errorf("node %s was rebooted", disruption.Node)
+			}
+
+			ready, err := node.IsReady()
+			if err != nil || !ready {
+				return false, nil
+			}
+
+			recovered, err := disruption.recovered()
+			if err != nil {
+				glog.V(90).Infof("Failed to check recovery of %s on node %s: %s", disruption.Description,
+					disruption.Node, err)
+
+				return false, nil
+			}
+
+			return recovered, nil
+		})
+
+	if rebooted {
+		return 0, fmt.Errorf("node %s was rebooted while recovering from %s", disruption.Node, disruption.Description)
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("node %s did not recover from %s within %s: %w", disruption.Node,
+			disruption.Description, timeout, err)
+	}
+
+	recoveryTime := time.Since(disruption.restoreAt)
+
+	glog.V(90).Infof("Node %s recovered from %s after %s", disruption.Node, disruption.Description, recoveryTime)
+
+	return recoveryTime, nil
+}
+
+// schedule runs a command on the node from a transient systemd timer firing after the given delay.
+func schedule(apiClient *clients.Settings, nodeName string, delay time.Duration, command ...string) error {
+	cmdToExec := append([]string{"chroot", "/rootfs", "systemd-run",
+		fmt.Sprintf("--on-active=%d", int(delay.Seconds())), "--timer-property=AccuracySec=1"}, command...)
+
+	glog.V(90).Infof("Scheduling %v on node %s in %s", command, nodeName, delay)
+
+	_, err := cmd.ExecCmdWithClient(apiClient, cmdToExec, nodeName)
+
+	return err
+}
+
+func checkDowntime(downtime time.Duration) error {
+	if downtime <= 0 || downtime > MaxDowntime {
+		return fmt.Errorf("downtime %s must be positive and at most %s", downtime, MaxDowntime)
+	}
+
+	return nil
+}
+
+func isServiceActive(apiClient *clients.Settings, nodeName, service string) (bool, error) {
+	return areServicesActive(apiClient, nodeName, service)
+}
+
+// areServicesActive tells whether all the services are active. systemctl is-active prints the state of every
+// service and exits with an error when one is not active.
+func areServicesActive(apiClient *clients.Settings, nodeName string, services ...string) (bool, error) {
+	output, err := cmd.ExecCmdWithClient(apiClient,
+		append([]string{"chroot", "/rootfs", "systemctl", "is-active"}, services...), nodeName)
+	if err != nil {
+		return false, err
+	}
+
+	states := strings.Fields(output)
+	if len(states) != len(services) {
+		return false, nil
+	}
+
+	for _, state := range states {
+		if state != "active" {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// getActiveDependents returns the active services requiring the service, which systemd stops along with it.
+func getActiveDependents(apiClient *clients.Settings, nodeName, service string) ([]string, error) {
+	output, err := cmd.ExecCmdWithClient(apiClient, []string{"chroot", "/rootfs", "systemctl", "list-dependencies",
+		"--reverse", "--plain", "--no-legend", service}, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	var dependents []string
+
+	for _, unit := range strings.Fields(output) {
+		if !strings.HasSuffix(unit, ".service") || strings.TrimSuffix(unit, ".service") == service {
+			continue
+		}
+
+		active, err := isServiceActive(apiClient, nodeName, unit)
+		if err != nil {
+			glog.V(90).Infof("Service %s requiring %s on node %s is not active: %s", unit, service, nodeName, err)
+
+			continue
+		}
+
+		if active {
+			dependents = append(dependents, unit)
+		}
+	}
+
+	return dependents, nil
+}
+
+// isLeaseRenewedSince tells whether kubelet renewed the lease of the node since the given time, i.e. kubelet runs
+// and reaches the API again.
+func isLeaseRenewedSince(apiClient *clients.Settings, nodeName string, since time.Time) (bool, error) {
+	lease, err := apiClient.K8sClient.CoordinationV1().Leases(nodeLeaseNamespace).Get(
+		context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	return lease.Spec.RenewTime != nil && lease.Spec.RenewTime.After(since), nil
+}
+
+func getRunningContainers(apiClient *clients.Settings, nodeName, container string) ([]string, error) {
+	output, err := cmd.ExecCmdWithClient(apiClient, []string{"chroot", "/rootfs", "crictl", "ps", "-q", "--state",
+		"running", "--name", "^" + container + "$"}, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(output), nil
+}
+
+// getFirstFinishedAt returns the time the first of the containers finished.
+func getFirstFinishedAt(apiClient *clients.Settings, nodeName string, containerIDs []string) (time.Time, error) {
+	var firstFinishedAt time.Time
+
+	for _, containerID := range containerIDs {
+		output, err := cmd.ExecCmdWithClient(apiClient, []string{"chroot", "/rootfs", "crictl", "inspect", "-o",
+			"go-template", "--template", "{{.status.finishedAt}}", containerID}, nodeName)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		finishedAt, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(output))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid finish time of container %s: %w", containerID, err)
+		}
+
+		if firstFinishedAt.IsZero() || finishedAt.Before(firstFinishedAt) {
+			firstFinishedAt = finishedAt
+		}
+	}
+
+	return firstFinishedAt, nil
+}
@@ -0,0 +1,335 @@
+package latency
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/nto" //nolint:misspell
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/config"
+	"github.com/openshift/cluster-node-tuning-operator/pkg/performanceprofile/controller/performanceprofile/components"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
+)
+
+// Tool is a real-time latency measurement tool.
+type Tool string
+
+const (
+	// Cyclictest measures the wake up latency of timer driven threads.
+	Cyclictest Tool = "cyclictest"
+	// Oslat measures the OS jitter seen by busy looping threads.
+	Oslat Tool = "oslat"
+	// Hwlatdetect measures the latency introduced by the hardware and firmware, e.g. SMIs.
+	Hwlatdetect Tool = "hwlatdetect"
+)
+
+// Tools are all the supported latency measurement tools.
+var Tools = []Tool{Cyclictest, Oslat, Hwlatdetect}
+
+const (
+	// MetricMaxLatency is the prefix of the max latency metric in microseconds.
+	MetricMaxLatency = "ranmetrics_latency_max_us"
+	// MetricHistogram is the prefix of the latency histogram metric, formatted as "bucket_us:count" pairs.
+	MetricHistogram = "ranmetrics_latency_histogram_us"
+	// MetricOverflows is the prefix of the metric counting the samples above the histogram range.
+	MetricOverflows = "ranmetrics_latency_histogram_overflows"
+
+	containerName = "latency"
+	// cpusPrefix prefixes the line printed by the pod with the cpus it is pinned to.
+	cpusPrefix = "cpus: "
+	// histogramBuckets is the number of 1us buckets of the cyclictest histogram.
+	histogramBuckets = 100
+	cpusCmd          = "cpus=$(cat /sys/fs/cgroup/cpuset.cpus.effective 2>/dev/null || " +
+		"cat /sys/fs/cgroup/cpuset/cpuset.cpus); echo \"" + cpusPrefix + "$cpus\"; "
+	// defaultMemory is the memory requested by the latency pods.
+	defaultMemory = "256Mi"
+	// podStartTimeout is the time given to the latency pod on top of the measurement duration.
+	podStartTimeout = 10 * time.Minute
+)
+
+// Options describes a latency measurement.
+type Options struct {
+	Tool      Tool
+	Image     string
+	Namespace string
+	NodeName  string
+	// CPUs is the number of exclusive cpus requested by the pod.
+	CPUs     int
+	Memory   string
+	Duration time.Duration
+}
+
+// Result is the outcome of a latency measurement.
+type Result struct {
+	Tool Tool   `json:"tool"`
+	Node string `json:"node"`
+	// CPUs is the cpuset the measurement ran on.
+	CPUs string `json:"cpus"`
+	// MaxLatency is the highest latency measured on any cpu, in microseconds.
+	MaxLatency int `json:"maxLatency"`
+	// PerCPU keeps the max latency of each measurement thread or cpu, in microseconds.
+	PerCPU map[string]int `json:"perCpu"`
+	// Histogram keeps the number of samples of each latency in microseconds.
+	Histogram map[int]uint64 `json:"histogram"`
+	// Overflows is the number of samples above the histogram range.
+	Overflows uint64 `json:"overflows"`
+}
+
+// Settings are the latency measurement parameters read from the general configuration.
+type Settings struct {
+	Image    string
+	Duration time.Duration
+	CPUs     int
+	// MaxLatency keeps the max latency threshold of each tool in microseconds.
+	MaxLatency map[Tool]int
+	// Tolerance is the max increase of the max latency compared to a baseline, in microseconds.
+	Tolerance int
+}
+
+// NewSettings parses the latency measurement parameters of the general configuration.
+func NewSettings(conf *config.GeneralConfig) (*Settings, error) {
+	duration, err := time.ParseDuration(conf.LatencyTestDuration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latency test duration: %w", err)
+	}
+
+	cpus, err := strconv.Atoi(conf.LatencyTestCPUs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latency test cpus: %w", err)
+	}
+
+	tolerance, err := strconv.Atoi(conf.LatencyTolerance)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latency tolerance: %w", err)
+	}
+
+	settings := &Settings{
+		Image:      conf.LatencyTestImage,
+		Duration:   duration,
+		CPUs:       cpus,
+		MaxLatency: map[Tool]int{},
+		Tolerance:  tolerance,
+	}
+
+	for tool, threshold := range map[Tool]string{
+		Cyclictest:  conf.CyclictestMaxLatency,
+		Oslat:       conf.OslatMaxLatency,
+		Hwlatdetect: conf.HwlatdetectMaxLatency,
+	} {
+		settings.MaxLatency[tool], err = strconv.Atoi(threshold)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s max latency: %w", tool, err)
+		}
+	}
+
+	return settings, nil
+}
+
+// Options returns the options of a measurement with the given tool on the given node.
+func (settings *Settings) Options(tool Tool, namespace, nodeName string) Options {
+	return Options{
+		Tool:      tool,
+		Image:     settings.Image,
+		Namespace: namespace,
+		NodeName:  nodeName,
+		CPUs:      settings.CPUs,
+		Memory:    defaultMemory,
+		Duration:  settings.Duration,
+	}
+}
+
+// GetPerformanceProfile returns the first performance profile with reserved and isolated cpus.
+func GetPerformanceProfile(apiClient *clients.Settings) (*nto.Builder, error) {
+	profiles, err := nto.ListProfiles(apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profile := range profiles {
+		if profile.Object.Spec.CPU != nil && profile.Object.Spec.CPU.Reserved != nil &&
+			profile.Object.Spec.CPU.Isolated != nil {
+			return profile, nil
+		}
+	}
+
+	return nil, fmt.Errorf("performance profile with reserved and isolated cpus not found")
+}
+
+// Run measures the latency with a guaranteed pod pinned to isolated cpus of the performance profile.
+// It returns an error if the pod could not run, its cpus are not isolated or its output could not be parsed.
+func Run(apiClient *clients.Settings, profile *nto.Builder, options Options) (*Result, error) {
+	glog.V(90).Infof("Running %s on node %s for %s", options.Tool, options.NodeName, options.Duration)
+
+	command, err := toolCmd(options)
+	if err != nil {
+		return nil, err
+	}
+
+	isolated, err := cpuset.Parse(string(*profile.Object.Spec.CPU.Isolated))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse isolated cpus of performance profile %s: %w",
+			profile.Object.Name, err)
+	}
+
+	latencyPod := definePod(apiClient, profile, options, command)
+
+	latencyPod, err = latencyPod.CreateAndWaitUntilRunning(podStartTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s pod: %w", options.Tool, err)
+	}
+
+	defer func() {
+		_, err := latencyPod.DeleteAndWait(podStartTimeout)
+		if err != nil {
+			glog.V(90).Infof("Failed to delete %s pod: %s", options.Tool, err)
+		}
+	}()
+
+	if latencyPod.Object.Status.QOSClass != v1.PodQOSGuaranteed {
+		return nil, fmt.Errorf("%s pod has QoS class %s instead of %s", options.Tool,
+			latencyPod.Object.Status.QOSClass, v1.PodQOSGuaranteed)
+	}
+
+	err = latencyPod.WaitUntilInStatus(v1.PodSucceeded, options.Duration+podStartTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%s pod did not complete: %w", options.Tool, err)
+	}
+
+	output, err := latencyPod.GetFullLog(containerName)
+	if err != nil {
+		return nil, err
+	}
+
+	glog.V(100).Infof("%s output:\n%s", options.Tool, output)
+
+	result, err := parseOutput(options.Tool, output)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Node = options.NodeName
+
+	podCPUs, err := cpuset.Parse(result.CPUs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s pod cpus %q: %w", options.Tool, result.CPUs, err)
+	}
+
+	if podCPUs.IsEmpty() {
+		return nil, fmt.Errorf("%s pod cpus not found in its output", options.Tool)
+	}
+
+	if !podCPUs.IsSubsetOf(isolated) {
+		return nil, fmt.Errorf("%s pod ran on cpus %s which are not all isolated (%s)", options.Tool,
+			podCPUs.String(), isolated.String())
+	}
+
+	return result, nil
+}
+
+// CheckThreshold returns an error if the max latency is above maxLatency microseconds.
+func (result *Result) CheckThreshold(maxLatency int) error {
+	if result.MaxLatency > maxLatency {
+		return fmt.Errorf("%s max latency %dus on node %s cpus %s is above %dus", result.Tool, result.MaxLatency,
+			result.Node, result.CPUs, maxLatency)
+	}
+
+	if result.Overflows > 0 && result.Tool == Cyclictest {
+		return fmt.Errorf("%s measured %d samples above %dus on node %s", result.Tool, result.Overflows,
+			histogramBuckets, result.Node)
+	}
+
+	return nil
+}
+
+// CheckRegression returns an error if the max latency is more than tolerance microseconds above the max latency
+// of the baseline measured with the same tool, e.g. before a power mode change.
+func (result *Result) CheckRegression(baseline *Result, tolerance int) error {
+	if baseline == nil || baseline.Tool != result.Tool {
+		return fmt.Errorf("no %s baseline to compare the latency with", result.Tool)
+	}
+
+	if result.MaxLatency > baseline.MaxLatency+tolerance {
+		return fmt.Errorf("%s max latency %dus on node %s is more than %dus above the baseline %dus", result.Tool,
+			result.MaxLatency, result.Node, tolerance, baseline.MaxLatency)
+	}
+
+	return nil
+}
+
+// Metrics returns the latency metrics of the result named after the tool and the given tag.
+func (result *Result) Metrics(tag string) map[string]string {
+	buckets := make([]int, 0, len(result.Histogram))
+
+	for bucket := range result.Histogram {
+		buckets = append(buckets, bucket)
+	}
+
+	sort.Ints(buckets)
+
+	histogram := make([]string, 0, len(buckets))
+
+	for _, bucket := range buckets {
+		if result.Histogram[bucket] > 0 {
+			histogram = append(histogram, fmt.Sprintf("%d:%d", bucket, result.Histogram[bucket]))
+		}
+	}
+
+	return map[string]string{
+		fmt.Sprintf("%s_%s_%s", MetricMaxLatency, result.Tool, tag): strconv.Itoa(result.MaxLatency),
+		fmt.Sprintf("%s_%s_%s", MetricHistogram, result.Tool, tag):  strings.Join(histogram, ","),
+		fmt.Sprintf("%s_%s_%s", MetricOverflows, result.Tool, tag):  strconv.FormatUint(result.Overflows, 10),
+	}
+}
+
+func toolCmd(options Options) (string, error) {
+	seconds := int(options.Duration.Seconds())
+	if seconds <= 0 {
+		return "", fmt.Errorf("invalid %s duration %s", options.Tool, options.Duration)
+	}
+
+	switch options.Tool {
+	case Cyclictest:
+		return fmt.Sprintf("%scyclictest -q -D %ds -p 95 -m -i 1000 -h %d -a $cpus -t $(nproc) 2>&1",
+			cpusCmd, seconds, histogramBuckets), nil
+	case Oslat:
+		return fmt.Sprintf("%soslat --cpu-list $cpus --rtprio 1 --duration %ds 2>&1", cpusCmd, seconds), nil
+	case Hwlatdetect:
+		// hwlatdetect exits with an error when the threshold is exceeded, the result is evaluated from its output.
+		return fmt.Sprintf("%shwlatdetect --duration=%ds --threshold=1us 2>&1; exit 0", cpusCmd, seconds), nil
+	default:
+		return "", fmt.Errorf("unsupported latency tool %s", options.Tool)
+	}
+}
+
+func definePod(apiClient *clients.Settings, profile *nto.Builder, options Options, command string) *pod.Builder {
+	runtimeClass := fmt.Sprintf("%s-%s", components.ComponentNamePrefix, profile.Object.Name)
+	quantities := v1.ResourceList{
+		v1.ResourceCPU:    *resource.NewQuantity(int64(options.CPUs), resource.DecimalSI),
+		v1.ResourceMemory: resource.MustParse(options.Memory),
+	}
+
+	latencyPod := pod.NewBuilder(apiClient, fmt.Sprintf("%s-%s", options.Tool, options.NodeName), options.Namespace,
+		options.Image)
+	latencyPod.Definition.Spec.Containers[0].Name = containerName
+	latencyPod.Definition.Spec.Containers[0].Command = []string{"/bin/bash", "-c", command}
+	latencyPod.Definition.Spec.Containers[0].Resources = v1.ResourceRequirements{
+		Requests: quantities,
+		Limits:   quantities,
+	}
+	latencyPod.Definition.Spec.RuntimeClassName = &runtimeClass
+	latencyPod.Definition.Spec.RestartPolicy = v1.RestartPolicyNever
+	latencyPod.Definition.Annotations = map[string]string{
+		"cpu-load-balancing.crio.io": "disable",
+		"cpu-quota.crio.io":          "disable",
+		"irq-load-balancing.crio.io": "disable",
+	}
+
+	return latencyPod.DefineOnNode(options.NodeName).WithPrivilegedFlag()
+}
@@ -0,0 +1,233 @@
+package latency
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// oslatHistogramRegex matches the oslat histogram lines, e.g. "001 (us):	 19487938 19487722".
+	oslatHistogramRegex = regexp.MustCompile(`^(\d+) \(us\):\s+(.*)$`)
+	// hwlatSampleRegex matches the hwlatdetect samples, e.g. "ts: 1700000000.123456789, inner:13, outer:12".
+	hwlatSampleRegex = regexp.MustCompile(`inner:\s*(\d+),\s*outer:\s*(\d+)`)
+	// hwlatMaxRegex matches the hwlatdetect summary, e.g. "Max Latency: 13us".
+	hwlatMaxRegex = regexp.MustCompile(`Max Latency:\s*(\d+)us`)
+)
+
+// parseOutput parses the log of a latency pod into a result.
+func parseOutput(tool Tool, output string) (*Result, error) {
+	result := &Result{
+		Tool:      tool,
+		PerCPU:    map[string]int{},
+		Histogram: map[int]uint64{},
+	}
+
+	lines := strings.Split(strings.ReplaceAll(output, "\r", ""), "\n")
+
+	for _, line := range lines {
+		if cpus, found := strings.CutPrefix(line, cpusPrefix); found {
+			result.CPUs = strings.TrimSpace(cpus)
+		}
+	}
+
+	var err error
+
+	switch tool {
+	case Cyclictest:
+		err = parseCyclictest(lines, result)
+	case Oslat:
+		err = parseOslat(lines, result)
+	case Hwlatdetect:
+		err = parseHwlatdetect(lines, result)
+	default:
+		err = fmt.Errorf("unsupported latency tool %s", tool)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, latency := range result.PerCPU {
+		if latency > result.MaxLatency {
+			result.MaxLatency = latency
+		}
+	}
+
+	return result, nil
+}
+
+// parseCyclictest parses the quiet histogram output of cyclictest:
+//
+//	000000 000000 000000
+//	000001 001234 001200
+//	# Max Latencies: 00005 00007
+//	# Histogram Overflows: 00000 00000
+func parseCyclictest(lines []string, result *Result) error {
+	var maxFound bool
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "# Max Latencies:"):
+			values, err := parseInts(strings.TrimPrefix(line, "# Max Latencies:"))
+			if err != nil {
+				return fmt.Errorf("failed to parse cyclictest line %q: %w", line, err)
+			}
+
+			for thread, value := range values {
+				result.PerCPU[fmt.Sprintf("thread%d", thread)] = value
+			}
+
+			maxFound = true
+		case strings.HasPrefix(line, "# Histogram Overflows:"):
+			values, err := parseInts(strings.TrimPrefix(line, "# Histogram Overflows:"))
+			if err != nil {
+				return fmt.Errorf("failed to parse cyclictest line %q: %w", line, err)
+			}
+
+			for _, value := range values {
+				result.Overflows += uint64(value)
+			}
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		default:
+			values, err := parseInts(line)
+			if err != nil || len(values) < 2 {
+				continue
+			}
+
+			for _, count := range values[1:] {
+				result.Histogram[values[0]] += uint64(count)
+			}
+		}
+	}
+
+	if !maxFound {
+		return fmt.Errorf("max latencies not found in cyclictest output")
+	}
+
+	return nil
+}
+
+// parseOslat parses the output of oslat:
+//
+//	Core:	 1 2
+//	001 (us):	 19487938 19487722
+//	Maximum:	 11 7 (us)
+func parseOslat(lines []string, result *Result) error {
+	var (
+		cores     []string
+		maxValues []int
+	)
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if histogram := oslatHistogramRegex.FindStringSubmatch(line); histogram != nil {
+			bucket, _ := strconv.Atoi(histogram[1])
+
+			values, err := parseInts(histogram[2])
+			if err != nil {
+				return fmt.Errorf("failed to parse oslat line %q: %w", line, err)
+			}
+
+			for _, count := range values {
+				result.Histogram[bucket] += uint64(count)
+			}
+
+			continue
+		}
+
+		key, values, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+
+		switch key {
+		case "Core":
+			cores = strings.Fields(values)
+		case "Maximum":
+			var err error
+
+			maxValues, err = parseInts(strings.TrimSuffix(strings.TrimSpace(values), "(us)"))
+			if err != nil {
+				return fmt.Errorf("failed to parse oslat line %q: %w", line, err)
+			}
+		}
+	}
+
+	if len(maxValues) == 0 {
+		return fmt.Errorf("maximum latencies not found in oslat output")
+	}
+
+	for index, value := range maxValues {
+		cpu := fmt.Sprintf("thread%d", index)
+		if index < len(cores) {
+			cpu = "cpu" + cores[index]
+		}
+
+		result.PerCPU[cpu] = value
+	}
+
+	return nil
+}
+
+// parseHwlatdetect parses the output of hwlatdetect:
+//
+//	Max Latency: 13us
+//	ts: 1700000000.123456789, inner:13, outer:12
+//
+// The max latency is reported as "Below threshold" when no sample exceeded the threshold.
+func parseHwlatdetect(lines []string, result *Result) error {
+	var summaryFound bool
+
+	for _, line := range lines {
+		if sample := hwlatSampleRegex.FindStringSubmatch(line); sample != nil {
+			inner, _ := strconv.Atoi(sample[1])
+			outer, _ := strconv.Atoi(sample[2])
+
+			if outer > inner {
+				inner = outer
+			}
+
+			result.Histogram[inner]++
+
+			continue
+		}
+
+		if strings.Contains(line, "Max Latency:") {
+			summaryFound = true
+
+			if maxLatency := hwlatMaxRegex.FindStringSubmatch(line); maxLatency != nil {
+				value, _ := strconv.Atoi(maxLatency[1])
+				result.PerCPU["all"] = value
+			} else {
+				result.PerCPU["all"] = 0
+			}
+		}
+	}
+
+	if !summaryFound {
+		return fmt.Errorf("max latency not found in hwlatdetect output")
+	}
+
+	return nil
+}
+
+func parseInts(content string) ([]int, error) {
+	var values []int
+
+	for _, field := range strings.Fields(content) {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
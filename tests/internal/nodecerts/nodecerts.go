@@ -0,0 +1,186 @@
+package nodecerts
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/certs"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cmd"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// KubeletClient is the kubelet client certificate used to authenticate to the API server.
+	KubeletClient = "/var/lib/kubelet/pki/kubelet-client-current.pem"
+	// KubeletServer is the kubelet serving certificate.
+	KubeletServer = "/var/lib/kubelet/pki/kubelet-server-current.pem"
+	// staticPodCerts matches the certificates of the control plane static pods, e.g.
+	// /etc/kubernetes/static-pod-resources/kube-apiserver-certs/secrets/<secret>/tls.crt.
+	staticPodCerts = "/etc/kubernetes/static-pod-resources/*-certs/secrets"
+	// fileSeparator is printed before the content of every certificate file read from the node.
+	fileSeparator = "### "
+	// rotationDelay delays the kubelet restart so that the exec session returns before kubelet goes down.
+	rotationDelay = 2 * time.Second
+	pollInterval  = 10 * time.Second
+)
+
+// ListNodeCertificates returns the kubelet client and serving certificates and the control plane static pod
+// certificates of the node, sorted by expiry. Only the first certificate of every file is reported.
+func ListNodeCertificates(nodeName string) ([]certs.Certificate, error) {
+	script := fmt.Sprintf(
+		`for file in %s %s $(find %s -name '*.crt' 2>/dev/null); do [ -f "$file" ] && echo "%s$file" && cat "$file"; `+
+			`done; exit 0`, KubeletClient, KubeletServer, staticPodCerts, fileSeparator)
+
+	output, err := cmd.ExecCmd([]string{"chroot", "/rootfs", "/bin/sh", "-c", script}, nodeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificates of node %s: %w", nodeName, err)
+	}
+
+	certificates := parseCertificateFiles(output)
+	certs.SortByExpiry(certificates)
+
+	return certificates, nil
+}
+
+// GetNodeCertificate returns the first certificate of a file of the node.
+func GetNodeCertificate(nodeName, path string) (*certs.Certificate, error) {
+	output, err := cmd.ExecCmd([]string{"chroot", "/rootfs", "cat", path}, nodeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate %s of node %s: %w", path, nodeName, err)
+	}
+
+	return parseCertificate(path, output)
+}
+
+// ForceKubeletRotation removes a kubelet certificate, KubeletClient or KubeletServer, and restarts kubelet, which
+// requests a new certificate through a CertificateSigningRequest. Without its client certificate kubelet uses its
+// bootstrap kubeconfig. The removal and the restart are scheduled on the node with a transient systemd timer
+// since kubelet serves the exec session.
+func ForceKubeletRotation(nodeName, path string) error {
+	if path != KubeletClient && path != KubeletServer {
+		return fmt.Errorf("%s is not a kubelet certificate", path)
+	}
+
+	glog.V(90).Infof("Forcing the rotation of %s on node %s", path, nodeName)
+
+	_, err := cmd.ExecCmd([]string{"chroot", "/rootfs", "systemd-run",
+		fmt.Sprintf("--on-active=%d", int(rotationDelay.Seconds())), "--timer-property=AccuracySec=1",
+		"/bin/sh", "-c", fmt.Sprintf("rm -f %s && systemctl restart kubelet", path)}, nodeName)
+
+	return err
+}
+
+// WaitForRenewal waits until the certificate file of the node holds a certificate with a serial number different
+// from previousSerial and the node is Ready, and returns the new certificate. When approveCSRs is true the pending
+// kubelet CertificateSigningRequests of the node are approved while waiting, otherwise they are expected to be
+// auto-approved. API and exec errors are tolerated while waiting since kubelet is restarting.
+func WaitForRenewal(apiClient *clients.Settings, nodeName, path, previousSerial string, approveCSRs bool,
+	timeout time.Duration) (*certs.Certificate, error) {
+	var renewed *certs.Certificate
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			if approveCSRs {
+				_, err := cluster.ApprovePendingCSRs(apiClient, nodeName)
+				if err != nil {
+					glog.V(90).Infof("Failed to approve pending CSRs: %s", err)
+				}
+			}
+
+			node, err := nodes.Pull(apiClient, nodeName)
+			if err != nil {
+				glog.V(90).Infof("Failed to pull node %s: %s", nodeName, err)
+
+				return false, nil
+			}
+
+			ready, err := node.IsReady()
+			if err != nil || !ready {
+				return false, nil
+			}
+
+			certificate, err := GetNodeCertificate(nodeName, path)
+			if err != nil {
+				glog.V(90).Infof("Failed to get certificate %s of node %s: %s", path, nodeName, err)
+
+				return false, nil
+			}
+
+			if certificate.Serial == previousSerial {
+				return false, nil
+			}
+
+			renewed = certificate
+
+			return true, nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("certificate %s of node %s was not renewed within %s: %w", path, nodeName, timeout,
+			err)
+	}
+
+	return renewed, nil
+}
+
+// parseCertificateFiles parses the content of the certificate files printed after their path and the file
+// separator. Files without a valid certificate are skipped.
+func parseCertificateFiles(output string) []certs.Certificate {
+	var certificates []certs.Certificate
+
+	for _, file := range strings.Split(output, fileSeparator) {
+		path, content, found := strings.Cut(file, "\n")
+		if !found || strings.TrimSpace(path) == "" {
+			continue
+		}
+
+		certificate, err := parseCertificate(strings.TrimSpace(path), content)
+		if err != nil {
+			glog.V(90).Infof("Skipping %s: %s", path, err)
+
+			continue
+		}
+
+		certificates = append(certificates, *certificate)
+	}
+
+	return certificates
+}
+
+// parseCertificate parses the first PEM encoded certificate of the content.
+func parseCertificate(source, content string) (*certs.Certificate, error) {
+	rest := []byte(content)
+
+	for {
+		var block *pem.Block
+
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no certificate found in %s", source)
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		parsed, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate in %s: %w", source, err)
+		}
+
+		return &certs.Certificate{
+			Source:    source,
+			Subject:   parsed.Subject.String(),
+			Serial:    parsed.SerialNumber.String(),
+			NotBefore: parsed.NotBefore,
+			NotAfter:  parsed.NotAfter,
+		}, nil
+	}
+}
@@ -0,0 +1,238 @@
+package nodesampler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cmd"
+	. "github.com/openshift-kni/eco-gosystem/tests/internal/inittools"
+)
+
+// Memory keeps the memory usage of a node in kB.
+type Memory struct {
+	TotalKB     uint64 `json:"totalKB"`
+	AvailableKB uint64 `json:"availableKB"`
+}
+
+// AvailablePercent returns the percentage of memory available on the node.
+func (memory Memory) AvailablePercent() float64 {
+	if memory.TotalKB == 0 {
+		return 0
+	}
+
+	return float64(memory.AvailableKB) * 100 / float64(memory.TotalKB)
+}
+
+// Pressure keeps the 10 seconds PSI averages of a resource.
+type Pressure struct {
+	SomeAvg10 float64 `json:"someAvg10"`
+	FullAvg10 float64 `json:"fullAvg10"`
+}
+
+// Sample is a single set of resource metrics collected from a node.
+type Sample struct {
+	Node      string    `json:"node"`
+	Timestamp time.Time `json:"timestamp"`
+	LoadAvg1  float64   `json:"loadAvg1"`
+	LoadAvg5  float64   `json:"loadAvg5"`
+	LoadAvg15 float64   `json:"loadAvg15"`
+	// CPUUtilization is the busy percentage of each core since the previous sample of the node.
+	// It is empty for the first sample of a node.
+	CPUUtilization map[string]float64  `json:"cpuUtilization,omitempty"`
+	Memory         Memory              `json:"memory"`
+	Pressure       map[string]Pressure `json:"pressure,omitempty"`
+	// Softirqs is the count of each softirq summed over all the cpus since boot.
+	Softirqs map[string]uint64 `json:"softirqs"`
+}
+
+// MaxCPUUtilization returns the highest utilization of all the cores in the sample.
+func (sample Sample) MaxCPUUtilization() float64 {
+	var maxUtilization float64
+
+	for _, utilization := range sample.CPUUtilization {
+		if utilization > maxUtilization {
+			maxUtilization = utilization
+		}
+	}
+
+	return maxUtilization
+}
+
+// Sampler periodically collects resource metrics from a set of nodes in the background.
+type Sampler struct {
+	nodeNames []string
+	interval  time.Duration
+	mutex     sync.Mutex
+	results   *Results
+	previous  map[string]map[string]cpuTimes
+	stop      chan struct{}
+	done      sync.WaitGroup
+}
+
+// New returns a sampler collecting metrics from the given nodes at the given interval.
+func New(nodeNames []string, interval time.Duration) *Sampler {
+	return &Sampler{
+		nodeNames: nodeNames,
+		interval:  interval,
+	}
+}
+
+// Start collects a first sample from every node and keeps sampling in the background until Stop is called.
+func (sampler *Sampler) Start() error {
+	if len(sampler.nodeNames) == 0 {
+		return fmt.Errorf("node sampler requires at least one node")
+	}
+
+	if sampler.interval <= 0 {
+		return fmt.Errorf("invalid node sampler interval %s", sampler.interval)
+	}
+
+	if sampler.stop != nil {
+		return fmt.Errorf("node sampler already started")
+	}
+
+	glog.V(90).Infof("Starting node sampler on nodes %v every %s", sampler.nodeNames, sampler.interval)
+
+	sampler.results = &Results{Interval: sampler.interval.String()}
+	sampler.previous = map[string]map[string]cpuTimes{}
+	sampler.stop = make(chan struct{})
+
+	sampler.sampleAll()
+
+	sampler.done.Add(1)
+
+	go func() {
+		defer sampler.done.Done()
+
+		ticker := time.NewTicker(sampler.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-sampler.stop:
+				return
+			case <-ticker.C:
+				sampler.sampleAll()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop ends the background sampling and returns the collected results.
+func (sampler *Sampler) Stop() *Results {
+	if sampler.stop == nil {
+		return &Results{}
+	}
+
+	close(sampler.stop)
+	sampler.done.Wait()
+	sampler.stop = nil
+
+	glog.V(90).Infof("Stopped node sampler, collected %d samples", len(sampler.results.Samples))
+
+	return sampler.results
+}
+
+func (sampler *Sampler) sampleAll() {
+	var group sync.WaitGroup
+
+	for _, nodeName := range sampler.nodeNames {
+		group.Add(1)
+
+		go func(nodeName string) {
+			defer group.Done()
+
+			sample, err := sampler.sampleNode(nodeName)
+
+			sampler.mutex.Lock()
+			defer sampler.mutex.Unlock()
+
+			if err != nil {
+				glog.V(90).Infof("Failed to sample node %s: %s", nodeName, err)
+				sampler.results.Errors = append(sampler.results.Errors,
+					fmt.Sprintf("%s %s: %s", time.Now().Format(time.RFC3339), nodeName, err))
+
+				return
+			}
+
+			sampler.results.Samples = append(sampler.results.Samples, *sample)
+		}(nodeName)
+	}
+
+	group.Wait()
+}
+
+func (sampler *Sampler) sampleNode(nodeName string) (*Sample, error) {
+	raw, err := collectSample(APIClient, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	sampler.mutex.Lock()
+	previous := sampler.previous[nodeName]
+	sampler.previous[nodeName] = raw.cpuTimes
+	sampler.mutex.Unlock()
+
+	sample := raw.toSample(nodeName)
+	sample.CPUUtilization = cpuUtilization(previous, raw.cpuTimes)
+
+	return sample, nil
+}
+
+// Collect reads the resource metrics of a node of the cluster of the given api client once. CPUUtilization is not
+// set on the returned sample since it requires two consecutive reads.
+func Collect(apiClient *clients.Settings, nodeName string) (*Sample, error) {
+	raw, err := collectSample(apiClient, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	return raw.toSample(nodeName), nil
+}
+
+// collectSample reads the resource metrics of a node once.
+func collectSample(apiClient *clients.Settings, nodeName string) (*rawSample, error) {
+	output, err := cmd.ExecCmdWithClient(apiClient, []string{"/bin/sh", "-c", sampleCmd}, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSample(output)
+}
+
+func (raw *rawSample) toSample(nodeName string) *Sample {
+	return &Sample{
+		Node:      nodeName,
+		Timestamp: time.Now(),
+		LoadAvg1:  raw.loadAvg[0],
+		LoadAvg5:  raw.loadAvg[1],
+		LoadAvg15: raw.loadAvg[2],
+		Memory:    raw.memory,
+		Pressure:  raw.pressure,
+		Softirqs:  raw.softirqs,
+	}
+}
+
+func cpuUtilization(previous, current map[string]cpuTimes) map[string]float64 {
+	if previous == nil {
+		return nil
+	}
+
+	utilization := map[string]float64{}
+
+	for cpu, times := range current {
+		before, found := previous[cpu]
+		if !found || times.total <= before.total {
+			continue
+		}
+
+		utilization[cpu] = float64(times.busy-before.busy) * 100 / float64(times.total-before.total)
+	}
+
+	return utilization
+}
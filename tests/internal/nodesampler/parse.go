@@ -0,0 +1,241 @@
+package nodesampler
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// sectionSeparator delimits the output of the files read by sampleCmd.
+	sectionSeparator = "@@"
+	// sampleCmd reads all the sampled files in a single exec to reduce the load generated on the node.
+	sampleCmd = "cat /proc/loadavg; echo " + sectionSeparator + "; " +
+		"grep '^cpu[0-9]' /proc/stat; echo " + sectionSeparator + "; " +
+		"grep -E '^(MemTotal|MemAvailable):' /proc/meminfo; echo " + sectionSeparator + "; " +
+		"grep -H . /proc/pressure/* 2>/dev/null; echo " + sectionSeparator + "; " +
+		"cat /proc/softirqs"
+	sampleSections = 5
+)
+
+// cpuTimes keeps the cumulative busy and total jiffies of a core read from /proc/stat.
+type cpuTimes struct {
+	busy  uint64
+	total uint64
+}
+
+// rawSample is the parsed output of sampleCmd.
+type rawSample struct {
+	loadAvg  [3]float64
+	cpuTimes map[string]cpuTimes
+	memory   Memory
+	pressure map[string]Pressure
+	softirqs map[string]uint64
+}
+
+func parseSample(output string) (*rawSample, error) {
+	sections := strings.Split(strings.ReplaceAll(output, "\r", ""), sectionSeparator+"\n")
+	if len(sections) != sampleSections {
+		return nil, fmt.Errorf("unexpected sample output, found %d sections instead of %d: %q",
+			len(sections), sampleSections, output)
+	}
+
+	var (
+		sample = &rawSample{}
+		err    error
+	)
+
+	if sample.loadAvg, err = parseLoadAvg(sections[0]); err != nil {
+		return nil, err
+	}
+
+	if sample.cpuTimes, err = parseCPUTimes(sections[1]); err != nil {
+		return nil, err
+	}
+
+	if sample.memory, err = parseMemInfo(sections[2]); err != nil {
+		return nil, err
+	}
+
+	if sample.pressure, err = parsePressure(sections[3]); err != nil {
+		return nil, err
+	}
+
+	if sample.softirqs, err = parseSoftirqs(sections[4]); err != nil {
+		return nil, err
+	}
+
+	return sample, nil
+}
+
+// parseLoadAvg parses /proc/loadavg, e.g. "0.52 0.58 0.59 2/1209 39207".
+func parseLoadAvg(content string) ([3]float64, error) {
+	var loadAvg [3]float64
+
+	fields := strings.Fields(content)
+	if len(fields) < len(loadAvg) {
+		return loadAvg, fmt.Errorf("unexpected /proc/loadavg content: %q", content)
+	}
+
+	for index := range loadAvg {
+		value, err := strconv.ParseFloat(fields[index], 64)
+		if err != nil {
+			return loadAvg, fmt.Errorf("failed to parse /proc/loadavg content %q: %w", content, err)
+		}
+
+		loadAvg[index] = value
+	}
+
+	return loadAvg, nil
+}
+
+// parseCPUTimes parses the per core lines of /proc/stat, e.g.
+// "cpu0 4705 356 584 3699176 23060 0 277 0 0 0".
+func parseCPUTimes(content string) (map[string]cpuTimes, error) {
+	times := map[string]cpuTimes{}
+
+	for _, line := range nonEmptyLines(content) {
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("unexpected /proc/stat line: %q", line)
+		}
+
+		var current cpuTimes
+
+		for index, field := range fields[1:] {
+			value, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse /proc/stat line %q: %w", line, err)
+			}
+
+			// guest and guest_nice are already accounted in user and nice.
+			if index >= 8 {
+				break
+			}
+
+			current.total += value
+
+			// idle and iowait are the 4th and 5th columns.
+			if index != 3 && index != 4 {
+				current.busy += value
+			}
+		}
+
+		times[fields[0]] = current
+	}
+
+	return times, nil
+}
+
+// parseMemInfo parses the MemTotal and MemAvailable lines of /proc/meminfo.
+func parseMemInfo(content string) (Memory, error) {
+	var memory Memory
+
+	for _, line := range nonEmptyLines(content) {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return memory, fmt.Errorf("unexpected /proc/meminfo line: %q", line)
+		}
+
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return memory, fmt.Errorf("failed to parse /proc/meminfo line %q: %w", line, err)
+		}
+
+		switch fields[0] {
+		case "MemTotal:":
+			memory.TotalKB = value
+		case "MemAvailable:":
+			memory.AvailableKB = value
+		}
+	}
+
+	if memory.TotalKB == 0 {
+		return memory, fmt.Errorf("MemTotal not found in /proc/meminfo content: %q", content)
+	}
+
+	return memory, nil
+}
+
+// parsePressure parses the PSI files, e.g.
+// "/proc/pressure/cpu:some avg10=0.00 avg60=0.00 avg300=0.00 total=1234".
+// Kernels booted without psi=1 do not expose /proc/pressure, in which case an empty map is returned.
+func parsePressure(content string) (map[string]Pressure, error) {
+	pressure := map[string]Pressure{}
+
+	for _, line := range nonEmptyLines(content) {
+		path, values, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("unexpected pressure line: %q", line)
+		}
+
+		fields := strings.Fields(values)
+		if len(fields) < 2 || !strings.HasPrefix(fields[1], "avg10=") {
+			return nil, fmt.Errorf("unexpected pressure line: %q", line)
+		}
+
+		avg10, err := strconv.ParseFloat(strings.TrimPrefix(fields[1], "avg10="), 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse pressure line %q: %w", line, err)
+		}
+
+		resource := filepath.Base(path)
+		current := pressure[resource]
+
+		switch fields[0] {
+		case "some":
+			current.SomeAvg10 = avg10
+		case "full":
+			current.FullAvg10 = avg10
+		}
+
+		pressure[resource] = current
+	}
+
+	return pressure, nil
+}
+
+// parseSoftirqs parses /proc/softirqs and returns the count of each softirq summed over all the cpus.
+func parseSoftirqs(content string) (map[string]uint64, error) {
+	softirqs := map[string]uint64{}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+
+	// The first line is the cpus header.
+	scanner.Scan()
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		var total uint64
+
+		for _, field := range fields[1:] {
+			value, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse /proc/softirqs line %q: %w", scanner.Text(), err)
+			}
+
+			total += value
+		}
+
+		softirqs[strings.TrimSuffix(fields[0], ":")] = total
+	}
+
+	return softirqs, scanner.Err()
+}
+
+func nonEmptyLines(content string) []string {
+	var lines []string
+
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,241 @@
+package nodesampler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	"gonum.org/v1/gonum/floats"
+)
+
+// Thresholds are the limits the collected samples are asserted against. A zero value disables the threshold.
+type Thresholds struct {
+	MaxLoadAvg1            float64 `json:"maxLoadAvg1,omitempty"`
+	MaxCPUUtilization      float64 `json:"maxCpuUtilization,omitempty"`
+	MinMemAvailablePercent float64 `json:"minMemAvailablePercent,omitempty"`
+	MaxPressureSomeAvg10   float64 `json:"maxPressureSomeAvg10,omitempty"`
+}
+
+// NodeSummary keeps the peak values observed on a node.
+type NodeSummary struct {
+	Samples                int     `json:"samples"`
+	MaxLoadAvg1            float64 `json:"maxLoadAvg1"`
+	MaxCPUUtilization      float64 `json:"maxCpuUtilization"`
+	MinMemAvailablePercent float64 `json:"minMemAvailablePercent"`
+	MaxPressureSomeAvg10   float64 `json:"maxPressureSomeAvg10"`
+}
+
+// Results holds the time series collected by a sampler.
+type Results struct {
+	Interval string   `json:"interval"`
+	Samples  []Sample `json:"samples"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// Summary returns the peak values observed on each node.
+func (results *Results) Summary() map[string]NodeSummary {
+	series := map[string]map[string][]float64{}
+
+	for _, sample := range results.Samples {
+		if _, found := series[sample.Node]; !found {
+			series[sample.Node] = map[string][]float64{}
+		}
+
+		nodeSeries := series[sample.Node]
+		nodeSeries["load"] = append(nodeSeries["load"], sample.LoadAvg1)
+		nodeSeries["cpu"] = append(nodeSeries["cpu"], sample.MaxCPUUtilization())
+		nodeSeries["memory"] = append(nodeSeries["memory"], sample.Memory.AvailablePercent())
+
+		var maxPressure float64
+
+		for _, pressure := range sample.Pressure {
+			if pressure.SomeAvg10 > maxPressure {
+				maxPressure = pressure.SomeAvg10
+			}
+		}
+
+		nodeSeries["pressure"] = append(nodeSeries["pressure"], maxPressure)
+	}
+
+	summary := map[string]NodeSummary{}
+
+	for node, nodeSeries := range series {
+		summary[node] = NodeSummary{
+			Samples:                len(nodeSeries["load"]),
+			MaxLoadAvg1:            floats.Max(nodeSeries["load"]),
+			MaxCPUUtilization:      floats.Max(nodeSeries["cpu"]),
+			MinMemAvailablePercent: floats.Min(nodeSeries["memory"]),
+			MaxPressureSomeAvg10:   floats.Max(nodeSeries["pressure"]),
+		}
+	}
+
+	return summary
+}
+
+// CheckErrors returns an error when no sample was collected or when the sampling errors exceed maxErrorRatio of
+// the sampling attempts, the isolated errors being tolerated, e.g. a node briefly unreachable.
+func (results *Results) CheckErrors(maxErrorRatio float64) error {
+	if len(results.Samples) == 0 {
+		return fmt.Errorf("no node samples collected, %d sampling errors", len(results.Errors))
+	}
+
+	attempts := len(results.Samples) + len(results.Errors)
+	if errorRatio := float64(len(results.Errors)) / float64(attempts); errorRatio > maxErrorRatio {
+		return fmt.Errorf("%d of %d node sampling attempts failed, above the tolerated ratio %.2f",
+			len(results.Errors), attempts, maxErrorRatio)
+	}
+
+	return nil
+}
+
+// CheckThresholds returns a line per sample exceeding one of the thresholds.
+func (results *Results) CheckThresholds(thresholds Thresholds) []string {
+	var violations []string
+
+	for _, sample := range results.Samples {
+		timestamp := sample.Timestamp.Format(time.RFC3339)
+
+		if thresholds.MaxLoadAvg1 > 0 && sample.LoadAvg1 >= thresholds.MaxLoadAvg1 {
+			violations = append(violations, fmt.Sprintf("%s %s: load average %.2f is above %.2f",
+				timestamp, sample.Node, sample.LoadAvg1, thresholds.MaxLoadAvg1))
+		}
+
+		if thresholds.MaxCPUUtilization > 0 {
+			for _, cpu := range sortedKeys(sample.CPUUtilization) {
+				if sample.CPUUtilization[cpu] > thresholds.MaxCPUUtilization {
+					violations = append(violations, fmt.Sprintf("%s %s: %s utilization %.1f%% is above %.1f%%",
+						timestamp, sample.Node, cpu, sample.CPUUtilization[cpu], thresholds.MaxCPUUtilization))
+				}
+			}
+		}
+
+		if thresholds.MinMemAvailablePercent > 0 && sample.Memory.AvailablePercent() < thresholds.MinMemAvailablePercent {
+			violations = append(violations, fmt.Sprintf("%s %s: available memory %.1f%% is below %.1f%%",
+				timestamp, sample.Node, sample.Memory.AvailablePercent(), thresholds.MinMemAvailablePercent))
+		}
+
+		if thresholds.MaxPressureSomeAvg10 > 0 {
+			for _, resource := range sortedKeys(sample.Pressure) {
+				if sample.Pressure[resource].SomeAvg10 > thresholds.MaxPressureSomeAvg10 {
+					violations = append(violations, fmt.Sprintf("%s %s: %s pressure %.2f is above %.2f",
+						timestamp, sample.Node, resource, sample.Pressure[resource].SomeAvg10,
+						thresholds.MaxPressureSomeAvg10))
+				}
+			}
+		}
+	}
+
+	return violations
+}
+
+// WriteArtifacts writes the raw time series as JSON and CSV along with the threshold evaluation to dir.
+// The files are prefixed with name.
+func (results *Results) WriteArtifacts(dir, name string, thresholds Thresholds) error {
+	glog.V(90).Infof("Writing node sampler artifacts %s to %s", name, dir)
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	err = writeJSON(filepath.Join(dir, name+"_samples.json"), results)
+	if err != nil {
+		return err
+	}
+
+	err = results.writeCSV(filepath.Join(dir, name+"_samples.csv"))
+	if err != nil {
+		return err
+	}
+
+	violations := results.CheckThresholds(thresholds)
+
+	return writeJSON(filepath.Join(dir, name+"_thresholds.json"), struct {
+		Thresholds Thresholds             `json:"thresholds"`
+		Summary    map[string]NodeSummary `json:"summary"`
+		Violations []string               `json:"violations"`
+		Passed     bool                   `json:"passed"`
+	}{
+		Thresholds: thresholds,
+		Summary:    results.Summary(),
+		Violations: violations,
+		Passed:     len(violations) == 0,
+	})
+}
+
+func (results *Results) writeCSV(path string) error {
+	csvFile, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = csvFile.Close()
+	}()
+
+	writer := csv.NewWriter(csvFile)
+
+	err = writer.Write([]string{"timestamp", "node", "load1", "load5", "load15", "max_cpu_utilization",
+		"mem_total_kb", "mem_available_kb", "cpu_pressure", "memory_pressure", "io_pressure", "net_rx_softirqs",
+		"net_tx_softirqs", "timer_softirqs"})
+	if err != nil {
+		return err
+	}
+
+	for _, sample := range results.Samples {
+		err = writer.Write([]string{
+			sample.Timestamp.Format(time.RFC3339),
+			sample.Node,
+			formatFloat(sample.LoadAvg1),
+			formatFloat(sample.LoadAvg5),
+			formatFloat(sample.LoadAvg15),
+			formatFloat(sample.MaxCPUUtilization()),
+			strconv.FormatUint(sample.Memory.TotalKB, 10),
+			strconv.FormatUint(sample.Memory.AvailableKB, 10),
+			formatFloat(sample.Pressure["cpu"].SomeAvg10),
+			formatFloat(sample.Pressure["memory"].SomeAvg10),
+			formatFloat(sample.Pressure["io"].SomeAvg10),
+			strconv.FormatUint(sample.Softirqs["NET_RX"], 10),
+			strconv.FormatUint(sample.Softirqs["NET_TX"], 10),
+			strconv.FormatUint(sample.Softirqs["TIMER"], 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func writeJSON(path string, content interface{}) error {
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
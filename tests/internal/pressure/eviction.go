@@ -0,0 +1,193 @@
+package pressure
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	evictedReason = "Evicted"
+	pollInterval  = 10 * time.Second
+)
+
+// qosRank is the order in which kubelet evicts the pods of a QoS class staying within their requests.
+var qosRank = map[v1.PodQOSClass]int{
+	v1.PodQOSBestEffort: 0,
+	v1.PodQOSBurstable:  1,
+	v1.PodQOSGuaranteed: 2,
+}
+
+// Eviction is a pod evicted by kubelet.
+type Eviction struct {
+	Pod     string         `json:"pod"`
+	QoS     v1.PodQOSClass `json:"qos"`
+	Time    time.Time      `json:"time"`
+	Message string         `json:"message"`
+}
+
+// ListEvictions returns the pods of the namespace evicted by kubelet after the given time, sorted by eviction time.
+func ListEvictions(apiClient *clients.Settings, nsname string, since time.Time) ([]Eviction, error) {
+	podList, err := pod.List(apiClient, nsname)
+	if err != nil {
+		return nil, err
+	}
+
+	var evictions []Eviction
+
+	for _, evictedPod := range podList {
+		status := evictedPod.Object.Status
+		if status.Phase != v1.PodFailed || status.Reason != evictedReason {
+			continue
+		}
+
+		eviction := Eviction{
+			Pod:     evictedPod.Object.Name,
+			QoS:     status.QOSClass,
+			Time:    evictionTime(evictedPod.Object),
+			Message: status.Message,
+		}
+
+		if eviction.Time.Before(since.Truncate(time.Second)) {
+			continue
+		}
+
+		evictions = append(evictions, eviction)
+	}
+
+	sort.SliceStable(evictions, func(i, j int) bool {
+		return evictions[i].Time.Before(evictions[j].Time)
+	})
+
+	return evictions, nil
+}
+
+// WaitForEvictions waits until all the given pods of the namespace are evicted by kubelet and returns them. API
+// errors are tolerated while waiting since the node is under pressure.
+func WaitForEvictions(apiClient *clients.Settings, nsname string, podNames []string, since time.Time,
+	timeout time.Duration) ([]Eviction, error) {
+	var evictions []Eviction
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			var err error
+
+			evictions, err = ListEvictions(apiClient, nsname, since)
+			if err != nil {
+				glog.V(90).Infof("Failed to list evicted pods in namespace %s: %s", nsname, err)
+
+				return false, nil
+			}
+
+			for _, podName := range podNames {
+				if findEviction(evictions, podName) == nil {
+					return false, nil
+				}
+			}
+
+			return true, nil
+		})
+	if err != nil {
+		return evictions, fmt.Errorf("pods %v were not evicted within %s: %w", podNames, timeout, err)
+	}
+
+	return evictions, nil
+}
+
+// CheckEvictionOrder returns an error if a pod was evicted before a pod of a lower QoS class, e.g. a Burstable pod
+// before a BestEffort pod. The order only applies to pods staying within their requests.
+func CheckEvictionOrder(evictions []Eviction) error {
+	for index, eviction := range evictions {
+		for _, later := range evictions[index+1:] {
+			if qosRank[later.QoS] < qosRank[eviction.QoS] {
+				return fmt.Errorf("%s pod %s was evicted before %s pod %s", eviction.QoS, eviction.Pod, later.QoS,
+					later.Pod)
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckGuaranteedSpared returns an error if a Guaranteed pod was evicted. Guaranteed pods staying within their
+// limits, as the DU workload, must be spared by the node-pressure eviction.
+func CheckGuaranteedSpared(evictions []Eviction) error {
+	for _, eviction := range evictions {
+		if eviction.QoS == v1.PodQOSGuaranteed {
+			return fmt.Errorf("%s pod %s was evicted: %s", eviction.QoS, eviction.Pod, eviction.Message)
+		}
+	}
+
+	return nil
+}
+
+// WaitForNodeCondition waits until the node reports the condition with the given status and returns the time
+// elapsed since start. API errors are tolerated while waiting since the node is under pressure.
+func WaitForNodeCondition(apiClient *clients.Settings, nodeName string, condition v1.NodeConditionType,
+	status v1.ConditionStatus, start time.Time, timeout time.Duration) (time.Duration, error) {
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			node, err := nodes.Pull(apiClient, nodeName)
+			if err != nil {
+				glog.V(90).Infof("Failed to pull node %s: %s", nodeName, err)
+
+				return false, nil
+			}
+
+			for _, nodeCondition := range node.Object.Status.Conditions {
+				if nodeCondition.Type == condition {
+					return nodeCondition.Status == status, nil
+				}
+			}
+
+			return false, nil
+		})
+	if err != nil {
+		return 0, fmt.Errorf("node %s condition %s was not %s within %s: %w", nodeName, condition, status, timeout,
+			err)
+	}
+
+	elapsed := time.Since(start)
+
+	glog.V(90).Infof("Node %s condition %s is %s after %s", nodeName, condition, status, elapsed)
+
+	return elapsed, nil
+}
+
+// evictionTime returns the time kubelet marked the pod for eviction, or the time its last container terminated
+// when the pod has no disruption condition.
+func evictionTime(evictedPod *v1.Pod) time.Time {
+	for _, condition := range evictedPod.Status.Conditions {
+		if condition.Type == v1.DisruptionTarget {
+			return condition.LastTransitionTime.Time
+		}
+	}
+
+	var finishedAt time.Time
+
+	for _, status := range evictedPod.Status.ContainerStatuses {
+		if status.State.Terminated != nil && status.State.Terminated.FinishedAt.Time.After(finishedAt) {
+			finishedAt = status.State.Terminated.FinishedAt.Time
+		}
+	}
+
+	return finishedAt
+}
+
+func findEviction(evictions []Eviction, podName string) *Eviction {
+	for index := range evictions {
+		if evictions[index].Pod == podName {
+			return &evictions[index]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package pressure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// Eviction signals of the kubelet eviction thresholds.
+	memoryAvailable = "memory.available"
+	nodefsAvailable = "nodefs.available"
+	// Default kubelet hard eviction thresholds, used when the kubelet configuration does not set them.
+	defaultMemoryThreshold = "100Mi"
+	defaultNodefsThreshold = "10%"
+)
+
+// nodeStats are the node stats of the kubelet summary API used by the eviction manager.
+type nodeStats struct {
+	Node struct {
+		Memory struct {
+			AvailableBytes  uint64 `json:"availableBytes"`
+			WorkingSetBytes uint64 `json:"workingSetBytes"`
+		} `json:"memory"`
+		Fs struct {
+			AvailableBytes uint64 `json:"availableBytes"`
+			CapacityBytes  uint64 `json:"capacityBytes"`
+		} `json:"fs"`
+	} `json:"node"`
+}
+
+// kubeletConfig is the part of the kubelet configz endpoint holding the eviction thresholds.
+type kubeletConfig struct {
+	KubeletConfig struct {
+		EvictionHard map[string]string `json:"evictionHard"`
+	} `json:"kubeletconfig"`
+}
+
+// getNodeStats returns the node stats reported by the kubelet summary API through the API server node proxy.
+func getNodeStats(apiClient *clients.Settings, nodeName string) (*nodeStats, error) {
+	raw, err := apiClient.K8sClient.CoreV1().RESTClient().Get().
+		AbsPath("/api/v1/nodes", nodeName, "proxy", "stats", "summary").DoRaw(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubelet stats of node %s: %w", nodeName, err)
+	}
+
+	stats := &nodeStats{}
+
+	err = json.Unmarshal(raw, stats)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubelet stats of node %s: %w", nodeName, err)
+	}
+
+	return stats, nil
+}
+
+// getEvictionThreshold returns the kubelet hard eviction threshold of a signal in bytes. Percentage thresholds are
+// computed from the given capacity.
+func getEvictionThreshold(apiClient *clients.Settings, nodeName, signal string, capacity uint64) (uint64, error) {
+	raw, err := apiClient.K8sClient.CoreV1().RESTClient().Get().
+		AbsPath("/api/v1/nodes", nodeName, "proxy", "configz").DoRaw(context.TODO())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get kubelet configuration of node %s: %w", nodeName, err)
+	}
+
+	config := &kubeletConfig{}
+
+	err = json.Unmarshal(raw, config)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse kubelet configuration of node %s: %w", nodeName, err)
+	}
+
+	threshold, found := config.KubeletConfig.EvictionHard[signal]
+	if !found {
+		threshold = defaultMemoryThreshold
+		if signal == nodefsAvailable {
+			threshold = defaultNodefsThreshold
+		}
+	}
+
+	return parseThreshold(threshold, capacity)
+}
+
+// parseThreshold converts an eviction threshold, either a quantity like 100Mi or a percentage like 10%, to bytes.
+func parseThreshold(threshold string, capacity uint64) (uint64, error) {
+	if percentage, found := strings.CutSuffix(threshold, "%"); found {
+		value, err := strconv.ParseFloat(percentage, 64)
+		if err != nil || value < 0 || value > 100 {
+			return 0, fmt.Errorf("invalid eviction threshold %s", threshold)
+		}
+
+		return uint64(float64(capacity) * value / 100), nil
+	}
+
+	quantity, err := resource.ParseQuantity(threshold)
+	if err != nil {
+		return 0, fmt.Errorf("invalid eviction threshold %s: %w", threshold, err)
+	}
+
+	return uint64(quantity.Value()), nil
+}
@@ -0,0 +1,360 @@
+package pressure
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cmd"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// MaxDuration is the longest time a pressure is allowed to be applied before it is released on the node.
+	MaxDuration = 30 * time.Minute
+	// stressOverhead is the memory requested by a stress pod on top of the memory it fills.
+	stressOverhead = 64 * 1024 * 1024
+	// minMemAvailable is the memory left to the kernel when filling the node memory, so that the pressure is
+	// handled by the kubelet eviction manager rather than the kernel OOM killer.
+	minMemAvailable = 64 * 1024 * 1024
+	fillMountPath   = "/fill"
+	fillFileName    = "fill.img"
+	deleteTimeout   = 2 * time.Minute
+	kubeletRootDir  = "/var/lib/kubelet"
+)
+
+// Pressure is a controlled source of resource pressure on a node paired with its release.
+type Pressure struct {
+	Node        string
+	Description string
+	// Condition is the node condition expected to be set by kubelet while the pressure is applied. It is empty
+	// when the pressure is contained to a pod.
+	Condition v1.NodeConditionType
+	// Pods are the names of the pods created by the pressure, expected to be evicted by kubelet.
+	Pods    []string
+	apply   func() error
+	release func() error
+}
+
+// StressPod returns a pod of the given QoS class filling fillBytes of a memory backed volume on the node and
+// keeping it filled. Burstable and Guaranteed pods request the filled memory plus an overhead, so they stay within
+// their requests. The pod is stopped by kubelet after maxDuration, which releases its memory.
+func StressPod(apiClient *clients.Settings, name, nsname, nodeName, image string, qos v1.PodQOSClass,
+	fillBytes int64, maxDuration time.Duration) *pod.Builder {
+	sizeLimit := resource.NewQuantity(fillBytes+stressOverhead, resource.BinarySI)
+	command := fmt.Sprintf("dd if=/dev/zero of=%s bs=1M count=%d status=none && sleep infinity",
+		filepath.Join(fillMountPath, fillFileName), fillBytes/(1024*1024))
+
+	return fillPod(apiClient, name, nsname, nodeName, image, command, v1.StorageMediumMemory, sizeLimit,
+		stressResources(qos, *sizeLimit), maxDuration)
+}
+
+// Memory fills the node memory from a BestEffort stress pod until the memory available to kubelet is half of the
+// memory.available hard eviction threshold, which sets the MemoryPressure node condition. The stress pod is
+// expected to be the first pod evicted by kubelet. An error is returned if the kernel would run out of memory
+// before kubelet reaches its threshold, e.g. when hugepages are reserved on the node.
+func Memory(apiClient *clients.Settings, nsname, nodeName, image string, maxDuration time.Duration) *Pressure {
+	podName := "pressure-memory"
+	pressure := &Pressure{
+		Node:        nodeName,
+		Description: "memory pressure",
+		Condition:   v1.NodeMemoryPressure,
+		Pods:        []string{podName},
+	}
+
+	pressure.apply = func() error {
+		if err := checkDuration(maxDuration); err != nil {
+			return err
+		}
+
+		stats, err := getNodeStats(apiClient, nodeName)
+		if err != nil {
+			return err
+		}
+
+		available := stats.Node.Memory.AvailableBytes
+		capacity := available + stats.Node.Memory.WorkingSetBytes
+
+		threshold, err := getEvictionThreshold(apiClient, nodeName, memoryAvailable, capacity)
+		if err != nil {
+			return err
+		}
+
+		if available <= threshold {
+			return fmt.Errorf("node %s is already under memory pressure: %d bytes available", nodeName, available)
+		}
+
+		fillBytes := available - threshold/2
+
+		memAvailable, err := getMemAvailable(nodeName)
+		if err != nil {
+			return err
+		}
+
+		if fillBytes+minMemAvailable > memAvailable {
+			return fmt.Errorf("cannot fill %d bytes on node %s with %d bytes available to the kernel", fillBytes,
+				nodeName, memAvailable)
+		}
+
+		glog.V(90).Infof("Filling %d bytes of memory on node %s, eviction threshold %d bytes", fillBytes, nodeName,
+			threshold)
+
+		_, err = StressPod(apiClient, podName, nsname, nodeName, image, v1.PodQOSBestEffort, int64(fillBytes),
+			maxDuration).Create()
+
+		return err
+	}
+
+	pressure.release = func() error {
+		return deletePods(apiClient, nsname, pressure.Pods)
+	}
+
+	return pressure
+}
+
+// EphemeralStorage writes writeBytes to the local storage of a pod limited to limitBytes of ephemeral storage. The
+// pod is expected to be evicted by kubelet when the write exceeds the limit, no node condition is set.
+func EphemeralStorage(apiClient *clients.Settings, nsname, nodeName, image string, limitBytes, writeBytes int64,
+	maxDuration time.Duration) *Pressure {
+	podName := "pressure-ephemeral-storage"
+	pressure := &Pressure{
+		Node:        nodeName,
+		Description: fmt.Sprintf("write %d bytes of ephemeral storage limited to %d bytes", writeBytes, limitBytes),
+		Pods:        []string{podName},
+	}
+
+	pressure.apply = func() error {
+		if err := checkDuration(maxDuration); err != nil {
+			return err
+		}
+
+		limit := *resource.NewQuantity(limitBytes, resource.BinarySI)
+		command := fmt.Sprintf("dd if=/dev/zero of=%s bs=1M count=%d status=none; sleep infinity",
+			filepath.Join(fillMountPath, fillFileName), writeBytes/(1024*1024))
+
+		_, err := fillPod(apiClient, podName, nsname, nodeName, image, command, v1.StorageMediumDefault, nil,
+			v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceEphemeralStorage: limit},
+				Limits:   v1.ResourceList{v1.ResourceEphemeralStorage: limit},
+			}, maxDuration).Create()
+
+		return err
+	}
+
+	pressure.release = func() error {
+		return deletePods(apiClient, nsname, pressure.Pods)
+	}
+
+	return pressure
+}
+
+// NodeDisk allocates a file in the given host directory until the node filesystem available to kubelet is half
+// of the nodefs.available hard eviction threshold, which sets the DiskPressure node condition. The directory must
+// be on the kubelet root filesystem. The file removal is scheduled on the node after maxDuration before the file
+// is allocated, so the disk space is released even if the test is interrupted.
+func NodeDisk(apiClient *clients.Settings, nodeName, dir string, maxDuration time.Duration) *Pressure {
+	pressure := &Pressure{
+		Node:        nodeName,
+		Description: fmt.Sprintf("disk pressure in %s", dir),
+		Condition:   v1.NodeDiskPressure,
+	}
+
+	var fillFile string
+
+	pressure.apply = func() error {
+		if err := checkDuration(maxDuration); err != nil {
+			return err
+		}
+
+		stats, err := getNodeStats(apiClient, nodeName)
+		if err != nil {
+			return err
+		}
+
+		threshold, err := getEvictionThreshold(apiClient, nodeName, nodefsAvailable, stats.Node.Fs.CapacityBytes)
+		if err != nil {
+			return err
+		}
+
+		available := stats.Node.Fs.AvailableBytes
+		if available <= threshold {
+			return fmt.Errorf("node %s is already under disk pressure: %d bytes available", nodeName, available)
+		}
+
+		_, err = cmd.ExecCmd([]string{"chroot", "/rootfs", "mkdir", "-p", dir}, nodeName)
+		if err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+
+		err = checkSameFilesystem(nodeName, dir, kubeletRootDir)
+		if err != nil {
+			return err
+		}
+
+		fillFile = filepath.Join(dir, fmt.Sprintf("pressure-%d.img", time.Now().Unix()))
+
+		_, err = cmd.ExecCmd([]string{"chroot", "/rootfs", "systemd-run",
+			fmt.Sprintf("--on-active=%d", int(maxDuration.Seconds())), "rm", "-f", fillFile}, nodeName)
+		if err != nil {
+			return fmt.Errorf("failed to schedule %s removal: %w", fillFile, err)
+		}
+
+		fillBytes := available - threshold/2
+
+		glog.V(90).Infof("Allocating %d bytes in %s on node %s, eviction threshold %d bytes", fillBytes, fillFile,
+			nodeName, threshold)
+
+		_, err = cmd.ExecCmd([]string{"chroot", "/rootfs", "fallocate", "-l", strconv.FormatUint(fillBytes, 10),
+			fillFile}, nodeName)
+		if err != nil {
+			return fmt.Errorf("failed to allocate %s: %w", fillFile, err)
+		}
+
+		return nil
+	}
+
+	pressure.release = func() error {
+		if fillFile == "" {
+			return nil
+		}
+
+		_, err := cmd.ExecCmd([]string{"chroot", "/rootfs", "rm", "-f", fillFile}, nodeName)
+		if err != nil {
+			return fmt.Errorf("failed to remove %s: %w", fillFile, err)
+		}
+
+		fillFile = ""
+
+		return nil
+	}
+
+	return pressure
+}
+
+// Apply induces the pressure on the node.
+func (pressure *Pressure) Apply() error {
+	glog.V(90).Infof("Applying %s on node %s", pressure.Description, pressure.Node)
+
+	return pressure.apply()
+}
+
+// Release removes the source of the pressure. It can be called more than once, e.g. from a deferred cleanup.
+func (pressure *Pressure) Release() error {
+	glog.V(90).Infof("Releasing %s on node %s", pressure.Description, pressure.Node)
+
+	return pressure.release()
+}
+
+func fillPod(apiClient *clients.Settings, name, nsname, nodeName, image, command string, medium v1.StorageMedium,
+	sizeLimit *resource.Quantity, resources v1.ResourceRequirements, maxDuration time.Duration) *pod.Builder {
+	activeDeadline := int64(maxDuration.Seconds())
+
+	fillPod := pod.NewBuilder(apiClient, name, nsname, image).
+		RedefineDefaultCMD([]string{"/bin/sh", "-c", command}).
+		WithRestartPolicy(v1.RestartPolicyNever).
+		WithVolume(v1.Volume{
+			Name: "fill",
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{Medium: medium, SizeLimit: sizeLimit},
+			},
+		}).
+		DefineOnNode(nodeName)
+
+	fillPod.Definition.Spec.Containers[0].VolumeMounts = []v1.VolumeMount{{Name: "fill", MountPath: fillMountPath}}
+	fillPod.Definition.Spec.Containers[0].Resources = resources
+	fillPod.Definition.Spec.ActiveDeadlineSeconds = &activeDeadline
+
+	return fillPod
+}
+
+func stressResources(qos v1.PodQOSClass, memory resource.Quantity) v1.ResourceRequirements {
+	cpu := resource.MustParse("100m")
+
+	switch qos {
+	case v1.PodQOSGuaranteed:
+		return v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: cpu, v1.ResourceMemory: memory},
+			Limits:   v1.ResourceList{v1.ResourceCPU: cpu, v1.ResourceMemory: memory},
+		}
+	case v1.PodQOSBurstable:
+		return v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: cpu, v1.ResourceMemory: memory},
+		}
+	default:
+		return v1.ResourceRequirements{}
+	}
+}
+
+func checkDuration(maxDuration time.Duration) error {
+	if maxDuration <= 0 || maxDuration > MaxDuration {
+		return fmt.Errorf("pressure duration %s must be positive and at most %s", maxDuration, MaxDuration)
+	}
+
+	return nil
+}
+
+// checkSameFilesystem returns an error if the host directories are not on the same filesystem.
+func checkSameFilesystem(nodeName, dir, otherDir string) error {
+	var sources []string
+
+	for _, path := range []string{dir, otherDir} {
+		output, err := cmd.ExecCmd([]string{"chroot", "/rootfs", "findmnt", "-n", "-o", "SOURCE", "--target", path},
+			nodeName)
+		if err != nil {
+			return fmt.Errorf("failed to find the filesystem of %s: %w", path, err)
+		}
+
+		sources = append(sources, strings.TrimSpace(output))
+	}
+
+	if sources[0] != sources[1] {
+		return fmt.Errorf("%s is on %s, not on the %s filesystem %s", dir, sources[0], otherDir, sources[1])
+	}
+
+	return nil
+}
+
+// getMemAvailable returns the memory available to the kernel of the node, which excludes the reserved hugepages.
+func getMemAvailable(nodeName string) (uint64, error) {
+	output, err := cmd.ExecCmd([]string{"grep", "MemAvailable", "/proc/meminfo"}, nodeName)
+	if err != nil {
+		return 0, err
+	}
+
+	// MemAvailable:   12345678 kB
+	fields := strings.Fields(output)
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unexpected meminfo output %q", output)
+	}
+
+	kiloBytes, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected meminfo output %q: %w", output, err)
+	}
+
+	return kiloBytes * 1024, nil
+}
+
+func deletePods(apiClient *clients.Settings, nsname string, podNames []string) error {
+	for _, podName := range podNames {
+		fillPod, err := pod.Pull(apiClient, podName, nsname)
+		if err != nil {
+			glog.V(90).Infof("Pod %s not found in namespace %s: %s", podName, nsname, err)
+
+			continue
+		}
+
+		_, err = fillPod.DeleteAndWait(deleteTimeout)
+		if err != nil {
+			return fmt.Errorf("failed to delete pod %s: %w", podName, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package prober
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// Check is a single availability check. The context carries the probe timeout.
+type Check func(ctx context.Context) error
+
+// HTTPCheck returns a check requesting the URL and expecting a non error status code. Certificates are not
+// verified since the targets are usually exposed with the cluster self-signed certificates.
+func HTTPCheck(targetURL string) Check {
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			DisableKeepAlives: true,
+		},
+	}
+
+	return func(ctx context.Context) error {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+		if err != nil {
+			return err
+		}
+
+		response, err := client.Do(request)
+		if err != nil {
+			return err
+		}
+
+		defer response.Body.Close()
+
+		if response.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("%s returned %s", targetURL, response.Status)
+		}
+
+		return nil
+	}
+}
+
+// TCPCheck returns a check opening a TCP connection to the address.
+func TCPCheck(address string) Check {
+	dialer := &net.Dialer{}
+
+	return func(ctx context.Context) error {
+		connection, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+
+		return connection.Close()
+	}
+}
+
+// APICheck returns a check requesting the kube-apiserver readiness endpoint.
+func APICheck(apiClient *clients.Settings) Check {
+	return func(ctx context.Context) error {
+		_, err := apiClient.K8sClient.Discovery().RESTClient().Get().AbsPath("/readyz").DoRaw(ctx)
+
+		return err
+	}
+}
+
+// PodsReadyCheck returns a check failing when a pod of the namespace is not ready, completed pods aside, e.g. to
+// probe the continuity of a workload. The check also fails while the API is unavailable.
+func PodsReadyCheck(apiClient *clients.Settings, nsname string) Check {
+	return func(ctx context.Context) error {
+		podList, err := apiClient.CoreV1Interface.Pods(nsname).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		if len(podList.Items) == 0 {
+			return fmt.Errorf("no pod found in namespace %s", nsname)
+		}
+
+		for _, workloadPod := range podList.Items {
+			if workloadPod.Status.Phase == corev1.PodSucceeded {
+				continue
+			}
+
+			if !isPodReady(&workloadPod) {
+				return fmt.Errorf("pod %s/%s is not ready", nsname, workloadPod.Name)
+			}
+		}
+
+		return nil
+	}
+}
+
+// PodHTTPCheck returns a check requesting the URL with curl from an in-cluster pod, e.g. to probe a ClusterIP
+// service. The exec request and curl are bounded by the probe timeout. The check also fails while the API is
+// unavailable since it relies on pod exec.
+func PodHTTPCheck(apiClient *clients.Settings, probePod *pod.Builder, targetURL string) Check {
+	return func(ctx context.Context) error {
+		command := []string{"curl", "-ksf", "-o", "/dev/null", targetURL}
+		if deadline, found := ctx.Deadline(); found {
+			command = append(command, "-m", fmt.Sprintf("%.3f", time.Until(deadline).Seconds()))
+		}
+
+		request := apiClient.CoreV1Interface.RESTClient().Post().
+			Namespace(probePod.Definition.Namespace).
+			Resource("pods").
+			Name(probePod.Definition.Name).
+			SubResource("exec").
+			VersionedParams(&corev1.PodExecOptions{
+				Container: probePod.Definition.Spec.Containers[0].Name,
+				Command:   command,
+				Stdout:    true,
+				Stderr:    true,
+			}, scheme.ParameterCodec)
+
+		executor, err := remotecommand.NewSPDYExecutor(apiClient.Config, http.MethodPost, request.URL())
+		if err != nil {
+			return err
+		}
+
+		var stdout, stderr bytes.Buffer
+
+		err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, stderr.String())
+		}
+
+		return nil
+	}
+}
+
+func isPodReady(workloadPod *corev1.Pod) bool {
+	for _, condition := range workloadPod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// ParseTarget returns the check of a target given as an URL: http:// and https:// targets are probed with HTTP
+// requests, tcp://host:port targets with TCP connections.
+func ParseTarget(target string) (Check, error) {
+	parsedURL, err := url.Parse(strings.TrimSpace(target))
+	if err != nil {
+		return nil, fmt.Errorf("invalid probe target %q: %w", target, err)
+	}
+
+	switch parsedURL.Scheme {
+	case "http", "https":
+		return HTTPCheck(parsedURL.String()), nil
+	case "tcp":
+		return TCPCheck(parsedURL.Host), nil
+	default:
+		return nil, fmt.Errorf("unsupported probe target %q, expected http, https or tcp scheme", target)
+	}
+}
@@ -0,0 +1,224 @@
+package prober
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	// MetricDowntime is the name of the total downtime metric.
+	MetricDowntime = "availability_downtime_seconds"
+	// MetricWindows is the name of the unavailability windows count metric.
+	MetricWindows = "availability_windows"
+	// MetricLongestWindow is the name of the longest unavailability window metric.
+	MetricLongestWindow = "availability_longest_window_seconds"
+)
+
+// Window is a period during which the target failed the probes.
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Error is the first error of the window.
+	Error string `json:"error"`
+}
+
+// Duration returns the length of the window.
+func (window Window) Duration() time.Duration {
+	return window.End.Sub(window.Start)
+}
+
+// Result is the outcome of a probing session.
+type Result struct {
+	Name     string        `json:"name"`
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Probes   int           `json:"probes"`
+	Failures int           `json:"failures"`
+	Windows  []Window      `json:"windows"`
+	Downtime time.Duration `json:"downtime"`
+}
+
+// LongestWindow returns the duration of the longest unavailability window.
+func (result *Result) LongestWindow() time.Duration {
+	var longest time.Duration
+
+	for _, window := range result.Windows {
+		if window.Duration() > longest {
+			longest = window.Duration()
+		}
+	}
+
+	return longest
+}
+
+// Metrics returns the downtime, windows count and longest window metrics of the result named after the given tag.
+func (result *Result) Metrics(tag string) map[string]string {
+	return map[string]string{
+		fmt.Sprintf("%s_%s_%s", MetricDowntime, result.Name, tag): strconv.FormatFloat(
+			result.Downtime.Seconds(), 'f', 3, 64),
+		fmt.Sprintf("%s_%s_%s", MetricWindows, result.Name, tag): strconv.Itoa(len(result.Windows)),
+		fmt.Sprintf("%s_%s_%s", MetricLongestWindow, result.Name, tag): strconv.FormatFloat(
+			result.LongestWindow().Seconds(), 'f', 3, 64),
+	}
+}
+
+// CheckDowntime returns an error if the total downtime is above maxDowntime. A zero maxDowntime disables the check.
+func (result *Result) CheckDowntime(maxDowntime time.Duration) error {
+	if maxDowntime > 0 && result.Downtime > maxDowntime {
+		return fmt.Errorf("%s was unavailable for %s in %d windows, above %s", result.Name, result.Downtime,
+			len(result.Windows), maxDowntime)
+	}
+
+	return nil
+}
+
+// Prober runs a check at a fixed interval in the background and records the unavailability windows.
+type Prober struct {
+	name     string
+	check    Check
+	interval time.Duration
+	timeout  time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+	mutex    sync.Mutex
+	result   Result
+	started  bool
+	stopped  bool
+	// current is the ongoing unavailability window, nil while the target is available.
+	current *Window
+	// now returns the current time, replaced in the unit tests.
+	now func() time.Time
+}
+
+// New returns a prober running the check every interval. Each check is given the interval as timeout, so that
+// a hanging target is accounted as unavailable rather than delaying the next probes.
+func New(name string, check Check, interval time.Duration) *Prober {
+	return &Prober{
+		name:     name,
+		check:    check,
+		interval: interval,
+		timeout:  interval,
+		now:      time.Now,
+	}
+}
+
+// WithTimeout sets the timeout of each check, e.g. when the interval is too short for the target to answer.
+func (prober *Prober) WithTimeout(timeout time.Duration) *Prober {
+	prober.timeout = timeout
+
+	return prober
+}
+
+// Start starts probing in the background.
+func (prober *Prober) Start() {
+	glog.V(90).Infof("Starting prober %s every %s", prober.name, prober.interval)
+
+	prober.mutex.Lock()
+	prober.stop = make(chan struct{})
+	prober.done = make(chan struct{})
+	prober.result = Result{Name: prober.name, Start: prober.now()}
+	prober.current = nil
+	prober.started = true
+	prober.stopped = false
+	prober.mutex.Unlock()
+
+	go func() {
+		defer close(prober.done)
+
+		ticker := time.NewTicker(prober.interval)
+		defer ticker.Stop()
+
+		for {
+			prober.probe()
+
+			select {
+			case <-prober.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops probing and returns the result. An ongoing unavailability window is closed at the time of the call.
+// Stopping an already stopped prober returns the same result and stopping a prober never started returns an empty
+// result.
+func (prober *Prober) Stop() *Result {
+	prober.mutex.Lock()
+	started, stopped := prober.started, prober.stopped
+	prober.stopped = true
+	prober.mutex.Unlock()
+
+	if !started {
+		return &Result{Name: prober.name}
+	}
+
+	if !stopped {
+		close(prober.stop)
+		<-prober.done
+	}
+
+	return prober.finish()
+}
+
+// finish closes the result at the current time on the first call and returns it.
+func (prober *Prober) finish() *Result {
+	prober.mutex.Lock()
+	defer prober.mutex.Unlock()
+
+	if prober.result.End.IsZero() {
+		prober.result.End = prober.now()
+
+		if prober.current != nil {
+			prober.closeWindow(prober.result.End)
+		}
+
+		glog.V(90).Infof("Prober %s stopped: %d probes, %d failures, downtime %s", prober.name,
+			prober.result.Probes, prober.result.Failures, prober.result.Downtime)
+	}
+
+	result := prober.result
+
+	return &result
+}
+
+func (prober *Prober) probe() {
+	ctx, cancel := context.WithTimeout(context.TODO(), prober.timeout)
+	defer cancel()
+
+	start := prober.now()
+	err := prober.check(ctx)
+
+	prober.mutex.Lock()
+	defer prober.mutex.Unlock()
+
+	prober.result.Probes++
+
+	if err != nil {
+		prober.result.Failures++
+
+		if prober.current == nil {
+			glog.V(90).Infof("Prober %s: target unavailable: %s", prober.name, err)
+
+			prober.current = &Window{Start: start, Error: err.Error()}
+		}
+
+		return
+	}
+
+	if prober.current != nil {
+		prober.closeWindow(start)
+	}
+}
+
+func (prober *Prober) closeWindow(end time.Time) {
+	prober.current.End = end
+	prober.result.Windows = append(prober.result.Windows, *prober.current)
+	prober.result.Downtime += prober.current.Duration()
+	prober.current = nil
+}
@@ -0,0 +1,184 @@
+package prober
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testInterval = 20 * time.Millisecond
+
+func TestHTTPCheck(t *testing.T) {
+	var failing atomic.Bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if failing.Load() {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	check := HTTPCheck(server.URL)
+
+	if err := check(context.TODO()); err != nil {
+		t.Fatalf("expected available server, got %s", err)
+	}
+
+	failing.Store(true)
+
+	if err := check(context.TODO()); err == nil {
+		t.Fatal("expected unavailable server on 503")
+	}
+}
+
+func TestHTTPCheckTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		time.Sleep(10 * testInterval)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.TODO(), testInterval)
+	defer cancel()
+
+	if err := HTTPCheck(server.URL)(ctx); err == nil {
+		t.Fatal("expected hanging server to time out")
+	}
+}
+
+func TestTCPCheck(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	address := listener.Addr().String()
+	check := TCPCheck(address)
+
+	if err := check(context.TODO()); err != nil {
+		t.Fatalf("expected listening address, got %s", err)
+	}
+
+	listener.Close()
+
+	if err := check(context.TODO()); err == nil {
+		t.Fatal("expected closed address to be unavailable")
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	for target, valid := range map[string]bool{
+		"http://example.com":   true,
+		"https://example.com":  true,
+		"tcp://127.0.0.1:6443": true,
+		"udp://127.0.0.1:53":   false,
+		"example.com":          false,
+	} {
+		_, err := ParseTarget(target)
+		if valid != (err == nil) {
+			t.Errorf("target %s: expected valid %t, got error %v", target, valid, err)
+		}
+	}
+}
+
+func TestProberWindows(t *testing.T) {
+	var failing bool
+
+	clock := time.Unix(0, 0)
+	prober := New("test", func(ctx context.Context) error {
+		if failing {
+			return errors.New("unavailable")
+		}
+
+		return nil
+	}, time.Second)
+	prober.now = func() time.Time {
+		return clock
+	}
+	prober.started = true
+	prober.result = Result{Name: prober.name, Start: clock}
+
+	// probe runs one probe per second of the given period, as the ticker would.
+	probe := func(period int) {
+		for second := 0; second < period; second++ {
+			prober.probe()
+			clock = clock.Add(time.Second)
+		}
+	}
+
+	for _, outage := range []int{10, 5} {
+		probe(5)
+		failing = true
+		probe(outage)
+		failing = false
+	}
+
+	probe(5)
+
+	result := prober.finish()
+
+	if len(result.Windows) != 2 {
+		t.Fatalf("expected 2 unavailability windows, got %d: %+v", len(result.Windows), result.Windows)
+	}
+
+	if result.Downtime != 15*time.Second {
+		t.Errorf("expected downtime of 15s, got %s", result.Downtime)
+	}
+
+	if result.LongestWindow() != 10*time.Second {
+		t.Errorf("expected longest window of 10s, got %s", result.LongestWindow())
+	}
+
+	if result.Probes != 30 || result.Failures != 15 {
+		t.Errorf("expected 30 probes and 15 failures, got %d and %d", result.Probes, result.Failures)
+	}
+
+	if err := result.CheckDowntime(time.Hour); err != nil {
+		t.Errorf("expected downtime below an hour, got %s", err)
+	}
+
+	if err := result.CheckDowntime(time.Second); err == nil {
+		t.Error("expected downtime above a second")
+	}
+}
+
+func TestProberStopBeforeStart(t *testing.T) {
+	result := New("test", func(ctx context.Context) error {
+		return nil
+	}, testInterval).Stop()
+
+	if result.Name != "test" || result.Probes != 0 || len(result.Windows) != 0 {
+		t.Errorf("expected an empty result, got %+v", result)
+	}
+}
+
+func TestProberOngoingWindow(t *testing.T) {
+	prober := New("test", func(ctx context.Context) error {
+		return errors.New("unavailable")
+	}, testInterval)
+	prober.Start()
+
+	time.Sleep(5 * testInterval)
+
+	result := prober.Stop()
+
+	if len(result.Windows) != 1 || result.Windows[0].End != result.End {
+		t.Fatalf("expected one window closed at the end of the probing, got %+v", result.Windows)
+	}
+
+	if result.Windows[0].Error != "unavailable" {
+		t.Errorf("expected window error to be recorded, got %q", result.Windows[0].Error)
+	}
+
+	if again := prober.Stop(); again.End != result.End || len(again.Windows) != 1 {
+		t.Errorf("expected stopping twice to return the same result, got %+v", again)
+	}
+}
@@ -1,10 +1,12 @@
 package reboot
 
 import (
+	"context"
 	"time"
 
 	"github.com/golang/glog"
 	"github.com/openshift-kni/eco-goinfra/pkg/deployment"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
 	"github.com/openshift-kni/eco-goinfra/pkg/pod"
 	"github.com/openshift-kni/eco-gosystem/tests/internal/cmd"
 	. "github.com/openshift-kni/eco-gosystem/tests/internal/inittools"
@@ -14,6 +16,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 // SoftRebootNode executes systemctl reboot on a node.
@@ -133,3 +136,32 @@ func KernelCrashKdump(nodeName string) error {
 
 	return nil
 }
+
+// WaitUntilNodeRebooted waits until the node reports a boot ID different from previousBootID and is Ready.
+// API errors are tolerated while waiting since the API may be unavailable during the reboot.
+func WaitUntilNodeRebooted(nodeName, previousBootID string, timeout time.Duration) error {
+	glog.V(90).Infof("Wait for node %s to reboot and become ready", nodeName)
+
+	return wait.PollUntilContextTimeout(
+		context.TODO(), 10*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+			node, err := nodes.Pull(APIClient, nodeName)
+			if err != nil {
+				glog.V(90).Infof("Failed to pull node %s: %s", nodeName, err)
+
+				return false, nil
+			}
+
+			if node.Object.Status.NodeInfo.BootID == previousBootID {
+				return false, nil
+			}
+
+			ready, err := node.IsReady()
+			if err != nil {
+				glog.V(90).Infof("Failed to get node %s readiness: %s", nodeName, err)
+
+				return false, nil
+			}
+
+			return ready, nil
+		})
+}
@@ -0,0 +1,187 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cmd"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubectl/pkg/drain"
+)
+
+const (
+	// MetricTotalTime is the ginkgo report metric of the duration of the whole scenario.
+	MetricTotalTime = "ranmetrics_shutdown_total_seconds"
+	// MetricPhaseTime is the ginkgo report metric of the duration of a phase of the scenario.
+	MetricPhaseTime = "ranmetrics_shutdown_phase_seconds"
+	pollInterval    = 10 * time.Second
+)
+
+// criticalPriorityClasses are the priority classes of the pods required by the platform, never drained.
+var criticalPriorityClasses = []string{"system-node-critical", "system-cluster-critical"}
+
+// criticalNamespacePrefixes are the prefixes of the platform namespaces whose pods are never drained.
+var criticalNamespacePrefixes = []string{"openshift-", "kube-"}
+
+// Phase is a timed step of the shutdown and restart scenario.
+type Phase struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Report holds the timing of the phases of the scenario run on a node.
+type Report struct {
+	Node   string  `json:"node"`
+	Phases []Phase `json:"phases"`
+}
+
+// NewReport returns an empty report of the node.
+func NewReport(nodeName string) *Report {
+	return &Report{Node: nodeName}
+}
+
+// Run runs a phase of the scenario and records its duration, including when it fails.
+func (report *Report) Run(name string, phase func() error) error {
+	glog.V(90).Infof("Running phase %s on node %s", name, report.Node)
+
+	start := time.Now()
+	err := phase()
+
+	report.Phases = append(report.Phases, Phase{Name: name, Duration: time.Since(start)})
+
+	if err != nil {
+		return fmt.Errorf("phase %s failed on node %s: %w", name, report.Node, err)
+	}
+
+	return nil
+}
+
+// Total returns the cumulated duration of the phases.
+func (report *Report) Total() time.Duration {
+	var total time.Duration
+
+	for _, phase := range report.Phases {
+		total += phase.Duration
+	}
+
+	return total
+}
+
+// Metrics returns the duration of every phase and the total duration of the scenario named after the given tag.
+func (report *Report) Metrics(tag string) map[string]string {
+	metrics := map[string]string{
+		fmt.Sprintf("%s_%s_%s", MetricTotalTime, report.Node, tag): strconv.FormatFloat(
+			report.Total().Seconds(), 'f', 0, 64),
+	}
+
+	for _, phase := range report.Phases {
+		metrics[fmt.Sprintf("%s_%s_%s_%s", MetricPhaseTime, phase.Name, report.Node, tag)] =
+			strconv.FormatFloat(phase.Duration.Seconds(), 'f', 0, 64)
+	}
+
+	return metrics
+}
+
+// DrainNonCritical evicts the pods of the node except the DaemonSet and static pods, the pods of the platform
+// namespaces and the pods with a critical priority class, which are needed until the node is shut down. The drain
+// progress is written to out.
+func DrainNonCritical(apiClient *clients.Settings, nodeName string, timeout time.Duration, out io.Writer) error {
+	drainHelper := &drain.Helper{
+		Ctx:                 context.TODO(),
+		Client:              apiClient.K8sClient,
+		Force:               true,
+		GracePeriodSeconds:  -1,
+		IgnoreAllDaemonSets: true,
+		DeleteEmptyDirData:  true,
+		Timeout:             timeout,
+		AdditionalFilters:   []drain.PodFilter{skipCritical},
+		Out:                 out,
+		ErrOut:              out,
+	}
+
+	glog.V(90).Infof("Draining non-critical pods of node %s", nodeName)
+
+	return drain.RunNodeDrain(drainHelper, nodeName)
+}
+
+// Shutdown gracefully shuts the node OS down after the given delay, rounded to minutes as done by shutdown -h.
+func Shutdown(nodeName string, delay time.Duration) error {
+	minutes := int(delay.Minutes())
+
+	glog.V(90).Infof("Shutting down node %s in %d minutes", nodeName, minutes)
+
+	_, err := cmd.ExecCmd([]string{"chroot", "/rootfs", "shutdown", "-h", strconv.Itoa(minutes)}, nodeName)
+
+	return err
+}
+
+// WaitForRestart waits until the node reports a boot ID different from previousBootID and is Ready. When
+// approveCSRs is true the pending kubelet CertificateSigningRequests of the node are approved while waiting, since
+// the kubelet client certificate may have expired while the node was powered off. The names of the approved requests
+// are returned. API errors are tolerated while waiting since the API is unavailable until the node has started.
+func WaitForRestart(apiClient *clients.Settings, nodeName, previousBootID string, approveCSRs bool,
+	timeout time.Duration) ([]string, error) {
+	var approved []string
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			if approveCSRs {
+				names, err := cluster.ApprovePendingCSRs(apiClient, nodeName)
+				if err != nil {
+					glog.V(90).Infof("Failed to approve pending CSRs: %s", err)
+
+					return false, nil
+				}
+
+				approved = append(approved, names...)
+			}
+
+			node, err := nodes.Pull(apiClient, nodeName)
+			if err != nil {
+				glog.V(90).Infof("Failed to pull node %s: %s", nodeName, err)
+
+				return false, nil
+			}
+
+			if node.Object.Status.NodeInfo.BootID == previousBootID {
+				return false, nil
+			}
+
+			ready, err := node.IsReady()
+			if err != nil {
+				return false, nil
+			}
+
+			return ready, nil
+		})
+	if err != nil {
+		return approved, fmt.Errorf("node %s did not restart within %s: %w", nodeName, timeout, err)
+	}
+
+	return approved, nil
+}
+
+func skipCritical(pod corev1.Pod) drain.PodDeleteStatus {
+	for _, priorityClass := range criticalPriorityClasses {
+		if pod.Spec.PriorityClassName == priorityClass {
+			return drain.MakePodDeleteStatusSkip()
+		}
+	}
+
+	for _, prefix := range criticalNamespacePrefixes {
+		if strings.HasPrefix(pod.Namespace, prefix) {
+			return drain.MakePodDeleteStatusSkip()
+		}
+	}
+
+	return drain.MakePodDeleteStatusOkay()
+}
@@ -0,0 +1,283 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// absent is the value reported for a field missing from one of the snapshots.
+const absent = "<absent>"
+
+// Expectation is the expected evolution of the snapshot fields matched by a rule.
+type Expectation string
+
+const (
+	// MustChange fields must differ between the snapshots, e.g. the cluster version after an upgrade.
+	MustChange Expectation = "must-change"
+	// MustNotChange fields must be identical in both snapshots, e.g. the cluster ID.
+	MustNotChange Expectation = "must-not-change"
+	// MayChange fields are reported when they differ but never fail the comparison.
+	MayChange Expectation = "may-change"
+)
+
+// Rule sets the expectation of the snapshot fields matched by its path. Paths are made of the JSON names of the
+// snapshot fields and of the keys of its collections separated by slashes, e.g. nodes/<node>/bootID, where * matches
+// any sequence of characters. A rule matches the field of its path and all the fields below it. When several rules
+// match a field the one with the longest path applies, fields matched by no rule must not change.
+type Rule struct {
+	Path   string
+	Expect Expectation
+}
+
+// Change is a field which differs between the snapshots, or a field expected to change which did not.
+type Change struct {
+	Path   string
+	Before string
+	After  string
+	Expect Expectation
+}
+
+// Report is the result of the comparison of two snapshots.
+type Report struct {
+	Changes    []Change
+	Violations []Change
+}
+
+// UpgradeRules are the expectations of a platform upgrade: the version and the components shipped with the
+// platform change while the identity of the cluster and of its nodes and the custom workloads must not.
+func UpgradeRules() []Rule {
+	return []Rule{
+		{Path: "taken", Expect: MayChange},
+		{Path: "version", Expect: MustChange},
+		{Path: "csvs", Expect: MayChange},
+		{Path: "nodes/*/bootID", Expect: MayChange},
+		{Path: "nodes/*/kubeletVersion", Expect: MayChange},
+		{Path: "nodes/*/osImage", Expect: MayChange},
+		{Path: "machineConfigs", Expect: MayChange},
+		{Path: "machineConfigPools", Expect: MayChange},
+		{Path: "certificates", Expect: MayChange},
+	}
+}
+
+// PlatformUpgradeRules are the expectations of an in-place platform upgrade through the ClusterVersion: the machine
+// config operator renders new configurations from the release and reboots every node to apply them, while the
+// identity of the cluster and of its nodes and the custom workloads must not change.
+func PlatformUpgradeRules() []Rule {
+	return []Rule{
+		{Path: "taken", Expect: MayChange},
+		{Path: "version", Expect: MustChange},
+		{Path: "csvs", Expect: MayChange},
+		{Path: "nodes/*/bootID", Expect: MustChange},
+		{Path: "nodes/*/kubeletVersion", Expect: MayChange},
+		{Path: "nodes/*/osImage", Expect: MayChange},
+		{Path: "machineConfigs", Expect: MayChange},
+		{Path: "machineConfigPools", Expect: MustChange},
+		{Path: "certificates", Expect: MayChange},
+	}
+}
+
+// RebootRules are the expectations of a reboot of the given nodes: their boot IDs change while the rest of the
+// cluster, including the boot IDs of the other nodes, must be restored.
+func RebootRules(nodeNames ...string) []Rule {
+	rules := []Rule{
+		{Path: "taken", Expect: MayChange},
+		{Path: "certificates", Expect: MayChange},
+	}
+
+	for _, nodeName := range nodeNames {
+		rules = append(rules, Rule{Path: fmt.Sprintf("nodes/%s/bootID", nodeName), Expect: MustChange})
+	}
+
+	return rules
+}
+
+// RollbackRules are the expectations of a rollback: the cluster is restored to its state before the upgrade, the
+// nodes having possibly rebooted into their previous stateroot.
+func RollbackRules() []Rule {
+	return []Rule{
+		{Path: "taken", Expect: MayChange},
+		{Path: "nodes/*/bootID", Expect: MayChange},
+		{Path: "certificates", Expect: MayChange},
+	}
+}
+
+// Diff compares the snapshots taken before and after an operation field by field according to the rules.
+func Diff(before, after *Snapshot, rules []Rule) (*Report, error) {
+	beforeFields, err := flatten(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := flatten(after)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool)
+
+	for path := range beforeFields {
+		paths[path] = true
+	}
+
+	for path := range afterFields {
+		paths[path] = true
+	}
+
+	matchers := compileRules(rules)
+	report := &Report{}
+
+	for path := range paths {
+		beforeValue, found := beforeFields[path]
+		if !found {
+			beforeValue = absent
+		}
+
+		afterValue, found := afterFields[path]
+		if !found {
+			afterValue = absent
+		}
+
+		change := Change{Path: path, Before: beforeValue, After: afterValue, Expect: matchers.expectation(path)}
+		changed := beforeValue != afterValue
+
+		if changed {
+			report.Changes = append(report.Changes, change)
+		}
+
+		switch change.Expect {
+		case MustChange:
+			if !changed {
+				report.Violations = append(report.Violations, change)
+			}
+		case MustNotChange:
+			if changed {
+				report.Violations = append(report.Violations, change)
+			}
+		case MayChange:
+		}
+	}
+
+	sortChanges(report.Changes)
+	sortChanges(report.Violations)
+
+	return report, nil
+}
+
+// String returns the violations and the changes, one per line.
+func (report *Report) String() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "Snapshot diff: %d violations, %d changes\n", len(report.Violations), len(report.Changes))
+
+	for _, violation := range report.Violations {
+		fmt.Fprintf(&builder, "VIOLATION %s %s: %s -> %s\n", violation.Expect, violation.Path, violation.Before,
+			violation.After)
+	}
+
+	for _, change := range report.Changes {
+		fmt.Fprintf(&builder, "changed %s: %s -> %s\n", change.Path, change.Before, change.After)
+	}
+
+	return builder.String()
+}
+
+// Failures returns the violations of the rules, one message per violation.
+func (report *Report) Failures() []string {
+	var failures []string
+
+	for _, violation := range report.Violations {
+		failures = append(failures, fmt.Sprintf("%s %s: %s -> %s", violation.Expect, violation.Path,
+			violation.Before, violation.After))
+	}
+
+	return failures
+}
+
+type ruleMatcher struct {
+	pattern *regexp.Regexp
+	length  int
+	expect  Expectation
+}
+
+type ruleMatchers []ruleMatcher
+
+func compileRules(rules []Rule) ruleMatchers {
+	var matchers ruleMatchers
+
+	for _, rule := range rules {
+		pattern := strings.ReplaceAll(regexp.QuoteMeta(rule.Path), `\*`, ".*")
+		matchers = append(matchers, ruleMatcher{
+			pattern: regexp.MustCompile("^" + pattern + "(/.*)?$"),
+			length:  len(rule.Path),
+			expect:  rule.Expect,
+		})
+	}
+
+	return matchers
+}
+
+// expectation returns the expectation of the longest rule matching the path, MustNotChange when none matches.
+func (matchers ruleMatchers) expectation(path string) Expectation {
+	expect := MustNotChange
+	longest := -1
+
+	for _, matcher := range matchers {
+		if matcher.length > longest && matcher.pattern.MatchString(path) {
+			expect = matcher.expect
+			longest = matcher.length
+		}
+	}
+
+	return expect
+}
+
+// flatten returns the leaf fields of the JSON representation of the snapshot keyed by their path. Lists are
+// compared as a whole.
+func flatten(snapshot *Snapshot) (map[string]string, error) {
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree map[string]interface{}
+
+	err = json.Unmarshal(content, &tree)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]string)
+	flattenInto(fields, "", tree)
+
+	return fields, nil
+}
+
+func flattenInto(fields map[string]string, prefix string, value interface{}) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			path := key
+			if prefix != "" {
+				path = prefix + "/" + key
+			}
+
+			flattenInto(fields, path, child)
+		}
+	case string:
+		fields[prefix] = typed
+	case nil:
+		return
+	default:
+		content, _ := json.Marshal(typed)
+		fields[prefix] = string(content)
+	}
+}
+
+func sortChanges(changes []Change) {
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+}
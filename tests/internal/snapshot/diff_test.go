@@ -0,0 +1,175 @@
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRuleExpectation(t *testing.T) {
+	matchers := compileRules([]Rule{
+		{Path: "version", Expect: MustChange},
+		{Path: "nodes", Expect: MayChange},
+		{Path: "nodes/*/systemUUID", Expect: MustNotChange},
+		{Path: "nodes/*/bootID", Expect: MustChange},
+		{Path: "csvs/openshift-*", Expect: MayChange},
+	})
+
+	for _, testCase := range []struct {
+		path   string
+		expect Expectation
+	}{
+		{"version", MustChange},
+		{"versions", MustNotChange},
+		{"clusterID", MustNotChange},
+		{"nodes/sno/osImage", MayChange},
+		{"nodes/sno/bootID", MustChange},
+		{"nodes/sno/systemUUID", MustNotChange},
+		{"csvs/openshift-ptp/ptp-operator", MayChange},
+		{"csvs/custom/custom-operator", MustNotChange},
+	} {
+		if expect := matchers.expectation(testCase.path); expect != testCase.expect {
+			t.Errorf("path %s: expected %s, got %s", testCase.path, testCase.expect, expect)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	for _, testCase := range []struct {
+		name       string
+		mutate     func(after *Snapshot)
+		changes    []string
+		violations []string
+	}{
+		{
+			name:       "version not changed",
+			mutate:     func(after *Snapshot) {},
+			changes:    []string{"taken"},
+			violations: []string{"version"},
+		},
+		{
+			name: "upgrade",
+			mutate: func(after *Snapshot) {
+				after.Version = "4.15.1"
+				after.CSVs["openshift-ptp/ptp-operator"] = "4.15.0"
+				after.Nodes["sno"] = Node{SystemUUID: "uuid", BootID: "boot-2", Addresses: []string{"InternalIP=10.0.0.1"}}
+			},
+			changes: []string{"csvs/openshift-ptp/ptp-operator", "nodes/sno/bootID", "taken", "version"},
+		},
+		{
+			name: "cluster ID changed",
+			mutate: func(after *Snapshot) {
+				after.Version = "4.15.1"
+				after.ClusterID = "other"
+			},
+			changes:    []string{"clusterID", "taken", "version"},
+			violations: []string{"clusterID"},
+		},
+		{
+			name: "workload missing",
+			mutate: func(after *Snapshot) {
+				after.Version = "4.15.1"
+				delete(after.Workloads, "app/Deployment/server")
+			},
+			changes:    []string{"taken", "version", "workloads/app/Deployment/server"},
+			violations: []string{"workloads/app/Deployment/server"},
+		},
+		{
+			name: "node address changed",
+			mutate: func(after *Snapshot) {
+				after.Version = "4.15.1"
+				after.Nodes["sno"] = Node{SystemUUID: "uuid", BootID: "boot-1", Addresses: []string{"InternalIP=10.0.0.2"}}
+			},
+			changes:    []string{"nodes/sno/addresses", "taken", "version"},
+			violations: []string{"nodes/sno/addresses"},
+		},
+	} {
+		before := testSnapshot()
+		after := testSnapshot()
+		after.Taken = before.Taken.Add(time.Hour)
+		testCase.mutate(after)
+
+		report, err := Diff(before, after, UpgradeRules())
+		if err != nil {
+			t.Fatalf("%s: %s", testCase.name, err)
+		}
+
+		if changes := changePaths(report.Changes); !reflect.DeepEqual(changes, testCase.changes) {
+			t.Errorf("%s: expected changes %v, got %v", testCase.name, testCase.changes, changes)
+		}
+
+		if violations := changePaths(report.Violations); !reflect.DeepEqual(violations, testCase.violations) {
+			t.Errorf("%s: expected violations %v, got %v", testCase.name, testCase.violations, violations)
+		}
+	}
+}
+
+func TestDiffAbsentField(t *testing.T) {
+	before := testSnapshot()
+	after := testSnapshot()
+	after.Version = "4.15.1"
+
+	delete(after.CSVs, "openshift-ptp/ptp-operator")
+
+	report, err := Diff(before, after, UpgradeRules())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Change{Path: "csvs/openshift-ptp/ptp-operator", Before: "4.14.0", After: absent, Expect: MayChange}
+
+	if len(report.Changes) != 2 || report.Changes[0] != expected {
+		t.Errorf("expected change %+v, got %+v", expected, report.Changes)
+	}
+}
+
+func TestDiffRebootRules(t *testing.T) {
+	before := testSnapshot()
+	before.Nodes["worker"] = Node{SystemUUID: "uuid-2", BootID: "boot-2"}
+
+	after := testSnapshot()
+	after.Nodes["sno"] = Node{SystemUUID: "uuid", BootID: "boot-3", Addresses: []string{"InternalIP=10.0.0.1"}}
+	after.Nodes["worker"] = Node{SystemUUID: "uuid-2", BootID: "boot-4"}
+
+	report, err := Diff(before, after, RebootRules("sno"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if violations := changePaths(report.Violations); !reflect.DeepEqual(violations,
+		[]string{"nodes/worker/bootID"}) {
+		t.Errorf("expected the boot ID of the node not rebooted to be a violation, got %v", violations)
+	}
+
+	report, err = Diff(before, before, RebootRules("sno"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if violations := changePaths(report.Violations); !reflect.DeepEqual(violations, []string{"nodes/sno/bootID"}) {
+		t.Errorf("expected the unchanged boot ID of the rebooted node to be a violation, got %v", violations)
+	}
+}
+
+func testSnapshot() *Snapshot {
+	return &Snapshot{
+		Taken:     time.Unix(0, 0).UTC(),
+		ClusterID: "cluster",
+		Version:   "4.14.5",
+		CSVs:      map[string]string{"openshift-ptp/ptp-operator": "4.14.0"},
+		Nodes: map[string]Node{
+			"sno": {SystemUUID: "uuid", BootID: "boot-1", Addresses: []string{"InternalIP=10.0.0.1"}},
+		},
+		Workloads: map[string]string{"app/Deployment/server": "1/1"},
+	}
+}
+
+func changePaths(changes []Change) []string {
+	var paths []string
+
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+
+	return paths
+}
@@ -0,0 +1,311 @@
+package snapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/olm"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/certs"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// platformNamespacePrefixes are the prefixes of the namespaces created by the platform, excluded from the custom
+// namespaces and workloads.
+var platformNamespacePrefixes = []string{"openshift", "kube-", "default"}
+
+// Node is the identity of a node.
+type Node struct {
+	SystemUUID     string `json:"systemUUID"`
+	MachineID      string `json:"machineID"`
+	BootID         string `json:"bootID"`
+	KubeletVersion string `json:"kubeletVersion"`
+	OSImage        string `json:"osImage"`
+	// Addresses are the sorted addresses of the node, as type=address.
+	Addresses []string `json:"addresses"`
+}
+
+// Network is the cluster network configuration.
+type Network struct {
+	Type            string   `json:"type"`
+	ClusterNetworks []string `json:"clusterNetworks"`
+	ServiceNetworks []string `json:"serviceNetworks"`
+}
+
+// Snapshot is the state of a cluster. Every collection is keyed by the identity of its items so that two snapshots
+// can be compared item by item, see Diff.
+type Snapshot struct {
+	Taken     time.Time `json:"taken"`
+	ClusterID string    `json:"clusterID"`
+	Version   string    `json:"version"`
+	// CSVs are the versions of the ClusterServiceVersions, keyed by namespace/name without the version suffix.
+	CSVs  map[string]string `json:"csvs"`
+	Nodes map[string]Node   `json:"nodes"`
+	// MachineConfigs are the hashes of the MachineConfig specs, keyed by name.
+	MachineConfigs map[string]string `json:"machineConfigs"`
+	// MachineConfigPools are the rendered configurations of the pools, keyed by name.
+	MachineConfigPools map[string]string `json:"machineConfigPools"`
+	Network            Network           `json:"network"`
+	// Certificates are the expiry dates of the certificates managed by the platform, keyed by secret.
+	Certificates map[string]string `json:"certificates"`
+	// Namespaces are the phases of the custom namespaces, keyed by name.
+	Namespaces map[string]string `json:"namespaces"`
+	// Workloads are the ready and desired replicas of the deployments and statefulsets of the custom namespaces,
+	// keyed by namespace/kind/name.
+	Workloads map[string]string `json:"workloads"`
+	// PVBindings are the claims bound to the persistent volumes, keyed by volume name.
+	PVBindings map[string]string `json:"pvBindings"`
+}
+
+// Take captures the snapshot of the cluster.
+func Take(apiClient *clients.Settings) (*Snapshot, error) {
+	glog.V(90).Infof("Taking cluster snapshot")
+
+	var err error
+
+	snapshot := &Snapshot{Taken: time.Now()}
+
+	snapshot.ClusterID, err = cluster.GetClusterID(apiClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster ID: %w", err)
+	}
+
+	snapshot.Version, err = cluster.GetClusterVersion(apiClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster version: %w", err)
+	}
+
+	collectors := []struct {
+		name    string
+		collect func(*clients.Settings, *Snapshot) error
+	}{
+		{"CSVs", collectCSVs},
+		{"nodes", collectNodes},
+		{"MachineConfigs", collectMachineConfigs},
+		{"network", collectNetwork},
+		{"certificates", collectCertificates},
+		{"namespaces", collectNamespaces},
+		{"workloads", collectWorkloads},
+		{"PV bindings", collectPVBindings},
+	}
+
+	for _, collector := range collectors {
+		err = collector.collect(apiClient, snapshot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect %s: %w", collector.name, err)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// String returns the indented JSON representation of the snapshot.
+func (snapshot *Snapshot) String() string {
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(content)
+}
+
+func collectCSVs(apiClient *clients.Settings, snapshot *Snapshot) error {
+	csvList, err := olm.ListClusterServiceVersionInAllNamespaces(apiClient)
+	if err != nil {
+		return err
+	}
+
+	snapshot.CSVs = make(map[string]string)
+
+	for _, csv := range csvList {
+		if csv.Object.IsCopied() {
+			continue
+		}
+
+		snapshot.CSVs[csv.Object.Namespace+"/"+cluster.CSVBaseName(csv.Object.Name)] = csv.Object.Spec.Version.String()
+	}
+
+	return nil
+}
+
+func collectNodes(apiClient *clients.Settings, snapshot *Snapshot) error {
+	nodeList, err := apiClient.CoreV1Interface.Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	snapshot.Nodes = make(map[string]Node)
+
+	for _, node := range nodeList.Items {
+		var addresses []string
+
+		for _, address := range node.Status.Addresses {
+			addresses = append(addresses, fmt.Sprintf("%s=%s", address.Type, address.Address))
+		}
+
+		sort.Strings(addresses)
+
+		snapshot.Nodes[node.Name] = Node{
+			SystemUUID:     node.Status.NodeInfo.SystemUUID,
+			MachineID:      node.Status.NodeInfo.MachineID,
+			BootID:         node.Status.NodeInfo.BootID,
+			KubeletVersion: node.Status.NodeInfo.KubeletVersion,
+			OSImage:        node.Status.NodeInfo.OSImage,
+			Addresses:      addresses,
+		}
+	}
+
+	return nil
+}
+
+func collectMachineConfigs(apiClient *clients.Settings, snapshot *Snapshot) error {
+	machineConfigList, err := apiClient.MachineConfigs().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	snapshot.MachineConfigs = make(map[string]string)
+
+	for _, machineConfig := range machineConfigList.Items {
+		spec, err := json.Marshal(machineConfig.Spec)
+		if err != nil {
+			return err
+		}
+
+		hash := sha256.Sum256(spec)
+		snapshot.MachineConfigs[machineConfig.Name] = hex.EncodeToString(hash[:8])
+	}
+
+	poolList, err := apiClient.MachineConfigPools().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	snapshot.MachineConfigPools = make(map[string]string)
+
+	for _, pool := range poolList.Items {
+		snapshot.MachineConfigPools[pool.Name] = pool.Status.Configuration.Name
+	}
+
+	return nil
+}
+
+func collectNetwork(apiClient *clients.Settings, snapshot *Snapshot) error {
+	network, err := apiClient.ConfigV1Interface.Networks().Get(context.TODO(), "cluster", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	snapshot.Network.Type = network.Status.NetworkType
+	snapshot.Network.ServiceNetworks = network.Status.ServiceNetwork
+
+	for _, clusterNetwork := range network.Status.ClusterNetwork {
+		snapshot.Network.ClusterNetworks = append(snapshot.Network.ClusterNetworks,
+			fmt.Sprintf("%s/%d", clusterNetwork.CIDR, clusterNetwork.HostPrefix))
+	}
+
+	return nil
+}
+
+func collectCertificates(apiClient *clients.Settings, snapshot *Snapshot) error {
+	certificates, err := certs.ListManagedCertificates(apiClient)
+	if err != nil {
+		return err
+	}
+
+	snapshot.Certificates = make(map[string]string)
+
+	for _, certificate := range certificates {
+		snapshot.Certificates[strings.TrimPrefix(certificate.Source, "secret ")] =
+			certificate.NotAfter.Format(time.RFC3339)
+	}
+
+	return nil
+}
+
+func collectNamespaces(apiClient *clients.Settings, snapshot *Snapshot) error {
+	namespaceList, err := apiClient.Namespaces().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	snapshot.Namespaces = make(map[string]string)
+
+	for _, namespace := range namespaceList.Items {
+		if isCustomNamespace(namespace.Name) {
+			snapshot.Namespaces[namespace.Name] = string(namespace.Status.Phase)
+		}
+	}
+
+	return nil
+}
+
+func collectWorkloads(apiClient *clients.Settings, snapshot *Snapshot) error {
+	deploymentList, err := apiClient.Deployments("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	snapshot.Workloads = make(map[string]string)
+
+	for _, deployment := range deploymentList.Items {
+		if isCustomNamespace(deployment.Namespace) && deployment.Spec.Replicas != nil {
+			snapshot.Workloads[fmt.Sprintf("%s/Deployment/%s", deployment.Namespace, deployment.Name)] =
+				fmt.Sprintf("%d/%d", deployment.Status.ReadyReplicas, *deployment.Spec.Replicas)
+		}
+	}
+
+	statefulSetList, err := apiClient.StatefulSets("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, statefulSet := range statefulSetList.Items {
+		if isCustomNamespace(statefulSet.Namespace) && statefulSet.Spec.Replicas != nil {
+			snapshot.Workloads[fmt.Sprintf("%s/StatefulSet/%s", statefulSet.Namespace, statefulSet.Name)] =
+				fmt.Sprintf("%d/%d", statefulSet.Status.ReadyReplicas, *statefulSet.Spec.Replicas)
+		}
+	}
+
+	return nil
+}
+
+func collectPVBindings(apiClient *clients.Settings, snapshot *Snapshot) error {
+	pvList, err := apiClient.PersistentVolumes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	snapshot.PVBindings = make(map[string]string)
+
+	for _, persistentVolume := range pvList.Items {
+		var claim string
+
+		if claimRef := persistentVolume.Spec.ClaimRef; claimRef != nil {
+			claim = claimRef.Namespace + "/" + claimRef.Name
+		}
+
+		snapshot.PVBindings[persistentVolume.Name] = strings.TrimSpace(
+			fmt.Sprintf("%s %s", persistentVolume.Status.Phase, claim))
+	}
+
+	return nil
+}
+
+func isCustomNamespace(name string) bool {
+	for _, prefix := range platformNamespacePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
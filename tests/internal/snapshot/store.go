@@ -0,0 +1,81 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// snapshotsDir is the directory of the reports directory holding the snapshots, one subdirectory per run ID.
+const snapshotsDir = "snapshots"
+
+var (
+	// generatedRunID is the run ID used when none is configured, generated from the start time of the process.
+	generatedRunID = time.Now().UTC().Format("20060102-150405")
+	// logRunID logs the generated run ID once so that it can be configured to resume the run.
+	logRunID sync.Once
+)
+
+// RunID returns the configured run ID or, when none is configured, an ID generated once per process. Configure the
+// run ID to load the snapshots of a previous invocation, e.g. to validate an upgrade once it completed.
+func RunID(configured string) string {
+	if configured != "" {
+		return configured
+	}
+
+	logRunID.Do(func() {
+		glog.Infof("Using generated run ID %s, set it as run ID to load the snapshots of this run", generatedRunID)
+	})
+
+	return generatedRunID
+}
+
+// Save writes the snapshot as <reportsDir>/snapshots/<runID>/<name>.json and returns the path of the file.
+func (snapshot *Snapshot) Save(reportsDir, runID, name string) (string, error) {
+	dir := filepath.Join(reportsDir, snapshotsDir, runID)
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshots directory %s: %w", dir, err)
+	}
+
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, name+".json")
+
+	err = os.WriteFile(path, content, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to write snapshot %s: %w", path, err)
+	}
+
+	glog.V(90).Infof("Saved snapshot %s", path)
+
+	return path, nil
+}
+
+// Load reads the snapshot saved with the given run ID and name.
+func Load(reportsDir, runID, name string) (*Snapshot, error) {
+	path := filepath.Join(reportsDir, snapshotsDir, runID, name+".json")
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s of run %s: %w", name, runID, err)
+	}
+
+	var snapshot Snapshot
+
+	err = json.Unmarshal(content, &snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+
+	return &snapshot, nil
+}
@@ -0,0 +1,314 @@
+package soak
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/nodesampler"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ProbeRestarts is the name of the container restarts probe, which also counts the pods recreated or deleted.
+	ProbeRestarts = "restarts"
+	// ProbeOOMKills is the name of the OOM killed containers probe.
+	ProbeOOMKills = "oomkills"
+	// ProbeReadiness is the name of the pod readiness flaps probe.
+	ProbeReadiness = "readiness"
+	// ProbeNodePressure is the name of the node pressure conditions probe.
+	ProbeNodePressure = "node-pressure"
+	// ProbeLoadAvg is the name of the node load average probe.
+	ProbeLoadAvg = "loadavg"
+)
+
+// ErrFailureCriteria is returned by Run when one of the failure criteria is met.
+var ErrFailureCriteria = errors.New("soak failure criteria met")
+
+// Criteria are the failure criteria evaluated after every probe round. A negative value disables the criterion.
+type Criteria struct {
+	MaxRestarts               int
+	MaxOOMKills               int
+	MaxReadinessFlaps         int
+	MaxPressureEvents         int
+	MaxLoadAvg                float64
+	MaxConsecutiveProbeErrors int
+}
+
+// Config describes a soak run.
+type Config struct {
+	// Namespace is the namespace of the workload under soak.
+	Namespace string
+	// Duration is the total soak time, including the time spent by a previous run resumed from the checkpoint.
+	Duration time.Duration
+	// Interval is the time between two probe rounds.
+	Interval time.Duration
+	// CheckpointPath is the file the progress of the run is written to after every probe round.
+	CheckpointPath string
+	// Resume continues the run found in CheckpointPath instead of starting a new one.
+	Resume   bool
+	Criteria Criteria
+	// Output receives the hourly summaries. Nothing is written when it is nil.
+	Output io.Writer
+}
+
+// Run probes the workload and the nodes every interval for the configured duration. The failure criteria are
+// evaluated after every probe round and the run stops with ErrFailureCriteria as soon as one is met.
+func Run(apiClient *clients.Settings, config Config) (*State, error) {
+	if config.Duration <= 0 || config.Interval <= 0 {
+		return nil, fmt.Errorf("invalid soak duration %s or interval %s", config.Duration, config.Interval)
+	}
+
+	state, err := initState(config)
+	if err != nil {
+		return nil, err
+	}
+
+	glog.V(90).Infof("Starting soak of namespace %s for %s every %s", config.Namespace, config.Duration,
+		config.Interval)
+
+	endTime := state.StartTime.Add(config.Duration)
+	ticker := time.NewTicker(config.Interval)
+
+	defer ticker.Stop()
+
+	for {
+		lastHour := len(state.Hours)
+
+		failures := probeRound(apiClient, state, config)
+
+		if len(state.Hours) != lastHour && lastHour > 0 && config.Output != nil {
+			writeHour(config.Output, "hour", state.Hours[lastHour-1])
+		}
+
+		finished := !time.Now().Before(endTime)
+		state.Finished = finished || len(failures) > 0
+
+		err = state.save(config.CheckpointPath)
+		if err != nil {
+			return state, fmt.Errorf("failed to checkpoint soak progress: %w", err)
+		}
+
+		if len(failures) > 0 {
+			if config.Output != nil {
+				state.WriteSummary(config.Output)
+			}
+
+			return state, fmt.Errorf("%w: %v", ErrFailureCriteria, failures)
+		}
+
+		if finished {
+			if config.Output != nil {
+				state.WriteSummary(config.Output)
+			}
+
+			return state, nil
+		}
+
+		<-ticker.C
+	}
+}
+
+func initState(config Config) (*State, error) {
+	if !config.Resume {
+		return newState(config.Namespace, config.Duration), nil
+	}
+
+	state, err := LoadState(config.CheckpointPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return newState(config.Namespace, config.Duration), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if state.Namespace != config.Namespace || state.Finished {
+		glog.V(90).Infof("Checkpoint %s does not match a running soak, starting a new one", config.CheckpointPath)
+
+		return newState(config.Namespace, config.Duration), nil
+	}
+
+	glog.V(90).Infof("Resuming soak started at %s after %d iterations", state.StartTime, state.Iterations)
+
+	return state, nil
+}
+
+// probeRound runs all the probes once and returns the failure criteria met.
+func probeRound(apiClient *clients.Settings, state *State, config Config) []string {
+	probeTime := time.Now()
+	summary := state.currentHour(probeTime)
+	state.LastProbe = probeTime
+	state.Iterations++
+	summary.Probes++
+	state.Totals.Probes++
+
+	err := probeWorkload(apiClient, state, summary, config.Namespace, probeTime)
+	if err == nil {
+		err = probeNodes(apiClient, state, summary, probeTime)
+	}
+
+	if err != nil {
+		glog.V(90).Infof("Soak probe round %d failed: %s", state.Iterations, err)
+
+		summary.ProbeErrors++
+		state.Totals.ProbeErrors++
+		state.ConsecutiveProbeErrors++
+	} else {
+		state.ConsecutiveProbeErrors = 0
+	}
+
+	failures := evaluate(state, config.Criteria)
+	summary.FailureCriteria = append(summary.FailureCriteria, failures...)
+
+	return failures
+}
+
+func probeWorkload(
+	apiClient *clients.Settings, state *State, summary *HourlySummary, namespace string, probeTime time.Time) error {
+	podsList, err := pod.List(apiClient, namespace, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+	}
+
+	listed := make(map[string]bool)
+
+	for _, testPod := range podsList {
+		podName := testPod.Object.Name
+		podUID := string(testPod.Object.UID)
+		ready := isPodReady(testPod.Object)
+
+		listed[podName] = true
+
+		// A recreated pod restarts its containers from a zero restart count, which would hide the restart.
+		if previous, found := state.PodUIDs[podName]; found && previous != podUID {
+			state.addEvent(summary, Event{Time: probeTime, Probe: ProbeRestarts, Object: podName,
+				Message: fmt.Sprintf("pod recreated, UID changed from %s to %s", previous, podUID)})
+		}
+
+		state.PodUIDs[podName] = podUID
+
+		if previous, found := state.Ready[podName]; found && previous && !ready {
+			state.addEvent(summary, Event{Time: probeTime, Probe: ProbeReadiness, Object: podName,
+				Message: "pod is no longer ready"})
+		}
+
+		state.Ready[podName] = ready
+
+		for _, status := range testPod.Object.Status.ContainerStatuses {
+			key := podName + "/" + status.Name
+
+			if previous, found := state.Restarts[key]; found && status.RestartCount > previous {
+				state.addEvent(summary, Event{Time: probeTime, Probe: ProbeRestarts, Object: key,
+					Message: fmt.Sprintf("restart count increased from %d to %d", previous, status.RestartCount)})
+			}
+
+			state.Restarts[key] = status.RestartCount
+
+			terminated := status.LastTerminationState.Terminated
+			if terminated != nil && terminated.Reason == "OOMKilled" &&
+				!state.OOMKills[key].Equal(terminated.FinishedAt.Time) {
+				state.addEvent(summary, Event{Time: probeTime, Probe: ProbeOOMKills, Object: key,
+					Message: fmt.Sprintf("container OOM killed at %s", terminated.FinishedAt.Format(time.RFC3339))})
+				state.OOMKills[key] = terminated.FinishedAt.Time
+			}
+		}
+	}
+
+	// A deleted pod is replaced by a pod of another name, e.g. for deployments.
+	for podName, podUID := range state.PodUIDs {
+		if !listed[podName] {
+			state.addEvent(summary, Event{Time: probeTime, Probe: ProbeRestarts, Object: podName,
+				Message: fmt.Sprintf("pod deleted, UID %s", podUID)})
+			delete(state.PodUIDs, podName)
+			delete(state.Ready, podName)
+		}
+	}
+
+	return nil
+}
+
+func probeNodes(apiClient *clients.Settings, state *State, summary *HourlySummary, probeTime time.Time) error {
+	nodeList, err := nodes.List(apiClient, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	for _, node := range nodeList {
+		nodeName := node.Object.Name
+
+		for _, condition := range node.Object.Status.Conditions {
+			if condition.Type != v1.NodeMemoryPressure && condition.Type != v1.NodeDiskPressure &&
+				condition.Type != v1.NodePIDPressure {
+				continue
+			}
+
+			key := nodeName + "/" + string(condition.Type)
+			underPressure := condition.Status == v1.ConditionTrue
+
+			if underPressure && !state.Pressure[key] {
+				state.addEvent(summary, Event{Time: probeTime, Probe: ProbeNodePressure, Object: key,
+					Message: condition.Message})
+			}
+
+			state.Pressure[key] = underPressure
+		}
+
+		sample, err := nodesampler.Collect(apiClient, nodeName)
+		if err != nil {
+			return fmt.Errorf("failed to sample node %s: %w", nodeName, err)
+		}
+
+		if sample.LoadAvg1 > summary.MaxLoadAvg {
+			summary.MaxLoadAvg = sample.LoadAvg1
+			summary.MaxLoadAvgNode = nodeName
+		}
+
+		if sample.LoadAvg1 > state.Totals.MaxLoadAvg {
+			state.Totals.MaxLoadAvg = sample.LoadAvg1
+			state.Totals.MaxLoadAvgNode = nodeName
+		}
+	}
+
+	return nil
+}
+
+func evaluate(state *State, criteria Criteria) []string {
+	var failures []string
+
+	checkMax := func(name string, value, limit int) {
+		if limit >= 0 && value > limit {
+			failures = append(failures, fmt.Sprintf("%s: %d above %d", name, value, limit))
+		}
+	}
+
+	checkMax(ProbeRestarts, state.Totals.Restarts, criteria.MaxRestarts)
+	checkMax(ProbeOOMKills, state.Totals.OOMKills, criteria.MaxOOMKills)
+	checkMax(ProbeReadiness, state.Totals.ReadinessFlaps, criteria.MaxReadinessFlaps)
+	checkMax(ProbeNodePressure, state.Totals.PressureEvents, criteria.MaxPressureEvents)
+	checkMax("consecutive probe errors", state.ConsecutiveProbeErrors, criteria.MaxConsecutiveProbeErrors)
+
+	if criteria.MaxLoadAvg >= 0 && state.Totals.MaxLoadAvg > criteria.MaxLoadAvg {
+		failures = append(failures, fmt.Sprintf("%s: %.2f on node %s above %.2f", ProbeLoadAvg,
+			state.Totals.MaxLoadAvg, state.Totals.MaxLoadAvgNode, criteria.MaxLoadAvg))
+	}
+
+	return failures
+}
+
+func isPodReady(podObject *v1.Pod) bool {
+	for _, condition := range podObject.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,171 @@
+package soak
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Event is a degradation observed by a probe.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Probe   string    `json:"probe"`
+	Object  string    `json:"object"`
+	Message string    `json:"message"`
+}
+
+// HourlySummary aggregates the probe results over an hour of soak.
+type HourlySummary struct {
+	Hour            int       `json:"hour"`
+	Start           time.Time `json:"start"`
+	Probes          int       `json:"probes"`
+	ProbeErrors     int       `json:"probeErrors"`
+	Restarts        int       `json:"restarts"`
+	OOMKills        int       `json:"oomKills"`
+	ReadinessFlaps  int       `json:"readinessFlaps"`
+	PressureEvents  int       `json:"pressureEvents"`
+	MaxLoadAvg      float64   `json:"maxLoadAvg"`
+	MaxLoadAvgNode  string    `json:"maxLoadAvgNode,omitempty"`
+	FailureCriteria []string  `json:"failureCriteria,omitempty"`
+}
+
+// State is the progress of a soak run. It is checkpointed to disk after every probe so that an interrupted run
+// can be resumed and inspected.
+type State struct {
+	Namespace  string    `json:"namespace"`
+	StartTime  time.Time `json:"startTime"`
+	LastProbe  time.Time `json:"lastProbe"`
+	Duration   string    `json:"duration"`
+	Iterations int       `json:"iterations"`
+	Finished   bool      `json:"finished"`
+	// ConsecutiveProbeErrors is the number of probe rounds in a row which could not complete.
+	ConsecutiveProbeErrors int `json:"consecutiveProbeErrors"`
+	// Restarts keeps the last observed restart count of each container, keyed by pod/container name.
+	Restarts map[string]int32 `json:"restarts"`
+	// PodUIDs keeps the UID of each pod, keyed by pod name, to detect the pods recreated or deleted.
+	PodUIDs map[string]string `json:"podUIDs"`
+	// Ready keeps the last observed readiness of each pod.
+	Ready map[string]bool `json:"ready"`
+	// OOMKills keeps the termination time of the OOM kills already reported, keyed by pod/container name.
+	OOMKills map[string]time.Time `json:"oomKills"`
+	// Pressure keeps the node conditions currently reporting pressure, keyed by node/condition.
+	Pressure map[string]bool `json:"pressure"`
+	Totals   HourlySummary   `json:"totals"`
+	Hours    []HourlySummary `json:"hours"`
+	Events   []Event         `json:"events"`
+}
+
+func newState(namespace string, duration time.Duration) *State {
+	return &State{
+		Namespace: namespace,
+		StartTime: time.Now(),
+		Duration:  duration.String(),
+		Restarts:  map[string]int32{},
+		PodUIDs:   map[string]string{},
+		Ready:     map[string]bool{},
+		OOMKills:  map[string]time.Time{},
+		Pressure:  map[string]bool{},
+	}
+}
+
+// LoadState reads a checkpoint written by a previous soak run.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &State{}
+
+	err = json.Unmarshal(data, state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse soak checkpoint %s: %w", path, err)
+	}
+
+	// Checkpoints written before the pod UIDs were tracked.
+	if state.PodUIDs == nil {
+		state.PodUIDs = map[string]string{}
+	}
+
+	return state, nil
+}
+
+func (state *State) save(path string) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that an interrupted run never leaves a truncated checkpoint.
+	tmpPath := path + ".tmp"
+
+	err = os.WriteFile(tmpPath, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// currentHour returns the summary of the hour the probe time belongs to, starting a new one if needed.
+func (state *State) currentHour(probeTime time.Time) *HourlySummary {
+	hour := int(probeTime.Sub(state.StartTime) / time.Hour)
+
+	if len(state.Hours) == 0 || state.Hours[len(state.Hours)-1].Hour != hour {
+		state.Hours = append(state.Hours, HourlySummary{
+			Hour:  hour,
+			Start: state.StartTime.Add(time.Duration(hour) * time.Hour),
+		})
+	}
+
+	return &state.Hours[len(state.Hours)-1]
+}
+
+func (state *State) addEvent(summary *HourlySummary, event Event) {
+	state.Events = append(state.Events, event)
+
+	switch event.Probe {
+	case ProbeRestarts:
+		summary.Restarts++
+		state.Totals.Restarts++
+	case ProbeOOMKills:
+		summary.OOMKills++
+		state.Totals.OOMKills++
+	case ProbeReadiness:
+		summary.ReadinessFlaps++
+		state.Totals.ReadinessFlaps++
+	case ProbeNodePressure:
+		summary.PressureEvents++
+		state.Totals.PressureEvents++
+	}
+}
+
+// WriteSummary writes a human readable summary of the soak run, one line per hour.
+func (state *State) WriteSummary(writer io.Writer) {
+	fmt.Fprintf(writer, "soak summary for namespace %s, started %s, %d iterations\n",
+		state.Namespace, state.StartTime.Format(time.RFC3339), state.Iterations)
+
+	for _, summary := range state.Hours {
+		writeHour(writer, "hour", summary)
+	}
+
+	writeHour(writer, "total", state.Totals)
+}
+
+func writeHour(writer io.Writer, label string, summary HourlySummary) {
+	fmt.Fprintf(writer, "%s %d: probes=%d errors=%d restarts=%d oomkills=%d readinessflaps=%d pressure=%d "+
+		"maxload=%.2f(%s)\n", label, summary.Hour, summary.Probes, summary.ProbeErrors, summary.Restarts,
+		summary.OOMKills, summary.ReadinessFlaps, summary.PressureEvents, summary.MaxLoadAvg, summary.MaxLoadAvgNode)
+
+	for _, criterion := range summary.FailureCriteria {
+		fmt.Fprintf(writer, "  failed: %s\n", criterion)
+	}
+}
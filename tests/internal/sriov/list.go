@@ -11,7 +11,17 @@ import (
 
 // NetworkInfo structure to store pod network information.
 type NetworkInfo struct {
-	Name string `json:"name"`
+	Name       string      `json:"name"`
+	Interface  string      `json:"interface"`
+	DeviceInfo *DeviceInfo `json:"device-info,omitempty"`
+}
+
+// DeviceInfo structure to store the device information reported for a pod network attachment.
+type DeviceInfo struct {
+	Type string `json:"type"`
+	Pci  struct {
+		PciAddress string `json:"pci-address"`
+	} `json:"pci"`
 }
 
 // ListNetworksByDeviceType returns a list of sriov networks matching the policies
@@ -53,25 +63,29 @@ func ListNetworksByDeviceType(
 	return devNetworks, nil
 }
 
-// ExtractNetworkNames returns the name of the networks based on the pods
+// ExtractNetworks returns the secondary networks based on the pods
 // network status annotations.
-func ExtractNetworkNames(jsonData string) ([]string, error) {
+func ExtractNetworks(jsonData string) ([]NetworkInfo, error) {
 	var networkInfo []NetworkInfo
 
+	if jsonData == "" {
+		return nil, nil
+	}
+
 	// Unmarshal the JSON data into the networkInfo slice.
 	err := json.Unmarshal([]byte(jsonData), &networkInfo)
 	if err != nil {
 		return nil, err
 	}
 
-	// Extract the interface names into a separate slice.
-	var networkNames []string
+	// Skip the cluster default network.
+	var networks []NetworkInfo
 
 	for _, info := range networkInfo {
 		if info.Name != "ovn-kubernetes" {
-			networkNames = append(networkNames, info.Name)
+			networks = append(networks, info)
 		}
 	}
 
-	return networkNames, nil
+	return networks, nil
 }
@@ -0,0 +1,130 @@
+package timesync
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sourceRegex matches the lines of chronyc -n sources, e.g.
+// ^* 192.168.1.1                   2   6   377    34   +123us[ +456us] +/-   12ms.
+var sourceRegex = regexp.MustCompile(`^([\^=#])([*+\-?x~])\s+(\S+)\s+(\d+)\s+(-?\d+)\s+([0-7]+)\s`)
+
+// parseTracking parses the output of chronyc tracking:
+//
+//	Reference ID    : C0A80101 (192.168.1.1)
+//	Stratum         : 3
+//	System time     : 0.000012345 seconds fast of NTP time
+//	Last offset     : -0.000001234 seconds
+//	RMS offset      : 0.000012345 seconds
+//	Leap status     : Normal
+func parseTracking(output string) (*Tracking, error) {
+	tracking := &Tracking{}
+	fieldsFound := map[string]bool{}
+
+	for _, line := range strings.Split(strings.ReplaceAll(output, "\r", ""), "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		var err error
+
+		switch key {
+		case "Reference ID":
+			tracking.ReferenceID = value
+		case "Stratum":
+			tracking.Stratum, err = strconv.Atoi(value)
+		case "System time":
+			tracking.SystemOffset, err = parseSystemTime(value)
+		case "Last offset":
+			tracking.LastOffset, err = parseSeconds(value)
+		case "RMS offset":
+			tracking.RMSOffset, err = parseSeconds(value)
+		case "Leap status":
+			tracking.LeapStatus = value
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse chronyc tracking line %q: %w", line, err)
+		}
+
+		fieldsFound[key] = true
+	}
+
+	for _, key := range []string{"Stratum", "System time", "Leap status"} {
+		if !fieldsFound[key] {
+			return nil, fmt.Errorf("%s not found in chronyc tracking output", key)
+		}
+	}
+
+	return tracking, nil
+}
+
+// parseSources parses the output of chronyc -n sources, skipping the header lines.
+func parseSources(output string) []Source {
+	var sources []Source
+
+	for _, line := range strings.Split(strings.ReplaceAll(output, "\r", ""), "\n") {
+		match := sourceRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		stratum, _ := strconv.Atoi(match[4])
+		reach, _ := strconv.ParseUint(match[6], 8, 16)
+
+		sources = append(sources, Source{
+			Mode:    match[1],
+			State:   match[2],
+			Address: match[3],
+			Stratum: stratum,
+			Reach:   uint16(reach),
+		})
+	}
+
+	return sources
+}
+
+// parseSystemTime parses the system clock offset, e.g. "0.000012345 seconds fast of NTP time". A clock slow of NTP
+// time has a negative offset.
+func parseSystemTime(value string) (time.Duration, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 3 {
+		return 0, fmt.Errorf("unexpected system time %q", value)
+	}
+
+	offset, err := parseSeconds(fields[0] + " " + fields[1])
+	if err != nil {
+		return 0, err
+	}
+
+	if fields[2] == "slow" {
+		offset = -offset
+	}
+
+	return offset, nil
+}
+
+// parseSeconds parses a value in seconds, e.g. "-0.000001234 seconds".
+func parseSeconds(value string) (time.Duration, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 2 || fields[1] != "seconds" {
+		return 0, fmt.Errorf("unexpected value in seconds %q", value)
+	}
+
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(math.Round(seconds * float64(time.Second))), nil
+}
@@ -0,0 +1,170 @@
+package timesync
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cmd"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// MetricOffset is the name of the system clock offset metric.
+	MetricOffset = "timesync_offset_us"
+	// MetricSyncTime is the name of the time to synchronization metric.
+	MetricSyncTime = "timesync_sync_time_seconds"
+	// LeapNotSynchronised is the leap status reported while the clock is not synchronized.
+	LeapNotSynchronised = "Not synchronised"
+	// SourceSelected is the state of the source the clock is synchronized to.
+	SourceSelected = "*"
+	pollInterval   = 10 * time.Second
+)
+
+// Tracking is the chronyc tracking report of a node.
+type Tracking struct {
+	ReferenceID string `json:"referenceID"`
+	Stratum     int    `json:"stratum"`
+	// SystemOffset is the offset of the system clock, negative when the clock is slow of NTP time.
+	SystemOffset time.Duration `json:"systemOffset"`
+	LastOffset   time.Duration `json:"lastOffset"`
+	RMSOffset    time.Duration `json:"rmsOffset"`
+	LeapStatus   string        `json:"leapStatus"`
+}
+
+// Source is a time source listed by chronyc sources.
+type Source struct {
+	// Mode is ^ for a server, = for a peer and # for a local reference clock.
+	Mode string `json:"mode"`
+	// State is * for the selected source, + for a combined source and -, ?, x or ~ for the others.
+	State   string `json:"state"`
+	Address string `json:"address"`
+	Stratum int    `json:"stratum"`
+	Reach   uint16 `json:"reach"`
+}
+
+// Status is the time synchronization status of a node.
+type Status struct {
+	Node     string        `json:"node"`
+	Tracking *Tracking     `json:"tracking"`
+	Sources  []Source      `json:"sources"`
+	SyncTime time.Duration `json:"syncTime"`
+}
+
+// Synchronized returns true if chrony reports the clock synchronized to a selected source.
+func (status *Status) Synchronized() bool {
+	if status.Tracking == nil || status.Tracking.LeapStatus == LeapNotSynchronised ||
+		status.Tracking.Stratum == 0 || status.Tracking.Stratum >= 16 {
+		return false
+	}
+
+	for _, source := range status.Sources {
+		if source.State == SourceSelected {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Metrics returns the system clock offset and time to synchronization metrics named after the given tag.
+func (status *Status) Metrics(tag string) map[string]string {
+	metrics := map[string]string{
+		fmt.Sprintf("%s_%s_%s", MetricSyncTime, status.Node, tag): strconv.FormatFloat(
+			status.SyncTime.Seconds(), 'f', 0, 64),
+	}
+
+	if status.Tracking != nil {
+		metrics[fmt.Sprintf("%s_%s_%s", MetricOffset, status.Node, tag)] = strconv.FormatInt(
+			status.Tracking.SystemOffset.Microseconds(), 10)
+	}
+
+	return metrics
+}
+
+// IsChronyActive returns true if chronyd runs on the node. Nodes synchronized by PTP usually disable it.
+func IsChronyActive(nodeName string) (bool, error) {
+	output, err := cmd.ExecCmd([]string{"chroot", "/rootfs", "/bin/sh", "-c",
+		"systemctl is-active chronyd || true"}, nodeName)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(output) == "active", nil
+}
+
+// GetStatus reads chronyc tracking and chronyc sources on the node.
+func GetStatus(nodeName string) (*Status, error) {
+	output, err := cmd.ExecCmd([]string{"chroot", "/rootfs", "chronyc", "tracking"}, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	tracking, err := parseTracking(output)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err = cmd.ExecCmd([]string{"chroot", "/rootfs", "chronyc", "-n", "sources"}, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Status{Node: nodeName, Tracking: tracking, Sources: parseSources(output)}, nil
+}
+
+// WaitForSync waits until the node clock is synchronized with an absolute offset of at most maxOffset and returns
+// the status, with the time it took since the given start. Exec errors are tolerated while waiting since the node
+// may be recovering from a disruption. On timeout the last status read is returned along with the last exec error.
+func WaitForSync(nodeName string, maxOffset time.Duration, start time.Time, timeout time.Duration) (*Status, error) {
+	var (
+		status  *Status
+		lastErr error
+	)
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			current, err := GetStatus(nodeName)
+			if err != nil {
+				glog.V(90).Infof("Failed to get time synchronization status of node %s: %s", nodeName, err)
+
+				lastErr = err
+
+				return false, nil
+			}
+
+			status = current
+
+			return status.Synchronized() && absDuration(status.Tracking.SystemOffset) <= maxOffset, nil
+		})
+	if err != nil {
+		if lastErr != nil {
+			err = fmt.Errorf("%w, last error: %w", err, lastErr)
+		}
+
+		if status != nil && status.Tracking != nil {
+			return status, fmt.Errorf("time not synchronized on node %s within %s, leap status %q, offset %s: %w",
+				nodeName, timeout, status.Tracking.LeapStatus, status.Tracking.SystemOffset, err)
+		}
+
+		return status, fmt.Errorf("time not synchronized on node %s within %s: %w", nodeName, timeout, err)
+	}
+
+	status.SyncTime = time.Since(start)
+
+	glog.V(90).Infof("Time synchronized on node %s after %s, offset %s", nodeName, status.SyncTime,
+		status.Tracking.SystemOffset)
+
+	return status, nil
+}
+
+func absDuration(duration time.Duration) time.Duration {
+	if duration < 0 {
+		return -duration
+	}
+
+	return duration
+}
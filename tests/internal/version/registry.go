@@ -0,0 +1,80 @@
+package version
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/onsi/ginkgo/v2"
+)
+
+// Component is a versioned part of the system under test.
+type Component string
+
+const (
+	// OCP is the OpenShift version of the cluster of the default kubeconfig, e.g. the spoke.
+	OCP Component = "OCP"
+	// HubOCP is the OpenShift version of the hub cluster.
+	HubOCP Component = "hub OCP"
+	// ACM is the version of the advanced cluster management operator of the hub.
+	ACM Component = "ACM"
+	// ZTP is the version of the ZTP site generator of the hub.
+	ZTP Component = "ZTP"
+	// TALM is the version of the topology aware lifecycle manager of the hub.
+	TALM Component = "TALM"
+)
+
+var (
+	registryMutex sync.RWMutex
+	registry      = make(map[Component]string)
+)
+
+// Register records the version of a component, once discovered by the suite. An empty version records a component
+// of unknown version, considered newer than any version.
+func Register(component Component, version string) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	glog.V(90).Infof("Registering %s version %q", component, version)
+
+	registry[component] = version
+}
+
+// unregister removes the version recorded for a component.
+func unregister(component Component) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	delete(registry, component)
+}
+
+// Lookup returns the version registered for the component.
+func Lookup(component Component) (string, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	version, found := registry[component]
+
+	return version, found
+}
+
+// Is tells whether the registered version of the component satisfies the constraint expression, e.g. to select
+// the behavior expected from the component. A component not registered is considered of unknown version. It panics
+// when the expression is invalid.
+func Is(component Component, expression string) bool {
+	version, _ := Lookup(component)
+
+	return Check(version, expression)
+}
+
+// RequireVersion skips the current spec when the registered version of the component does not satisfy the
+// constraint expression, e.g. RequireVersion(version.TALM, ">=4.12"). As with Is, a component not registered is
+// considered of unknown version. It panics when the expression is invalid.
+func RequireVersion(component Component, expression string) {
+	ginkgo.GinkgoHelper()
+
+	if !Is(component, expression) {
+		version, _ := Lookup(component)
+		ginkgo.Skip(fmt.Sprintf("%s version %s does not satisfy %s", component, version, expression))
+	}
+}
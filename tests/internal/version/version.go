@@ -0,0 +1,168 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+// Parse parses an OCP, operator or ZTP version, e.g. 4.14, v4.14.2, 4.15.0-rc.2 or 4.14.0-202310201027+build. Missing
+// minor and patch numbers are set to zero and the build metadata is ignored.
+func Parse(version string) (semver.Version, error) {
+	parsed, err := semver.ParseTolerant(strings.TrimSpace(version))
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("invalid version %q: %w", version, err)
+	}
+
+	parsed.Build = nil
+
+	return parsed, nil
+}
+
+var (
+	// operators are the comparison operators of the constraint terms, the two characters operators first.
+	operators = []string{">=", "<=", "!=", "==", ">", "<", "="}
+	// operatorSpaces matches the spaces between an operator and its version, e.g. in ">= 4.12".
+	operatorSpaces = regexp.MustCompile(`([<>=!]=?)\s+`)
+)
+
+// term is a comparison of a version with the version of a constraint, e.g. >=4.12.
+type term struct {
+	operator string
+	version  semver.Version
+}
+
+// Constraint is a set of version ranges: a version satisfies the constraint when it satisfies every term of one
+// of the ranges.
+type Constraint struct {
+	expression string
+	ranges     [][]term
+}
+
+// ParseConstraint parses a constraint expression made of ranges separated by ||, each range being made of terms
+// separated by commas or spaces, e.g. ">=4.12, <4.16" or "<4.11 || >=4.14". A term is an operator among >=, >, <=,
+// <, = or == and != followed by a version, = being assumed when no operator is given.
+func ParseConstraint(expression string) (*Constraint, error) {
+	constraint := &Constraint{expression: expression}
+
+	for _, rangeExpression := range strings.Split(operatorSpaces.ReplaceAllString(expression, "$1"), "||") {
+		var terms []term
+
+		for _, termExpression := range strings.FieldsFunc(rangeExpression, func(char rune) bool {
+			return char == ',' || char == ' '
+		}) {
+			parsed, err := parseTerm(termExpression)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %w", expression, err)
+			}
+
+			terms = append(terms, parsed)
+		}
+
+		if len(terms) == 0 {
+			return nil, fmt.Errorf("invalid constraint %q: empty range", expression)
+		}
+
+		constraint.ranges = append(constraint.ranges, terms)
+	}
+
+	return constraint, nil
+}
+
+// MustParseConstraint parses a constraint expression and panics when invalid, for the constraints hardcoded in
+// the tests.
+func MustParseConstraint(expression string) *Constraint {
+	constraint, err := ParseConstraint(expression)
+	if err != nil {
+		panic(err)
+	}
+
+	return constraint
+}
+
+// String returns the expression of the constraint.
+func (constraint *Constraint) String() string {
+	return constraint.expression
+}
+
+// Check tells whether the version satisfies the constraint. A version which cannot be parsed, e.g. empty for a
+// site generator image tagged latest, is considered newer than any version: it satisfies the ranges without upper
+// bound. The pre-release of the version is ignored when compared to a version without pre-release, so that
+// 4.15.0-rc.2 satisfies >=4.15 and does not satisfy <4.15.
+func (constraint *Constraint) Check(version string) bool {
+	parsed, err := Parse(version)
+
+	for _, terms := range constraint.ranges {
+		satisfied := true
+
+		for _, term := range terms {
+			if err != nil {
+				satisfied = satisfied && (term.operator == ">" || term.operator == ">=" || term.operator == "!=")
+
+				continue
+			}
+
+			satisfied = satisfied && term.check(parsed)
+		}
+
+		if satisfied {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Check tells whether the version satisfies the constraint expression, see Constraint.Check. It panics when the
+// expression is invalid.
+func Check(version, expression string) bool {
+	return MustParseConstraint(expression).Check(version)
+}
+
+func parseTerm(expression string) (term, error) {
+	operator := "="
+
+	for _, candidate := range operators {
+		if strings.HasPrefix(expression, candidate) {
+			operator = candidate
+
+			break
+		}
+	}
+
+	version, err := Parse(strings.TrimPrefix(expression, operator))
+	if err != nil {
+		return term{}, err
+	}
+
+	if operator == "==" {
+		operator = "="
+	}
+
+	return term{operator: operator, version: version}, nil
+}
+
+func (term term) check(version semver.Version) bool {
+	if len(term.version.Pre) == 0 {
+		version.Pre = nil
+	}
+
+	comparison := version.Compare(term.version)
+
+	switch term.operator {
+	case ">=":
+		return comparison >= 0
+	case ">":
+		return comparison > 0
+	case "<=":
+		return comparison <= 0
+	case "<":
+		return comparison < 0
+	case "!=":
+		return comparison != 0
+	default:
+		return comparison == 0
+	}
+}
@@ -0,0 +1,118 @@
+package version
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	for _, testCase := range []struct {
+		version  string
+		expected string
+		valid    bool
+	}{
+		{"4.14", "4.14.0", true},
+		{"v4.14.2", "4.14.2", true},
+		{" 4.14.2 ", "4.14.2", true},
+		{"4.15.0-rc.2", "4.15.0-rc.2", true},
+		{"4.14.0-202310201027+build", "4.14.0-202310201027", true},
+		{"", "", false},
+		{"latest", "", false},
+	} {
+		parsed, err := Parse(testCase.version)
+		if testCase.valid != (err == nil) {
+			t.Errorf("version %q: expected valid %t, got error %v", testCase.version, testCase.valid, err)
+
+			continue
+		}
+
+		if testCase.valid && parsed.String() != testCase.expected {
+			t.Errorf("version %q: expected %s, got %s", testCase.version, testCase.expected, parsed)
+		}
+	}
+}
+
+func TestParseConstraint(t *testing.T) {
+	for expression, valid := range map[string]bool{
+		">=4.12":           true,
+		">= 4.12":          true,
+		">=4.12, <4.16":    true,
+		">=4.12 <4.16":     true,
+		"<4.11 || >=4.14":  true,
+		"4.14":             true,
+		"==4.14.0-rc.1":    true,
+		"":                 false,
+		" ":                false,
+		">=":               false,
+		">=4.x":            false,
+		"=>4.12":           false,
+		"4.12 ||":          false,
+		">=4.12,, <4.16 |": false,
+	} {
+		_, err := ParseConstraint(expression)
+		if valid != (err == nil) {
+			t.Errorf("constraint %q: expected valid %t, got error %v", expression, valid, err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	for _, testCase := range []struct {
+		version    string
+		expression string
+		expected   bool
+	}{
+		{"4.12.0", ">=4.12", true},
+		{"4.12", ">=4.12", true},
+		{"4.13.5", ">= 4.12", true},
+		{"4.11.9", ">=4.12", false},
+		{"4.12.0", ">4.12", false},
+		{"4.14.2", ">=4.12, <4.16", true},
+		{"4.16.0", ">=4.12, <4.16", false},
+		{"4.10.1", "<4.11 || >=4.14", true},
+		{"4.12.1", "<4.11 || >=4.14", false},
+		{"4.14.0", "4.14", true},
+		{"4.14.1", "==4.14", false},
+		{"4.14.1", "!=4.14", true},
+		{"4.11.0", "<=4.11", true},
+		// The pre-release is ignored against a constraint without pre-release.
+		{"4.15.0-rc.2", ">=4.15", true},
+		{"4.15.0-rc.2", "<4.15", false},
+		{"4.14.0-202310201027", ">=4.14", true},
+		// The pre-releases are compared when the constraint has one.
+		{"4.15.0-rc.2", ">=4.15.0-rc.3", false},
+		{"4.15.0-rc.3", ">=4.15.0-rc.2", true},
+		{"4.15.0", ">4.15.0-rc.2", true},
+		// A version which cannot be parsed is newer than any version.
+		{"", ">=4.12", true},
+		{"latest", ">4.12, !=4.13", true},
+		{"", "<4.16", false},
+		{"", "4.14", false},
+	} {
+		if satisfied := Check(testCase.version, testCase.expression); satisfied != testCase.expected {
+			t.Errorf("version %q with constraint %q: expected %t, got %t", testCase.version, testCase.expression,
+				testCase.expected, satisfied)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	component := Component("test")
+
+	t.Cleanup(func() { unregister(component) })
+
+	if _, found := Lookup(component); found {
+		t.Fatalf("expected %s not to be registered", component)
+	}
+
+	if !Is(component, ">=4.12") {
+		t.Errorf("expected %s of unknown version to satisfy >=4.12", component)
+	}
+
+	Register(component, "4.11.3")
+
+	if registered, found := Lookup(component); !found || registered != "4.11.3" {
+		t.Errorf("expected %s version 4.11.3 to be registered, got %q", component, registered)
+	}
+
+	if Is(component, ">=4.12") {
+		t.Errorf("expected %s 4.11.3 not to satisfy >=4.12", component)
+	}
+}
@@ -0,0 +1,36 @@
+package workload
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/deployment"
+	"github.com/openshift-kni/eco-goinfra/pkg/nad"
+	"github.com/openshift-kni/eco-goinfra/pkg/namespace"
+	"github.com/openshift-kni/eco-goinfra/pkg/sriov"
+	"github.com/openshift-kni/eco-goinfra/pkg/statefulset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CleanNamespace removes all the workload objects inside the namespace, the namespace itself and the sriov networks
+// whose NetworkNamespace spec matches the namespace.
+func CleanNamespace(apiClient *clients.Settings, sriovOperatorNamespace, nsname string,
+	cleanTimeout time.Duration) error {
+	err := namespace.NewBuilder(apiClient, nsname).
+		CleanObjects(cleanTimeout, deployment.GetGVR(), statefulset.GetGVR(), nad.GetGVR())
+	if err != nil {
+		glog.V(100).Infof("Failed to clean up objects in namespace: %s", nsname)
+
+		return err
+	}
+
+	err = namespace.NewBuilder(apiClient, nsname).DeleteAndWait(cleanTimeout)
+	if err != nil {
+		glog.V(100).Infof("Failed to remove namespace: %s", nsname)
+
+		return err
+	}
+
+	return sriov.CleanAllNetworksByTargetNamespace(apiClient, sriovOperatorNamespace, nsname, metav1.ListOptions{})
+}
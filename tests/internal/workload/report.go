@@ -0,0 +1,90 @@
+package workload
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// CheckReadiness is the name of the pod readiness check.
+	CheckReadiness = "readiness"
+	// CheckRestarts is the name of the container restart count delta check.
+	CheckRestarts = "restarts"
+	// CheckSriovNetdevice is the name of the SR-IOV netdevice attachments check.
+	CheckSriovNetdevice = "sriov-netdevice"
+	// CheckSriovVfio is the name of the SR-IOV vfio-pci attachments check.
+	CheckSriovVfio = "sriov-vfio"
+	// CheckHugepages is the name of the hugepages allocation check.
+	CheckHugepages = "hugepages"
+	// CheckExclusiveCPUs is the name of the exclusive CPU set check.
+	CheckExclusiveCPUs = "exclusive-cpus"
+)
+
+// CheckResult is the outcome of a single check run against a pod.
+type CheckResult struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message"`
+}
+
+// PodReport holds the results of all the checks run against a pod.
+type PodReport struct {
+	Name   string        `json:"name"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Report is the structured per-pod result of a workload validation.
+type Report struct {
+	Namespace string       `json:"namespace"`
+	Pods      []*PodReport `json:"pods"`
+}
+
+// Passed returns true if all the checks of all the pods passed.
+func (report *Report) Passed() bool {
+	return len(report.Failures()) == 0
+}
+
+// Failures returns a line per failed check in the form "pod/check: message".
+func (report *Report) Failures() []string {
+	var failures []string
+
+	for _, podReport := range report.Pods {
+		for _, check := range podReport.Checks {
+			if !check.Passed {
+				failures = append(failures, fmt.Sprintf("%s/%s: %s", podReport.Name, check.Name, check.Message))
+			}
+		}
+	}
+
+	return failures
+}
+
+// String returns a human readable representation of the report.
+func (report *Report) String() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "workload validation report for namespace %s\n", report.Namespace)
+
+	for _, podReport := range report.Pods {
+		fmt.Fprintf(&builder, "  pod %s\n", podReport.Name)
+
+		for _, check := range podReport.Checks {
+			status := "PASS"
+			if !check.Passed {
+				status = "FAIL"
+			}
+
+			fmt.Fprintf(&builder, "    [%s] %s: %s\n", status, check.Name, check.Message)
+		}
+	}
+
+	return builder.String()
+}
+
+func (podReport *PodReport) add(name string, passed bool, format string, args ...interface{}) {
+	podReport.Checks = append(podReport.Checks, CheckResult{
+		Name:    name,
+		Passed:  passed,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
@@ -0,0 +1,419 @@
+package workload
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/sriov"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
+)
+
+const (
+	// NetworkStatusAnnotation is the pod annotation reporting the status of the pod network attachments.
+	NetworkStatusAnnotation = "k8s.v1.cni.cncf.io/network-status"
+	// execRetries is the number of attempts made when a command executed in a pod returns an empty output.
+	execRetries = 3
+	// hugepagesResourcePrefix is the prefix of the hugepages resource names.
+	hugepagesResourcePrefix = "hugepages-"
+	cpusetCmd               = "cat /sys/fs/cgroup/cpuset.cpus.effective 2>/dev/null || " +
+		"cat /sys/fs/cgroup/cpuset/cpuset.cpus"
+	hugetlbCmd = "cat /sys/fs/cgroup/hugetlb.%[1]s.max 2>/dev/null || " +
+		"cat /sys/fs/cgroup/hugetlb/hugetlb.%[1]s.limit_in_bytes"
+)
+
+// Baseline keeps the container restart counts of the pods in a namespace, keyed by pod/container name.
+type Baseline map[string]int32
+
+// Options controls the thresholds used when validating a workload.
+type Options struct {
+	// MaxRestartDelta is the number of container restarts tolerated since the baseline was captured.
+	MaxRestartDelta int32
+}
+
+// sriovNetworks keeps the SR-IOV network names grouped by device type.
+type sriovNetworks struct {
+	netdevice []string
+	vfio      []string
+}
+
+// CaptureBaseline records the current container restart counts of all the pods in a namespace.
+func CaptureBaseline(apiClient *clients.Settings, nsname string) (Baseline, error) {
+	podsList, err := pod.List(apiClient, nsname, metav1.ListOptions{})
+	if err != nil {
+		glog.V(100).Infof("Failed to list pods in namespace %s: %s", nsname, err)
+
+		return nil, err
+	}
+
+	baseline := Baseline{}
+
+	for _, testPod := range podsList {
+		for _, status := range testPod.Object.Status.ContainerStatuses {
+			baseline[restartKey(testPod.Object.Name, status.Name)] = status.RestartCount
+		}
+	}
+
+	return baseline, nil
+}
+
+// Validate verifies the integrity of every pod running in a namespace: readiness, container restarts since the
+// baseline, SR-IOV netdevice and vfio-pci attachments, hugepages allocation and exclusive CPU sets.
+// A nil baseline skips the restart count check. The returned error is only set when the validation could not run.
+func Validate(apiClient *clients.Settings, nsname string, baseline Baseline, options Options) (*Report, error) {
+	podsList, err := pod.List(apiClient, nsname, metav1.ListOptions{})
+	if err != nil {
+		glog.V(100).Infof("Failed to list pods in namespace %s: %s", nsname, err)
+
+		return nil, err
+	}
+
+	networks, err := listSriovNetworks(apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Namespace: nsname}
+	exclusiveCPUs := map[string]map[string]cpuset.CPUSet{}
+
+	for _, testPod := range podsList {
+		podReport := &PodReport{Name: testPod.Object.Name}
+		report.Pods = append(report.Pods, podReport)
+
+		if testPod.Object.Status.Phase == v1.PodSucceeded {
+			podReport.add(CheckReadiness, true, "pod completed")
+
+			continue
+		}
+
+		checkRestarts(testPod, baseline, options.MaxRestartDelta, podReport)
+
+		if !checkReadiness(testPod, podReport) {
+			continue
+		}
+
+		checkSriovNetdevice(testPod, networks.netdevice, podReport)
+		checkSriovVfio(testPod, networks.vfio, podReport)
+		checkHugepages(testPod, podReport)
+		checkExclusiveCPUs(testPod, exclusiveCPUs, podReport)
+	}
+
+	checkExclusiveCPUsOverlap(report, exclusiveCPUs)
+
+	return report, nil
+}
+
+func listSriovNetworks(apiClient *clients.Settings) (*sriovNetworks, error) {
+	netdeviceNetworks, err := sriov.ListNetworksByDeviceType(apiClient, "netdevice")
+	if err != nil {
+		return nil, fmt.Errorf("error when retrieving sriov networks using netdevice driver: %w", err)
+	}
+
+	vfioNetworks, err := sriov.ListNetworksByDeviceType(apiClient, "vfio-pci")
+	if err != nil {
+		return nil, fmt.Errorf("error when retrieving sriov networks using vfio-pci driver: %w", err)
+	}
+
+	return &sriovNetworks{netdevice: netdeviceNetworks, vfio: vfioNetworks}, nil
+}
+
+func checkReadiness(testPod *pod.Builder, podReport *PodReport) bool {
+	for _, condition := range testPod.Object.Status.Conditions {
+		if condition.Type == v1.PodReady && condition.Status == v1.ConditionTrue {
+			podReport.add(CheckReadiness, true, "pod is ready")
+
+			return true
+		}
+	}
+
+	podReport.add(CheckReadiness, false, "pod is not ready, phase %s: %s",
+		testPod.Object.Status.Phase, testPod.Object.Status.Message)
+
+	return false
+}
+
+func checkRestarts(testPod *pod.Builder, baseline Baseline, maxDelta int32, podReport *PodReport) {
+	if baseline == nil {
+		podReport.add(CheckRestarts, true, "no baseline captured, restart count not checked")
+
+		return
+	}
+
+	var exceeded []string
+
+	for _, status := range testPod.Object.Status.ContainerStatuses {
+		// Pods which did not exist when the baseline was captured are compared against zero restarts.
+		delta := status.RestartCount - baseline[restartKey(testPod.Object.Name, status.Name)]
+		if delta > maxDelta {
+			exceeded = append(exceeded, fmt.Sprintf("%s restarted %d times", status.Name, delta))
+		}
+	}
+
+	if len(exceeded) > 0 {
+		podReport.add(CheckRestarts, false, "%s, tolerated %d", strings.Join(exceeded, ", "), maxDelta)
+
+		return
+	}
+
+	podReport.add(CheckRestarts, true, "restart count delta within %d", maxDelta)
+}
+
+func checkSriovNetdevice(testPod *pod.Builder, netdeviceNetworks []string, podReport *PodReport) {
+	attachments, err := podAttachments(testPod, netdeviceNetworks)
+	if err != nil {
+		podReport.add(CheckSriovNetdevice, false, "error when retrieving pod network attachments: %s", err)
+
+		return
+	}
+
+	if len(attachments) == 0 {
+		return
+	}
+
+	output, err := execWithRetry(testPod, []string{"ls", "--color=never", "/sys/class/net"}, "")
+	if err != nil {
+		podReport.add(CheckSriovNetdevice, false, "error when listing pod interfaces: %s", err)
+
+		return
+	}
+
+	interfaces := strings.Fields(output)
+
+	var missing []string
+
+	for _, attachment := range attachments {
+		if !contains(interfaces, attachment.Interface) {
+			missing = append(missing, fmt.Sprintf("%s(%s)", attachment.Interface, attachment.Name))
+		}
+	}
+
+	if len(missing) > 0 {
+		podReport.add(CheckSriovNetdevice, false, "interfaces %v not visible in pod, found %v", missing, interfaces)
+
+		return
+	}
+
+	podReport.add(CheckSriovNetdevice, true, "%d netdevice attachments visible", len(attachments))
+}
+
+func checkSriovVfio(testPod *pod.Builder, vfioNetworks []string, podReport *PodReport) {
+	attachments, err := podAttachments(testPod, vfioNetworks)
+	if err != nil {
+		podReport.add(CheckSriovVfio, false, "error when retrieving pod network attachments: %s", err)
+
+		return
+	}
+
+	if len(attachments) == 0 {
+		return
+	}
+
+	output, err := execWithRetry(testPod, []string{"ls", "--color=never", "/dev/vfio"}, "")
+	if err != nil {
+		podReport.add(CheckSriovVfio, false, "error when listing /dev/vfio: %s", err)
+
+		return
+	}
+
+	// /dev/vfio holds one entry per vfio group plus the vfio container device itself.
+	vfioDevices := strings.Fields(strings.ReplaceAll(output, "vfio", ""))
+	if len(vfioDevices) < len(attachments) {
+		podReport.add(CheckSriovVfio, false, "vfio devices inside pod (%s) do not match %d vfio-pci attachments",
+			strings.TrimSpace(output), len(attachments))
+
+		return
+	}
+
+	podReport.add(CheckSriovVfio, true, "%d vfio devices for %d vfio-pci attachments",
+		len(vfioDevices), len(attachments))
+}
+
+func checkHugepages(testPod *pod.Builder, podReport *PodReport) {
+	for _, container := range testPod.Object.Spec.Containers {
+		for resourceName, quantity := range container.Resources.Limits {
+			if !strings.HasPrefix(string(resourceName), hugepagesResourcePrefix) {
+				continue
+			}
+
+			pageSize, err := resource.ParseQuantity(strings.TrimPrefix(string(resourceName), hugepagesResourcePrefix))
+			if err != nil {
+				podReport.add(CheckHugepages, false, "container %s: invalid resource %s", container.Name, resourceName)
+
+				continue
+			}
+
+			output, err := execWithRetry(testPod,
+				[]string{"/bin/sh", "-c", fmt.Sprintf(hugetlbCmd, hugetlbSuffix(pageSize.Value()))}, container.Name)
+			if err != nil {
+				podReport.add(CheckHugepages, false, "container %s: error when reading hugetlb limit: %s",
+					container.Name, err)
+
+				continue
+			}
+
+			limit := strings.TrimSpace(output)
+			if limit != fmt.Sprintf("%d", quantity.Value()) {
+				podReport.add(CheckHugepages, false, "container %s: hugetlb limit %s does not match %s request %s",
+					container.Name, limit, resourceName, quantity.String())
+
+				continue
+			}
+
+			podReport.add(CheckHugepages, true, "container %s: %s %s allocated",
+				container.Name, quantity.String(), resourceName)
+		}
+	}
+}
+
+func checkExclusiveCPUs(testPod *pod.Builder, exclusiveCPUs map[string]map[string]cpuset.CPUSet,
+	podReport *PodReport) {
+	if testPod.Object.Status.QOSClass != v1.PodQOSGuaranteed {
+		return
+	}
+
+	for _, container := range testPod.Object.Spec.Containers {
+		cpuRequest := container.Resources.Requests.Cpu()
+		if cpuRequest.IsZero() || cpuRequest.MilliValue()%1000 != 0 {
+			continue
+		}
+
+		output, err := execWithRetry(testPod, []string{"/bin/sh", "-c", cpusetCmd}, container.Name)
+		if err != nil {
+			podReport.add(CheckExclusiveCPUs, false, "container %s: error when reading cpuset: %s", container.Name, err)
+
+			continue
+		}
+
+		cpus, err := cpuset.Parse(strings.TrimSpace(output))
+		if err != nil {
+			podReport.add(CheckExclusiveCPUs, false, "container %s: error when parsing cpuset %q: %s",
+				container.Name, output, err)
+
+			continue
+		}
+
+		if int64(cpus.Size()) != cpuRequest.Value() {
+			podReport.add(CheckExclusiveCPUs, false, "container %s: cpuset %s does not match %d requested cpus",
+				container.Name, cpus.String(), cpuRequest.Value())
+
+			continue
+		}
+
+		nodeName := testPod.Object.Spec.NodeName
+		if exclusiveCPUs[nodeName] == nil {
+			exclusiveCPUs[nodeName] = map[string]cpuset.CPUSet{}
+		}
+
+		exclusiveCPUs[nodeName][restartKey(testPod.Object.Name, container.Name)] = cpus
+		podReport.add(CheckExclusiveCPUs, true, "container %s: pinned to cpus %s", container.Name, cpus.String())
+	}
+}
+
+// checkExclusiveCPUsOverlap fails the exclusive CPU check of every container sharing cpus with another one on the
+// same node. Containers of different nodes may be pinned to the same cpu ids.
+func checkExclusiveCPUsOverlap(report *Report, exclusiveCPUs map[string]map[string]cpuset.CPUSet) {
+	for _, podReport := range report.Pods {
+		for _, nodeCPUs := range exclusiveCPUs {
+			for key, cpus := range nodeCPUs {
+				if !strings.HasPrefix(key, podReport.Name+"/") {
+					continue
+				}
+
+				for otherKey, otherCPUs := range nodeCPUs {
+					if otherKey == key {
+						continue
+					}
+
+					if shared := cpus.Intersection(otherCPUs); !shared.IsEmpty() {
+						podReport.add(CheckExclusiveCPUs, false, "container %s shares cpus %s with %s",
+							strings.TrimPrefix(key, podReport.Name+"/"), shared.String(), otherKey)
+					}
+				}
+			}
+		}
+	}
+}
+
+// podAttachments returns the pod network attachments matching one of the given networks.
+func podAttachments(testPod *pod.Builder, networks []string) ([]sriov.NetworkInfo, error) {
+	if len(networks) == 0 {
+		return nil, nil
+	}
+
+	podNetworks, err := sriov.ExtractNetworks(testPod.Object.Annotations[NetworkStatusAnnotation])
+	if err != nil {
+		return nil, err
+	}
+
+	var attachments []sriov.NetworkInfo
+
+	for _, podNetwork := range podNetworks {
+		for _, network := range networks {
+			if podNetwork.Name == testPod.Object.Namespace+"/"+network {
+				attachments = append(attachments, podNetwork)
+			}
+		}
+	}
+
+	return attachments, nil
+}
+
+// execWithRetry executes a command in a pod container, retrying in case the command returns an empty output.
+// An empty container name selects the first container of the pod.
+func execWithRetry(testPod *pod.Builder, command []string, containerName string) (string, error) {
+	var containerNames []string
+
+	if containerName != "" {
+		containerNames = append(containerNames, containerName)
+	}
+
+	var output string
+
+	for attempt := 1; attempt <= execRetries; attempt++ {
+		buf, err := testPod.ExecCommand(command, containerNames...)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", err, buf.String())
+		}
+
+		output = strings.ReplaceAll(buf.String(), "\r", "")
+		if strings.TrimSpace(output) != "" {
+			break
+		}
+
+		glog.V(100).Infof("Command %v returned an empty output on pod %s, attempt %d",
+			command, testPod.Object.Name, attempt)
+	}
+
+	return output, nil
+}
+
+// hugetlbSuffix returns the hugetlb cgroup file suffix of a page size, e.g. 2MB or 1GB.
+func hugetlbSuffix(pageSize int64) string {
+	switch {
+	case pageSize >= 1<<30:
+		return fmt.Sprintf("%dGB", pageSize>>30)
+	case pageSize >= 1<<20:
+		return fmt.Sprintf("%dMB", pageSize>>20)
+	default:
+		return fmt.Sprintf("%dKB", pageSize>>10)
+	}
+}
+
+func restartKey(podName, containerName string) string {
+	return podName + "/" + containerName
+}
+
+func contains(items []string, item string) bool {
+	for _, current := range items {
+		if current == item {
+			return true
+		}
+	}
+
+	return false
+}
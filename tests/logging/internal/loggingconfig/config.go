@@ -0,0 +1,87 @@
+package loggingconfig
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/config"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// PathToDefaultLoggingParamsFile path to config file with default logging parameters.
+	PathToDefaultLoggingParamsFile = "./default.yaml"
+)
+
+// LoggingConfig type keeps logging configuration.
+type LoggingConfig struct {
+	*config.GeneralConfig
+	ForwarderNamespace      string `yaml:"logging_forwarder_namespace" envconfig:"ECO_LOGGING_FORWARDER_NAMESPACE"`
+	ForwarderName           string `yaml:"logging_forwarder_name" envconfig:"ECO_LOGGING_FORWARDER_NAME"`
+	ForwarderServiceAccount string `yaml:"logging_forwarder_service_account" envconfig:"ECO_LOGGING_FORWARDER_SA"`
+	TestImage               string `yaml:"logging_test_image" envconfig:"ECO_LOGGING_TEST_IMAGE"`
+	GeneratorInterval       string `yaml:"logging_generator_interval" envconfig:"ECO_LOGGING_GENERATOR_INTERVAL"`
+	DeliveryTimeout         string `yaml:"logging_delivery_timeout" envconfig:"ECO_LOGGING_DELIVERY_TIMEOUT"`
+	MaxLostLines            string `yaml:"logging_max_lost_lines" envconfig:"ECO_LOGGING_MAX_LOST_LINES"`
+}
+
+// NewLoggingConfig returns instance of LoggingConfig config type.
+func NewLoggingConfig() *LoggingConfig {
+	log.Print("Creating new LoggingConfig struct")
+
+	var loggingConf LoggingConfig
+	loggingConf.GeneralConfig = config.NewConfig()
+
+	_, filename, _, _ := runtime.Caller(0)
+	baseDir := filepath.Dir(filename)
+	confFile := filepath.Join(baseDir, PathToDefaultLoggingParamsFile)
+	err := readFile(&loggingConf, confFile)
+
+	if err != nil {
+		log.Printf("Error to read config file %s", confFile)
+
+		return nil
+	}
+
+	err = readEnv(&loggingConf)
+
+	if err != nil {
+		log.Print("Error to read environment variables")
+
+		return nil
+	}
+
+	return &loggingConf
+}
+
+func readFile(loggingConfig *LoggingConfig, cfgFile string) error {
+	openedCfgFile, err := os.Open(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = openedCfgFile.Close()
+	}()
+
+	decoder := yaml.NewDecoder(openedCfgFile)
+	err = decoder.Decode(&loggingConfig)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func readEnv(loggingConfig *LoggingConfig) error {
+	err := envconfig.Process("", loggingConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
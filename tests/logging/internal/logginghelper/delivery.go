@@ -0,0 +1,141 @@
+package logginghelper
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	"github.com/openshift-kni/eco-gosystem/tests/logging/internal/loggingparams"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	metricLostLines       = "loggingmetrics_lost_lines"
+	metricDuplicatedLines = "loggingmetrics_duplicated_lines"
+	metricDeliveryTime    = "loggingmetrics_delivery_time_seconds"
+)
+
+// DeliveryReport is the outcome of the delivery of the generated lines with a sequence in (From, To].
+type DeliveryReport struct {
+	From         int           `json:"from"`
+	To           int           `json:"to"`
+	Expected     int           `json:"expected"`
+	Received     int           `json:"received"`
+	Lost         int           `json:"lost"`
+	Duplicated   int           `json:"duplicated"`
+	DeliveryTime time.Duration `json:"deliveryTime"`
+	// LostRanges lists the lost sequence ranges, e.g. "120-135".
+	LostRanges []string `json:"lostRanges,omitempty"`
+}
+
+// Metrics returns the lost lines, duplicated lines and delivery time metrics of the report named after the given
+// tag.
+func (report *DeliveryReport) Metrics(tag string) map[string]string {
+	return map[string]string{
+		fmt.Sprintf("%s_%s", metricLostLines, tag):       strconv.Itoa(report.Lost),
+		fmt.Sprintf("%s_%s", metricDuplicatedLines, tag): strconv.Itoa(report.Duplicated),
+		fmt.Sprintf("%s_%s", metricDeliveryTime, tag): strconv.FormatFloat(
+			report.DeliveryTime.Seconds(), 'f', 0, 64),
+	}
+}
+
+// WaitForDelivery waits until all the lines with a sequence in (from, to] are received, or the timeout is reached,
+// and returns the delivery report. Lines still missing at the timeout are reported as lost. Exec errors are
+// tolerated while waiting since the receiver node may be recovering from a disruption.
+func WaitForDelivery(receiver *pod.Builder, runID string, from, to int, start time.Time,
+	timeout time.Duration) (*DeliveryReport, error) {
+	var report *DeliveryReport
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), loggingparams.PollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			received, err := GetReceivedSequences(receiver, runID)
+			if err != nil {
+				glog.V(100).Infof("Failed to get received log lines: %s", err)
+
+				return false, nil
+			}
+
+			report = NewDeliveryReport(received, from, to)
+
+			return report.Lost == 0, nil
+		})
+
+	if report == nil {
+		return nil, fmt.Errorf("failed to read the received log lines within %s: %w", timeout, err)
+	}
+
+	report.DeliveryTime = time.Since(start)
+
+	if err != nil {
+		glog.V(90).Infof("%d log lines not delivered within %s: %v", report.Lost, timeout, report.LostRanges)
+	}
+
+	return report, nil
+}
+
+// NewDeliveryReport builds the delivery report of the lines with a sequence in (from, to] from the number of times
+// every sequence was received.
+func NewDeliveryReport(received map[int]int, from, to int) *DeliveryReport {
+	report := &DeliveryReport{From: from, To: to, Expected: to - from}
+	lostStart := -1
+
+	for sequence := from + 1; sequence <= to+1; sequence++ {
+		count := received[sequence]
+
+		if sequence <= to && count == 0 {
+			report.Lost++
+
+			if lostStart < 0 {
+				lostStart = sequence
+			}
+
+			continue
+		}
+
+		if lostStart >= 0 {
+			report.LostRanges = append(report.LostRanges, fmt.Sprintf("%d-%d", lostStart, sequence-1))
+			lostStart = -1
+		}
+
+		if sequence <= to {
+			report.Received++
+			report.Duplicated += count - 1
+		}
+	}
+
+	return report
+}
+
+func parseSequence(output string) (int, error) {
+	sequence, err := strconv.Atoi(strings.TrimSpace(output))
+	if err != nil {
+		return 0, fmt.Errorf("invalid generator sequence %q: %w", output, err)
+	}
+
+	return sequence, nil
+}
+
+// parseReceived parses the "<run ID> <sequence>" lines written by the receiver.
+func parseReceived(output, runID string) map[int]int {
+	received := map[int]int{}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != runID {
+			continue
+		}
+
+		sequence, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+
+		received[sequence]++
+	}
+
+	return received
+}
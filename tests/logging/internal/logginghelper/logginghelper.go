@@ -0,0 +1,349 @@
+package logginghelper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/daemonset"
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	"github.com/openshift-kni/eco-gosystem/tests/logging/internal/loggingparams"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	dataVolumeName = "data"
+	dataMountPath  = "/data"
+	// receivedFile holds one "<run ID> <sequence>" line per sequence number found in the received logs.
+	receivedFile = dataMountPath + "/received"
+	// sequenceFile holds the last sequence number written by the generator, so that it resumes after a restart.
+	sequenceFile = dataMountPath + "/sequence"
+)
+
+// receiverScript is an HTTP server recording the sequence numbers of the log lines POSTed by the collector.
+const receiverScript = `
+import http.server, re, threading
+pattern = re.compile(rb"eco-seq-([a-z0-9]+)-([0-9]+)")
+lock = threading.Lock()
+class Handler(http.server.BaseHTTPRequestHandler):
+    def read_body(self):
+        if self.headers.get("Transfer-Encoding", "") != "chunked":
+            return self.rfile.read(int(self.headers.get("Content-Length", 0)))
+        body = b""
+        while True:
+            size = int(self.rfile.readline().strip(), 16)
+            if size == 0:
+                self.rfile.readline()
+                return body
+            body += self.rfile.read(size)
+            self.rfile.readline()
+    def do_POST(self):
+        matches = pattern.findall(self.read_body())
+        with lock, open("` + receivedFile + `", "ab") as received:
+            for run, seq in matches:
+                received.write(run + b" " + seq + b"\n")
+        self.send_response(200)
+        self.end_headers()
+    def log_message(self, *args):
+        pass
+http.server.ThreadingHTTPServer(("", %d), Handler).serve_forever()
+`
+
+// generatorScript prints a sequence numbered line every interval. The sequence is persisted after every line, so at
+// most one line is printed twice when the container is restarted.
+const generatorScript = `n=$(cat ` + sequenceFile + ` 2>/dev/null || echo 0)
+while true; do
+  n=$((n+1))
+  echo "$(date -u +%%FT%%T.%%NZ) eco-seq-%s-$n"
+  echo $n > ` + sequenceFile + `
+  sleep %s
+done`
+
+// DeployReceiver creates the pod and service receiving the logs forwarded by the collector.
+func DeployReceiver(apiClient *clients.Settings, nsname, image string) (*pod.Builder, error) {
+	receiver := pod.NewBuilder(apiClient, loggingparams.ReceiverName, nsname, image).
+		RedefineDefaultCMD([]string{"python3", "-c", fmt.Sprintf(receiverScript, loggingparams.ReceiverPort)}).
+		WithLabel("app", loggingparams.ReceiverName)
+
+	withDataVolume(receiver)
+
+	receiver, err := receiver.CreateAndWaitUntilRunning(loggingparams.DefaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: loggingparams.ReceiverName, Namespace: nsname},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{"app": loggingparams.ReceiverName},
+			Ports: []v1.ServicePort{{
+				Name:       "http",
+				Port:       loggingparams.ReceiverPort,
+				TargetPort: intstr.FromInt(loggingparams.ReceiverPort),
+			}},
+		},
+	}
+
+	_, err = apiClient.CoreV1Interface.Services(nsname).Create(context.TODO(), service, metav1.CreateOptions{})
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		glog.V(100).Infof("Failed to create service %s in namespace %s: %s", loggingparams.ReceiverName, nsname, err)
+
+		return nil, err
+	}
+
+	return receiver, nil
+}
+
+// ReceiverURL returns the in-cluster URL of the log receiver service.
+func ReceiverURL(nsname string) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d", loggingparams.ReceiverName, nsname, loggingparams.ReceiverPort)
+}
+
+// DeployGenerator creates the pod printing sequence numbered log lines tagged with the run ID.
+func DeployGenerator(apiClient *clients.Settings, nsname, image, runID, interval string) (*pod.Builder, error) {
+	generator := pod.NewBuilder(apiClient, loggingparams.GeneratorName, nsname, image).
+		RedefineDefaultCMD([]string{"/bin/bash", "-c", fmt.Sprintf(generatorScript, runID, interval)}).
+		WithLabel("app", loggingparams.GeneratorName)
+
+	withDataVolume(generator)
+
+	return generator.CreateAndWaitUntilRunning(loggingparams.DefaultTimeout)
+}
+
+// GetGeneratorSequence returns the last sequence number printed by the generator.
+func GetGeneratorSequence(generator *pod.Builder) (int, error) {
+	output, err := generator.ExecCommand([]string{"cat", sequenceFile})
+	if err != nil {
+		return 0, err
+	}
+
+	return parseSequence(output.String())
+}
+
+// WaitForGenerator waits until the generator pod is running again and returns its last sequence number. API and
+// exec errors are tolerated while waiting since the generator node may be recovering from a disruption.
+func WaitForGenerator(generator *pod.Builder, timeout time.Duration) (int, error) {
+	var sequence int
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), loggingparams.PollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			var err error
+
+			sequence, err = GetGeneratorSequence(generator)
+			if err != nil {
+				glog.V(100).Infof("Failed to get generator sequence: %s", err)
+
+				return false, nil
+			}
+
+			return true, nil
+		})
+
+	return sequence, err
+}
+
+// GetReceivedSequences returns how many times every sequence number of the run was received.
+func GetReceivedSequences(receiver *pod.Builder, runID string) (map[int]int, error) {
+	output, err := receiver.ExecCommand([]string{"/bin/sh", "-c", "cat " + receivedFile + " 2>/dev/null || true"})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseReceived(output.String(), runID), nil
+}
+
+// CreateForwarder creates the ClusterLogForwarder sending the application logs of the test namespace to the
+// receiver. If the ClusterLogForwarder already exists, the test output, input and pipeline are appended to it.
+func CreateForwarder(apiClient *clients.Settings, nsname, name, serviceAccount, testNamespace string) error {
+	client := apiClient.Interface.Resource(loggingparams.ClusterLogForwarderGVR).Namespace(nsname)
+
+	forwarder, err := client.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	if k8serrors.IsNotFound(err) {
+		forwarder = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": loggingparams.ClusterLogForwarderGVR.GroupVersion().String(),
+			"kind":       "ClusterLogForwarder",
+			"metadata":   map[string]interface{}{"name": name, "namespace": nsname},
+			"spec":       map[string]interface{}{},
+		}}
+
+		if serviceAccount != "" {
+			err = unstructured.SetNestedField(forwarder.Object, serviceAccount, "spec", "serviceAccountName")
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = appendToList(forwarder, "outputs", map[string]interface{}{
+		"name": loggingparams.ForwarderResourceName,
+		"type": "http",
+		"url":  ReceiverURL(testNamespace),
+		"http": map[string]interface{}{"method": "POST"},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = appendToList(forwarder, "inputs", map[string]interface{}{
+		"name":        loggingparams.ForwarderResourceName,
+		"application": map[string]interface{}{"namespaces": []interface{}{testNamespace}},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = appendToList(forwarder, "pipelines", map[string]interface{}{
+		"name":       loggingparams.ForwarderResourceName,
+		"inputRefs":  []interface{}{loggingparams.ForwarderResourceName},
+		"outputRefs": []interface{}{loggingparams.ForwarderResourceName},
+	})
+	if err != nil {
+		return err
+	}
+
+	if forwarder.GetResourceVersion() == "" {
+		_, err = client.Create(context.TODO(), forwarder, metav1.CreateOptions{})
+	} else {
+		_, err = client.Update(context.TODO(), forwarder, metav1.UpdateOptions{})
+	}
+
+	if err != nil {
+		glog.V(100).Infof("Failed to apply ClusterLogForwarder %s in namespace %s: %s", name, nsname, err)
+	}
+
+	return err
+}
+
+// DeleteForwarder removes the test output, input and pipeline from the ClusterLogForwarder. The ClusterLogForwarder
+// is deleted when nothing else is left in it.
+func DeleteForwarder(apiClient *clients.Settings, nsname, name string) error {
+	client := apiClient.Interface.Resource(loggingparams.ClusterLogForwarderGVR).Namespace(nsname)
+
+	forwarder, err := client.Get(context.TODO(), name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var remaining int
+
+	for _, field := range []string{"outputs", "inputs", "pipelines"} {
+		count, err := removeFromList(forwarder, field, loggingparams.ForwarderResourceName)
+		if err != nil {
+			return err
+		}
+
+		remaining += count
+	}
+
+	if remaining == 0 {
+		return client.Delete(context.TODO(), name, metav1.DeleteOptions{})
+	}
+
+	_, err = client.Update(context.TODO(), forwarder, metav1.UpdateOptions{})
+
+	return err
+}
+
+// CollectorName returns the name of the collector daemonset deployed for a ClusterLogForwarder.
+func CollectorName(forwarderName string) string {
+	if forwarderName == loggingparams.LegacyForwarderName {
+		return loggingparams.LegacyCollectorName
+	}
+
+	return forwarderName
+}
+
+// RestartCollector deletes the collector pods and waits for the collector daemonset to be ready again.
+func RestartCollector(apiClient *clients.Settings, nsname, forwarderName string, timeout time.Duration) error {
+	collector, err := daemonset.Pull(apiClient, CollectorName(forwarderName), nsname)
+	if err != nil {
+		return err
+	}
+
+	collectorPods, err := pod.List(apiClient, nsname, metav1.ListOptions{
+		LabelSelector: labels.Set(collector.Object.Spec.Selector.MatchLabels).String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, collectorPod := range collectorPods {
+		glog.V(90).Infof("Deleting collector pod %s", collectorPod.Object.Name)
+
+		_, err = collectorPod.DeleteAndWait(timeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !collector.IsReady(timeout) {
+		return fmt.Errorf("collector daemonset %s is not ready after %s", collector.Object.Name, timeout)
+	}
+
+	return nil
+}
+
+func withDataVolume(builder *pod.Builder) {
+	builder.WithVolume(v1.Volume{
+		Name:         dataVolumeName,
+		VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+	})
+
+	builder.Definition.Spec.Containers[0].VolumeMounts = append(builder.Definition.Spec.Containers[0].VolumeMounts,
+		v1.VolumeMount{Name: dataVolumeName, MountPath: dataMountPath})
+}
+
+func appendToList(forwarder *unstructured.Unstructured, field string, entry map[string]interface{}) error {
+	if _, err := removeFromList(forwarder, field, loggingparams.ForwarderResourceName); err != nil {
+		return err
+	}
+
+	list, _, err := unstructured.NestedSlice(forwarder.Object, "spec", field)
+	if err != nil {
+		return err
+	}
+
+	return unstructured.SetNestedSlice(forwarder.Object, append(list, entry), "spec", field)
+}
+
+// removeFromList removes the named entry from a spec list and returns the number of remaining entries.
+func removeFromList(forwarder *unstructured.Unstructured, field, name string) (int, error) {
+	list, _, err := unstructured.NestedSlice(forwarder.Object, "spec", field)
+	if err != nil {
+		return 0, err
+	}
+
+	var kept []interface{}
+
+	for _, entry := range list {
+		if entryMap, ok := entry.(map[string]interface{}); ok && entryMap["name"] == name {
+			continue
+		}
+
+		kept = append(kept, entry)
+	}
+
+	if len(kept) == 0 {
+		unstructured.RemoveNestedField(forwarder.Object, "spec", field)
+
+		return 0, nil
+	}
+
+	return len(kept), unstructured.SetNestedSlice(forwarder.Object, kept, "spec", field)
+}
@@ -0,0 +1,21 @@
+package logginginittools
+
+import (
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/inittools"
+	"github.com/openshift-kni/eco-gosystem/tests/logging/internal/loggingconfig"
+)
+
+var (
+	// APIClient provides API access to cluster.
+	APIClient *clients.Settings
+	// LoggingTestConfig provides access to logging system tests configuration parameters.
+	LoggingTestConfig *loggingconfig.LoggingConfig
+)
+
+// init loads all variables automatically when this package is imported. Once package is imported a user has full
+// access to all vars within init function. It is recommended to import this package using dot import.
+func init() {
+	LoggingTestConfig = loggingconfig.NewLoggingConfig()
+	APIClient = inittools.APIClient
+}
@@ -0,0 +1,28 @@
+package loggingparams
+
+import "time"
+
+const (
+	// Label represents logging system tests label that can be used for test cases selection.
+	Label = "logging"
+	// LabelLogDelivery represents tests labels related to the log delivery validation.
+	LabelLogDelivery = "log-delivery"
+	// ReceiverName is the name of the pod and service receiving the forwarded logs.
+	ReceiverName = "eco-log-receiver"
+	// ReceiverPort is the port the log receiver listens on.
+	ReceiverPort = 8080
+	// GeneratorName is the name of the pod emitting the sequence numbered log lines.
+	GeneratorName = "eco-log-generator"
+	// ForwarderResourceName is the name of the output, input and pipeline added to the ClusterLogForwarder.
+	ForwarderResourceName = "eco-log-delivery"
+	// LegacyForwarderName is the name of the ClusterLogForwarder managed by the ClusterLogging instance.
+	LegacyForwarderName = "instance"
+	// LegacyCollectorName is the name of the collector daemonset of the ClusterLogging instance.
+	LegacyCollectorName = "collector"
+	// DefaultTimeout is the timeout used for test resources creation.
+	DefaultTimeout = 5 * time.Minute
+	// RebootTimeout is the time given to a node to reboot and become ready again.
+	RebootTimeout = 30 * time.Minute
+	// PollInterval is the interval between two reads of the received log lines.
+	PollInterval = 10 * time.Second
+)
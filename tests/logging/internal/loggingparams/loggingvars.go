@@ -0,0 +1,32 @@
+package loggingparams
+
+import (
+	systemtestsparams "github.com/openshift-kni/eco-gosystem/tests/internal/params"
+	"github.com/openshift-kni/k8sreporter"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// Labels represents the range of labels that can be used for test cases selection.
+	Labels = []string{systemtestsparams.Label, Label}
+
+	// ReporterNamespacesToDump tells to the reporter from where to collect logs.
+	ReporterNamespacesToDump = map[string]string{
+		"openshift-logging":    "logging",
+		"logging-system-tests": "logging-system-tests",
+	}
+
+	// ReporterCRDsToDump tells to the reporter what CRs to dump.
+	ReporterCRDsToDump = []k8sreporter.CRData{
+		{Cr: &v1.PodList{}},
+	}
+
+	// TestNamespaceName is used for defining the namespace name where test resources are created.
+	TestNamespaceName = "logging-system-tests"
+
+	// ClusterLogForwarderGVR is the resource of the ClusterLogForwarder custom resources.
+	ClusterLogForwarderGVR = schema.GroupVersionResource{
+		Group: "logging.openshift.io", Version: "v1", Resource: "clusterlogforwarders",
+	}
+)
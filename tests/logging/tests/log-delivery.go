@@ -0,0 +1,193 @@
+package logging_system_test
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-goinfra/pkg/daemonset"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/await"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/reboot"
+	"github.com/openshift-kni/eco-gosystem/tests/logging/internal/logginghelper"
+	. "github.com/openshift-kni/eco-gosystem/tests/logging/internal/logginginittools"
+	"github.com/openshift-kni/eco-gosystem/tests/logging/internal/loggingparams"
+)
+
+var _ = Describe(
+	"LogDelivery",
+	Ordered,
+	ContinueOnFailure,
+	Label(loggingparams.LabelLogDelivery), func() {
+		var (
+			receiver        *pod.Builder
+			generator       *pod.Builder
+			runID           string
+			checkpoint      int
+			deliveryTimeout time.Duration
+			maxLostLines    int
+		)
+
+		BeforeAll(func() {
+			var err error
+
+			deliveryTimeout, err = time.ParseDuration(LoggingTestConfig.DeliveryTimeout)
+			Expect(err).ToNot(HaveOccurred(), "invalid log delivery timeout")
+
+			maxLostLines, err = strconv.Atoi(LoggingTestConfig.MaxLostLines)
+			Expect(err).ToNot(HaveOccurred(), "invalid max lost lines")
+
+			runID = strconv.FormatInt(time.Now().Unix(), 36)
+
+			By("Deploy the log receiver")
+			receiver, err = logginghelper.DeployReceiver(APIClient, loggingparams.TestNamespaceName,
+				LoggingTestConfig.TestImage)
+			Expect(err).ToNot(HaveOccurred(), "error deploying the log receiver")
+
+			By("Deploy the log generator")
+			generator, err = logginghelper.DeployGenerator(APIClient, loggingparams.TestNamespaceName,
+				LoggingTestConfig.TestImage, runID, LoggingTestConfig.GeneratorInterval)
+			Expect(err).ToNot(HaveOccurred(), "error deploying the log generator")
+
+			By("Forward the test namespace logs to the receiver")
+			err = logginghelper.CreateForwarder(APIClient, LoggingTestConfig.ForwarderNamespace,
+				LoggingTestConfig.ForwarderName, LoggingTestConfig.ForwarderServiceAccount,
+				loggingparams.TestNamespaceName)
+			Expect(err).ToNot(HaveOccurred(), "error configuring the ClusterLogForwarder")
+
+			By("Wait for the collector to be ready")
+			Eventually(func() error {
+				collector, err := daemonset.Pull(APIClient, logginghelper.CollectorName(LoggingTestConfig.ForwarderName),
+					LoggingTestConfig.ForwarderNamespace)
+				if err != nil {
+					return err
+				}
+
+				if !collector.IsReady(loggingparams.DefaultTimeout) {
+					return fmt.Errorf("collector daemonset %s is not ready", collector.Object.Name)
+				}
+
+				return nil
+			}, loggingparams.DefaultTimeout, loggingparams.PollInterval).Should(Succeed(),
+				"collector daemonset is not ready")
+
+			By("Wait for the first log line to be received")
+			Eventually(func() (int, error) {
+				received, err := logginghelper.GetReceivedSequences(receiver, runID)
+
+				return len(received), err
+			}, deliveryTimeout, loggingparams.PollInterval).Should(BeNumerically(">", 0),
+				"no log line received")
+
+			// Lines printed before the first one is received may have been skipped while the collector was
+			// configured, only the lines printed from now on are accounted.
+			checkpoint, err = logginghelper.GetGeneratorSequence(generator)
+			Expect(err).ToNot(HaveOccurred(), "error getting the generator sequence")
+		})
+
+		AfterAll(func() {
+			By("Remove the log receiver from the ClusterLogForwarder")
+			err := logginghelper.DeleteForwarder(APIClient, LoggingTestConfig.ForwarderNamespace,
+				LoggingTestConfig.ForwarderName)
+			Expect(err).ToNot(HaveOccurred(), "error cleaning up the ClusterLogForwarder")
+		})
+
+		It("Verify logs are delivered end-to-end", Label(loggingparams.LabelLogDelivery), func() {
+			By("Let the generator print log lines")
+			time.Sleep(time.Minute)
+
+			verifyDelivery(receiver, generator, runID, &checkpoint, time.Now(), deliveryTimeout, maxLostLines,
+				"steadystate")
+		})
+
+		It("Verify logs are not lost across a collector restart", Label(loggingparams.LabelLogDelivery), func() {
+			By("Restart the collector")
+			restartTime := time.Now()
+			err := logginghelper.RestartCollector(APIClient, LoggingTestConfig.ForwarderNamespace,
+				LoggingTestConfig.ForwarderName, loggingparams.DefaultTimeout)
+			Expect(err).ToNot(HaveOccurred(), "error restarting the collector")
+
+			verifyDelivery(receiver, generator, runID, &checkpoint, restartTime, deliveryTimeout, maxLostLines,
+				"collectorrestart")
+		})
+
+		It("Verify logs are not lost across a soft reboot", Label(loggingparams.LabelLogDelivery), func() {
+			nodeName := generator.Object.Spec.NodeName
+
+			node, err := nodes.Pull(APIClient, nodeName)
+			Expect(err).ToNot(HaveOccurred(), "error pulling node %s", nodeName)
+
+			bootID := node.Object.Status.NodeInfo.BootID
+
+			By(fmt.Sprintf("Soft reboot node %s", nodeName))
+			rebootTime := time.Now()
+			err = reboot.SoftRebootNode(nodeName)
+			Expect(err).ToNot(HaveOccurred(), "Error rebooting the nodes.")
+
+			By("Wait for node to become unreachable")
+			err = await.WaitUntilNodeIsUnreachable(nodeName, 3*time.Minute)
+			Expect(err).ToNot(HaveOccurred(), "Node is still reachable: %s", err)
+
+			By("Wait for node to reboot and become ready")
+			err = reboot.WaitUntilNodeRebooted(nodeName, bootID, loggingparams.RebootTimeout)
+			Expect(err).ToNot(HaveOccurred(), "node %s did not become ready after reboot", nodeName)
+
+			verifyDelivery(receiver, generator, runID, &checkpoint, rebootTime, deliveryTimeout, maxLostLines,
+				"softreboot")
+		})
+
+		It("Verify logs are not lost across a hard reboot", Label(loggingparams.LabelLogDelivery), func() {
+			nodeName := generator.Object.Spec.NodeName
+
+			node, err := nodes.Pull(APIClient, nodeName)
+			Expect(err).ToNot(HaveOccurred(), "error pulling node %s", nodeName)
+
+			bootID := node.Object.Status.NodeInfo.BootID
+
+			By(fmt.Sprintf("Hard reboot node %s", nodeName))
+			rebootTime := time.Now()
+			err = reboot.HardRebootNode(nodeName, loggingparams.TestNamespaceName)
+			Expect(err).ToNot(HaveOccurred(), "Error rebooting the nodes.")
+
+			By("Wait for node to reboot and become ready")
+			err = reboot.WaitUntilNodeRebooted(nodeName, bootID, loggingparams.RebootTimeout)
+			Expect(err).ToNot(HaveOccurred(), "node %s did not become ready after reboot", nodeName)
+
+			verifyDelivery(receiver, generator, runID, &checkpoint, rebootTime, deliveryTimeout, maxLostLines,
+				"hardreboot")
+		})
+	})
+
+// verifyDelivery waits for the lines printed since the checkpoint to be received, persists the lost lines,
+// duplicated lines and delivery time metrics to the ginkgo report and asserts the lost lines threshold. The
+// checkpoint is moved to the last line accounted so that every line is checked exactly once.
+func verifyDelivery(receiver, generator *pod.Builder, runID string, checkpoint *int, start time.Time,
+	timeout time.Duration, maxLostLines int, tag string) {
+	By("Wait for the log generator to be running")
+	sequence, err := logginghelper.WaitForGenerator(generator, loggingparams.DefaultTimeout)
+	Expect(err).ToNot(HaveOccurred(), "log generator is not running")
+
+	from := *checkpoint
+	*checkpoint = sequence
+
+	By(fmt.Sprintf("Wait for log lines %d to %d to be delivered", from+1, sequence))
+	report, err := logginghelper.WaitForDelivery(receiver, runID, from, sequence, start, timeout)
+	Expect(err).ToNot(HaveOccurred(), "error checking the received log lines")
+
+	// Persist log delivery metrics to ginkgo report for further processing in pipeline.
+	for metricName, metricValue := range report.Metrics(tag) {
+		_, err := fmt.Fprintf(GinkgoWriter, "%s: %s\n", metricName, metricValue)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	if len(report.LostRanges) > 0 {
+		_, err := fmt.Fprintf(GinkgoWriter, "lost log lines: %v\n", report.LostRanges)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	Expect(report.Lost).To(BeNumerically("<=", maxLostLines), "%d of %d log lines lost", report.Lost,
+		report.Expected)
+}
@@ -0,0 +1,95 @@
+package ocpupgradeconfig
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/config"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// PathToDefaultOcpUpgradeParamsFile path to config file with default OCP upgrade parameters.
+	PathToDefaultOcpUpgradeParamsFile = "./default.yaml"
+)
+
+// OcpUpgradeConfig type keeps OCP upgrade configuration.
+type OcpUpgradeConfig struct {
+	*config.GeneralConfig
+	TestWorkload struct {
+		Namespace      string `yaml:"namespace" envconfig:"ECO_OCPUPGRADE_TESTWORKLOAD_NAMESPACE"`
+		CreateMethod   string `yaml:"create_method" envconfig:"ECO_OCPUPGRADE_TESTWORKLOAD_CREATE_METHOD"`
+		CreateShellCmd string `yaml:"create_shell_cmd" envconfig:"ECO_OCPUPGRADE_TESTWORKLOAD_CREATE_SHELLCMD"`
+	} `yaml:"ocpupgrade_test_workload"`
+	DesiredVersion    string `yaml:"ocpupgrade_desired_version" envconfig:"ECO_OCPUPGRADE_DESIRED_VERSION"`
+	DesiredImage      string `yaml:"ocpupgrade_desired_image" envconfig:"ECO_OCPUPGRADE_DESIRED_IMAGE"`
+	Force             bool   `yaml:"ocpupgrade_force" envconfig:"ECO_OCPUPGRADE_FORCE"`
+	Timeout           string `yaml:"ocpupgrade_timeout" envconfig:"ECO_OCPUPGRADE_TIMEOUT"`
+	PollInterval      string `yaml:"ocpupgrade_poll_interval" envconfig:"ECO_OCPUPGRADE_POLL_INTERVAL"`
+	StabilityDuration string `yaml:"ocpupgrade_stability_duration" envconfig:"ECO_OCPUPGRADE_STABILITY_DURATION"`
+	MaxRestartDelta   string `yaml:"ocpupgrade_max_restart_delta" envconfig:"ECO_OCPUPGRADE_MAX_RESTART_DELTA"`
+	ProberInterval    string `yaml:"ocpupgrade_prober_interval" envconfig:"ECO_OCPUPGRADE_PROBER_INTERVAL"`
+	ProberTargets     string `yaml:"ocpupgrade_prober_targets" envconfig:"ECO_OCPUPGRADE_PROBER_TARGETS"`
+	MaxDowntime       string `yaml:"ocpupgrade_max_downtime" envconfig:"ECO_OCPUPGRADE_MAX_DOWNTIME"`
+}
+
+// NewOcpUpgradeConfig returns instance of OcpUpgradeConfig config type.
+func NewOcpUpgradeConfig() *OcpUpgradeConfig {
+	log.Print("Creating new OcpUpgradeConfig struct")
+
+	var ocpupgradeConf OcpUpgradeConfig
+	ocpupgradeConf.GeneralConfig = config.NewConfig()
+
+	_, filename, _, _ := runtime.Caller(0)
+	baseDir := filepath.Dir(filename)
+	confFile := filepath.Join(baseDir, PathToDefaultOcpUpgradeParamsFile)
+	err := readFile(&ocpupgradeConf, confFile)
+
+	if err != nil {
+		log.Printf("Error to read config file %s", confFile)
+
+		return nil
+	}
+
+	err = readEnv(&ocpupgradeConf)
+
+	if err != nil {
+		log.Print("Error to read environment variables")
+
+		return nil
+	}
+
+	return &ocpupgradeConf
+}
+
+func readFile(ocpupgradeConfig *OcpUpgradeConfig, cfgFile string) error {
+	openedCfgFile, err := os.Open(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = openedCfgFile.Close()
+	}()
+
+	decoder := yaml.NewDecoder(openedCfgFile)
+	err = decoder.Decode(&ocpupgradeConfig)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func readEnv(ocpupgradeConfig *OcpUpgradeConfig) error {
+	err := envconfig.Process("", ocpupgradeConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package ocpupgradehelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+const (
+	metricUpgradeDuration  = "ocpupgrademetrics_duration_seconds"
+	metricOperatorDuration = "ocpupgrademetrics_operator_duration_seconds"
+	metricPoolRollout      = "ocpupgrademetrics_mcp_rollout_seconds"
+)
+
+// Progress is the upgrade progress of a ClusterOperator or a MachineConfigPool. A zero End means it did not
+// finish.
+type Progress struct {
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Duration time.Duration `json:"duration"`
+}
+
+func (progress *Progress) finish(end time.Time) {
+	progress.End = end
+	progress.Duration = end.Sub(progress.Start)
+}
+
+// Report is the outcome of a platform upgrade.
+type Report struct {
+	FromVersion string                   `json:"fromVersion"`
+	ToVersion   string                   `json:"toVersion"`
+	Start       time.Time                `json:"start"`
+	End         time.Time                `json:"end"`
+	Duration    time.Duration            `json:"duration"`
+	History     []configv1.UpdateHistory `json:"history"`
+	Operators   map[string]*Progress     `json:"operators"`
+	Pools       map[string]*Progress     `json:"pools"`
+}
+
+// Metrics returns the upgrade duration, per-operator upgrade durations and MachineConfigPool rollout times.
+func (report *Report) Metrics() map[string]string {
+	metrics := map[string]string{
+		metricUpgradeDuration: formatSeconds(report.Duration),
+	}
+
+	for name, progress := range report.Operators {
+		if !progress.End.IsZero() {
+			metrics[fmt.Sprintf("%s_%s", metricOperatorDuration, name)] = formatSeconds(progress.Duration)
+		}
+	}
+
+	for name, progress := range report.Pools {
+		if !progress.End.IsZero() {
+			metrics[fmt.Sprintf("%s_%s", metricPoolRollout, name)] = formatSeconds(progress.Duration)
+		}
+	}
+
+	return metrics
+}
+
+// Pending returns the ClusterOperators and MachineConfigPools which did not finish upgrading.
+func (report *Report) Pending() []string {
+	var pending []string
+
+	for name, progress := range report.Operators {
+		if progress.End.IsZero() {
+			pending = append(pending, "clusteroperator/"+name)
+		}
+	}
+
+	for name, progress := range report.Pools {
+		if progress.End.IsZero() && !progress.Start.IsZero() {
+			pending = append(pending, "machineconfigpool/"+name)
+		}
+	}
+
+	sort.Strings(pending)
+
+	return pending
+}
+
+// String returns the ClusterVersion history of the report, newest first.
+func (report *Report) String() string {
+	var builder strings.Builder
+
+	for _, history := range report.History {
+		completion := "-"
+		if history.CompletionTime != nil {
+			completion = history.CompletionTime.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(&builder, "%s %s started %s completed %s\n", history.Version, history.State,
+			history.StartedTime.Format(time.RFC3339), completion)
+	}
+
+	return builder.String()
+}
+
+// WriteReport writes the report as <name>_report.json to dir.
+func (report *Report) WriteReport(dir, name string) error {
+	glog.V(90).Infof("Writing upgrade report %s to %s", name, dir)
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, name+"_report.json"), content, 0644)
+}
+
+func formatSeconds(duration time.Duration) string {
+	return strconv.FormatFloat(duration.Seconds(), 'f', 0, 64)
+}
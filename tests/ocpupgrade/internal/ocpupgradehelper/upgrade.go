@@ -0,0 +1,221 @@
+package ocpupgradehelper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/clusterversion"
+	"github.com/openshift-kni/eco-goinfra/pkg/mco"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
+	configv1 "github.com/openshift/api/config/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// StartUpgrade sets the desired update of the ClusterVersion to the given version and/or release image and
+// returns the upgrade report to track it.
+func StartUpgrade(apiClient *clients.Settings, version, image string, force bool) (*Report, error) {
+	if version == "" && image == "" {
+		return nil, fmt.Errorf("either the desired version or the desired release image is required")
+	}
+
+	fromVersion, err := cluster.GetClusterVersion(apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterVersion, err := clusterversion.Pull(apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterVersion.Object.Spec.DesiredUpdate = &configv1.Update{Version: version, Image: image, Force: force}
+
+	glog.V(90).Infof("Upgrading the cluster from %s to version %q image %q", fromVersion, version, image)
+
+	_, err = apiClient.ClusterVersions().Update(context.TODO(), clusterVersion.Object, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Report{
+		FromVersion: fromVersion,
+		Start:       time.Now(),
+		Operators:   map[string]*Progress{},
+		Pools:       map[string]*Progress{},
+	}, nil
+}
+
+// WaitForUpgrade polls the ClusterVersion, ClusterOperators and MachineConfigPools until the upgrade is completed
+// and every pool rolled out, recording the progress in the report. API errors are tolerated while waiting since
+// the API may be unavailable while the control plane is upgraded.
+func WaitForUpgrade(apiClient *clients.Settings, report *Report, pollInterval, timeout time.Duration) error {
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			done, err := track(apiClient, report)
+			if err != nil {
+				glog.V(90).Infof("Failed to track the upgrade progress: %s", err)
+
+				return false, nil
+			}
+
+			return done, nil
+		})
+
+	report.End = time.Now()
+	report.Duration = report.End.Sub(report.Start)
+
+	if err != nil {
+		return fmt.Errorf("upgrade from %s to %s not completed within %s: %w", report.FromVersion,
+			report.ToVersion, timeout, err)
+	}
+
+	glog.V(90).Infof("Upgrade from %s to %s completed in %s", report.FromVersion, report.ToVersion,
+		report.Duration)
+
+	return nil
+}
+
+// track records the current upgrade progress in the report and returns true once the upgrade is done.
+func track(apiClient *clients.Settings, report *Report) (bool, error) {
+	clusterVersion, err := clusterversion.Pull(apiClient)
+	if err != nil {
+		return false, err
+	}
+
+	report.History = clusterVersion.Object.Status.History
+
+	// The desired version is the current one until the update is accepted by the cluster version operator.
+	if clusterVersion.Object.Status.Desired.Version == report.FromVersion {
+		return false, nil
+	}
+
+	report.ToVersion = clusterVersion.Object.Status.Desired.Version
+
+	history := clusterVersion.Object.Status.History
+	versionCompleted := len(history) > 0 && history[0].Version == report.ToVersion &&
+		history[0].State == configv1.CompletedUpdate
+
+	operatorsDone, err := trackOperators(apiClient, report)
+	if err != nil {
+		return false, err
+	}
+
+	poolsDone, err := trackPools(apiClient, report)
+	if err != nil {
+		return false, err
+	}
+
+	return versionCompleted && operatorsDone && poolsDone, nil
+}
+
+// trackOperators records when every ClusterOperator starts progressing and when it reports the target version
+// while available and not progressing.
+func trackOperators(apiClient *clients.Settings, report *Report) (bool, error) {
+	clusterOperators, err := apiClient.ClusterOperators().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	done := true
+	now := time.Now()
+
+	for _, clusterOperator := range clusterOperators.Items {
+		progress, found := report.Operators[clusterOperator.Name]
+		if !found {
+			progress = &Progress{}
+			report.Operators[clusterOperator.Name] = progress
+		}
+
+		progressing := operatorCondition(&clusterOperator, configv1.OperatorProgressing)
+
+		if progressing && progress.Start.IsZero() {
+			progress.Start = now
+		}
+
+		if progress.End.IsZero() && !progressing && operatorCondition(&clusterOperator, configv1.OperatorAvailable) &&
+			operatorVersion(&clusterOperator) == report.ToVersion {
+			// The operator may have been upgraded between two polls without being seen progressing.
+			if progress.Start.IsZero() {
+				progress.Start = report.Start
+			}
+
+			progress.finish(now)
+		}
+
+		if progress.End.IsZero() {
+			done = false
+		}
+	}
+
+	return done, nil
+}
+
+// trackPools records when every MachineConfigPool starts updating and when it is updated again.
+func trackPools(apiClient *clients.Settings, report *Report) (bool, error) {
+	pools, err := mco.ListMCP(apiClient)
+	if err != nil {
+		return false, err
+	}
+
+	done := true
+	now := time.Now()
+
+	for _, pool := range pools {
+		progress, found := report.Pools[pool.Object.Name]
+		if !found {
+			progress = &Progress{}
+			report.Pools[pool.Object.Name] = progress
+		}
+
+		if pool.IsInCondition(mcov1.MachineConfigPoolUpdating) {
+			if progress.Start.IsZero() {
+				progress.Start = now
+			}
+
+			progress.End = time.Time{}
+			done = false
+
+			continue
+		}
+
+		// The pool is done once it is updated to the latest rendered config.
+		if !pool.IsInCondition(mcov1.MachineConfigPoolUpdated) ||
+			pool.Object.Spec.Configuration.Name != pool.Object.Status.Configuration.Name {
+			done = false
+
+			continue
+		}
+
+		if !progress.Start.IsZero() && progress.End.IsZero() {
+			progress.finish(now)
+		}
+	}
+
+	return done, nil
+}
+
+func operatorCondition(clusterOperator *configv1.ClusterOperator,
+	conditionType configv1.ClusterStatusConditionType) bool {
+	for _, condition := range clusterOperator.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == configv1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func operatorVersion(clusterOperator *configv1.ClusterOperator) string {
+	for _, version := range clusterOperator.Status.Versions {
+		if version.Name == "operator" {
+			return version.Version
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,21 @@
+package ocpupgradeinittools
+
+import (
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/inittools"
+	"github.com/openshift-kni/eco-gosystem/tests/ocpupgrade/internal/ocpupgradeconfig"
+)
+
+var (
+	// APIClient provides API access to cluster.
+	APIClient *clients.Settings
+	// OcpUpgradeTestConfig provides access to OCP upgrade system tests configuration parameters.
+	OcpUpgradeTestConfig *ocpupgradeconfig.OcpUpgradeConfig
+)
+
+// init loads all variables automatically when this package is imported. Once package is imported a user has full
+// access to all vars within init function. It is recommended to import this package using dot import.
+func init() {
+	OcpUpgradeTestConfig = ocpupgradeconfig.NewOcpUpgradeConfig()
+	APIClient = inittools.APIClient
+}
@@ -0,0 +1,18 @@
+package ocpupgradeparams
+
+import "time"
+
+const (
+	// Label represents OCP upgrade system tests label that can be used for test cases selection.
+	Label = "ocpupgrade"
+	// LabelPlatformUpgrade represents tests labels related to the platform upgrade through ClusterVersion.
+	LabelPlatformUpgrade = "platform-upgrade"
+	// DefaultTimeout is the timeout used for test resources creation.
+	DefaultTimeout = 5 * time.Minute
+	// StabilityTimeout is the time given to the cluster to pass the stability gate.
+	StabilityTimeout = time.Hour
+	// TestWorkloadShellLaunchMethod is used when using a shell script for launching the test workload.
+	TestWorkloadShellLaunchMethod = "shell"
+	// ReportName is the name of the upgrade report written to the reports directory.
+	ReportName = "ocpupgrade"
+)
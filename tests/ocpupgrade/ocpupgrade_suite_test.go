@@ -0,0 +1,60 @@
+package ocpupgrade_system_test
+
+import (
+	"runtime"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/namespace"
+	"github.com/openshift-kni/eco-goinfra/pkg/polarion"
+	"github.com/openshift-kni/eco-goinfra/pkg/reporter"
+	. "github.com/openshift-kni/eco-gosystem/tests/internal/inittools"
+	systemtestsparams "github.com/openshift-kni/eco-gosystem/tests/internal/params"
+	"github.com/openshift-kni/eco-gosystem/tests/ocpupgrade/internal/ocpupgradeparams"
+	_ "github.com/openshift-kni/eco-gosystem/tests/ocpupgrade/tests"
+)
+
+var (
+	_, currentFile, _, _ = runtime.Caller(0)
+	testNS               = namespace.NewBuilder(APIClient, ocpupgradeparams.TestNamespaceName)
+)
+
+func TestOcpUpgrade(t *testing.T) {
+	_, reporterConfig := GinkgoConfiguration()
+	reporterConfig.JUnitReport = GeneralConfig.GetJunitReportPath(currentFile)
+
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "OCP Upgrade SystemTests Suite", Label(ocpupgradeparams.Labels...), reporterConfig)
+}
+
+var _ = BeforeSuite(func() {
+	if !testNS.Exists() {
+		By("Creating test namespace")
+
+		for key, value := range systemtestsparams.PrivilegedNSLabels {
+			testNS.WithLabel(key, value)
+		}
+
+		_, err := testNS.Create()
+		Expect(err).ToNot(HaveOccurred(), "error creating the test namespace")
+	}
+})
+
+var _ = AfterSuite(func() {
+	By("Deleting test namespace")
+	err := testNS.Delete()
+	Expect(err).ToNot(HaveOccurred(), "error deleting the test namespace")
+})
+
+var _ = JustAfterEach(func() {
+	reporter.ReportIfFailed(
+		CurrentSpecReport(), GeneralConfig.GetDumpFailedTestReportLocation(currentFile), GeneralConfig.ReportsDirAbsPath,
+		ocpupgradeparams.ReporterNamespacesToDump, ocpupgradeparams.ReporterCRDsToDump, clients.SetScheme)
+})
+
+var _ = ReportAfterSuite("", func(report Report) {
+	polarion.CreateReport(
+		report, GeneralConfig.GetPolarionReportPath(), GeneralConfig.PolarionTCPrefix)
+})
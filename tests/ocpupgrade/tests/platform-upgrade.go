@@ -0,0 +1,242 @@
+package ocpupgrade_system_test
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-goinfra/pkg/namespace"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/await"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/prober"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/shell"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/snapshot"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/workload"
+	"github.com/openshift-kni/eco-gosystem/tests/ocpupgrade/internal/ocpupgradehelper"
+	. "github.com/openshift-kni/eco-gosystem/tests/ocpupgrade/internal/ocpupgradeinittools"
+	"github.com/openshift-kni/eco-gosystem/tests/ocpupgrade/internal/ocpupgradeparams"
+)
+
+var _ = Describe(
+	"PlatformUpgrade",
+	Ordered,
+	ContinueOnFailure,
+	Label(ocpupgradeparams.LabelPlatformUpgrade), func() {
+		var (
+			baseline           workload.Baseline
+			stabilityDuration  time.Duration
+			preUpgradeSnapshot *snapshot.Snapshot
+		)
+
+		BeforeAll(func() {
+			if OcpUpgradeTestConfig.DesiredVersion == "" && OcpUpgradeTestConfig.DesiredImage == "" {
+				Skip("No desired version or release image configured")
+			}
+
+			var err error
+
+			stabilityDuration, err = time.ParseDuration(OcpUpgradeTestConfig.StabilityDuration)
+			Expect(err).ToNot(HaveOccurred(), "invalid stability duration")
+
+			By("Preparing workload")
+
+			workloadNamespace := OcpUpgradeTestConfig.TestWorkload.Namespace
+			if namespace.NewBuilder(APIClient, workloadNamespace).Exists() {
+				err := workload.CleanNamespace(APIClient, OcpUpgradeTestConfig.SriovOperatorNamespace,
+					workloadNamespace, ocpupgradeparams.DefaultTimeout)
+				Expect(err).ToNot(HaveOccurred(), "Failed to clean workload test namespace objects")
+			}
+
+			if OcpUpgradeTestConfig.TestWorkload.CreateMethod == ocpupgradeparams.TestWorkloadShellLaunchMethod {
+				By("Launching workload using shell method")
+				_, err := shell.ExecuteCmd(OcpUpgradeTestConfig.TestWorkload.CreateShellCmd)
+				Expect(err).ToNot(HaveOccurred(), "Failed to launch workload")
+			}
+
+			waitForWorkloadReady()
+
+			By("Check the cluster is stable before the upgrade")
+			err = cluster.WaitForStability(APIClient, stabilityDuration, ocpupgradeparams.StabilityTimeout)
+			Expect(err).ToNot(HaveOccurred(), "cluster is not stable before the upgrade")
+
+			By("Capture workload restart counts")
+			baseline, err = workload.CaptureBaseline(APIClient, workloadNamespace)
+			Expect(err).ToNot(HaveOccurred(), "error when capturing workload restart counts")
+
+			By("Take the pre upgrade cluster snapshot")
+			preUpgradeSnapshot, err = snapshot.Take(APIClient)
+			Expect(err).ToNot(HaveOccurred(), "error when taking the pre upgrade cluster snapshot")
+		})
+
+		It("Upgrade the platform through ClusterVersion", Label(ocpupgradeparams.LabelPlatformUpgrade), func() {
+			timeout, err := time.ParseDuration(OcpUpgradeTestConfig.Timeout)
+			Expect(err).ToNot(HaveOccurred(), "invalid upgrade timeout")
+
+			pollInterval, err := time.ParseDuration(OcpUpgradeTestConfig.PollInterval)
+			Expect(err).ToNot(HaveOccurred(), "invalid upgrade poll interval")
+
+			By("Start probing the workload continuity")
+			apiProber, continuityProbers := startContinuityProbers()
+
+			By("Set the ClusterVersion desired update")
+			report, err := ocpupgradehelper.StartUpgrade(APIClient, OcpUpgradeTestConfig.DesiredVersion,
+				OcpUpgradeTestConfig.DesiredImage, OcpUpgradeTestConfig.Force)
+			Expect(err).ToNot(HaveOccurred(), "error starting the upgrade")
+
+			By("Wait for the upgrade to complete")
+			upgradeErr := ocpupgradehelper.WaitForUpgrade(APIClient, report, pollInterval, timeout)
+
+			fmt.Fprint(GinkgoWriter, report.String())
+
+			err = report.WriteReport(OcpUpgradeTestConfig.ReportsDirAbsPath, ocpupgradeparams.ReportName)
+			Expect(err).ToNot(HaveOccurred(), "error writing the upgrade report")
+
+			// Persist upgrade metrics to ginkgo report for further processing in pipeline.
+			for metricName, metricValue := range report.Metrics() {
+				_, err := fmt.Fprintf(GinkgoWriter, "%s: %s\n", metricName, metricValue)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			Expect(upgradeErr).ToNot(HaveOccurred(), "upgrade not completed, pending %v", report.Pending())
+
+			if OcpUpgradeTestConfig.DesiredVersion != "" {
+				Expect(report.ToVersion).To(Equal(OcpUpgradeTestConfig.DesiredVersion), "unexpected upgrade version")
+			}
+
+			By("Wait for the workload to be ready after the upgrade")
+			waitForWorkloadReady()
+
+			verifyContinuity(apiProber, continuityProbers)
+		})
+
+		It("Verify workload integrity after the upgrade", Label(ocpupgradeparams.LabelPlatformUpgrade), func() {
+			maxRestartDelta, err := strconv.ParseInt(OcpUpgradeTestConfig.MaxRestartDelta, 10, 32)
+			Expect(err).ToNot(HaveOccurred(), "invalid max restart delta")
+
+			waitForWorkloadReady()
+
+			By("Validate workload integrity")
+			report, err := workload.Validate(APIClient, OcpUpgradeTestConfig.TestWorkload.Namespace, baseline,
+				workload.Options{MaxRestartDelta: int32(maxRestartDelta)})
+			Expect(err).ToNot(HaveOccurred(), "error when validating the workload")
+
+			fmt.Print(report.String())
+			Expect(report.Failures()).To(BeEmpty(), "workload integrity checks failed after the upgrade")
+		})
+
+		It("Verify the cluster is stable after the upgrade", Label(ocpupgradeparams.LabelPlatformUpgrade), func() {
+			err := cluster.WaitForStability(APIClient, stabilityDuration, ocpupgradeparams.StabilityTimeout)
+			Expect(err).ToNot(HaveOccurred(), "cluster is not stable after the upgrade")
+		})
+
+		It("Verify the cluster state changes after the upgrade", Label(ocpupgradeparams.LabelPlatformUpgrade), func() {
+			var report *snapshot.Report
+
+			// The workloads of the custom namespaces may still be recovering from the last node reboot.
+			Eventually(func() ([]string, error) {
+				postUpgradeSnapshot, err := snapshot.Take(APIClient)
+				if err != nil {
+					return nil, err
+				}
+
+				report, err = snapshot.Diff(preUpgradeSnapshot, postUpgradeSnapshot, snapshot.PlatformUpgradeRules())
+				if err != nil {
+					return nil, err
+				}
+
+				return report.Failures(), nil
+			}, ocpupgradeparams.DefaultTimeout, 30*time.Second).Should(BeEmpty(), func() string {
+				if report == nil {
+					return "failed to compare the cluster snapshots"
+				}
+
+				return "unexpected cluster state changes after the upgrade\n" + report.String()
+			})
+
+			fmt.Fprint(GinkgoWriter, report.String())
+		})
+
+		AfterAll(func() {
+			By("Cleaning up test workload resources")
+			err := workload.CleanNamespace(APIClient, OcpUpgradeTestConfig.SriovOperatorNamespace,
+				OcpUpgradeTestConfig.TestWorkload.Namespace, ocpupgradeparams.DefaultTimeout)
+			Expect(err).ToNot(HaveOccurred(), "Failed to clean workload test namespace objects")
+		})
+	})
+
+func waitForWorkloadReady() {
+	By("Waiting for deployment replicas to become ready")
+	_, err := await.WaitUntilAllDeploymentsReady(APIClient, OcpUpgradeTestConfig.TestWorkload.Namespace,
+		ocpupgradeparams.DefaultTimeout)
+	Expect(err).ToNot(HaveOccurred(), "error while waiting for deployment to become ready")
+
+	By("Waiting for statefulset replicas to become ready")
+	_, err = await.WaitUntilAllStatefulSetsReady(APIClient, OcpUpgradeTestConfig.TestWorkload.Namespace,
+		ocpupgradeparams.DefaultTimeout)
+	Expect(err).ToNot(HaveOccurred(), "error while waiting for statefulsets to become ready")
+}
+
+// startContinuityProbers starts probing the API, the workload pods readiness and the configured targets. The probers
+// are stopped and their downtime metrics are persisted to the ginkgo report at the end of the spec, even when the
+// upgrade fails.
+func startContinuityProbers() (*prober.Prober, []*prober.Prober) {
+	interval, err := time.ParseDuration(OcpUpgradeTestConfig.ProberInterval)
+	Expect(err).ToNot(HaveOccurred(), "invalid prober interval")
+
+	apiProber := prober.New("api", prober.APICheck(APIClient), interval)
+	continuityProbers := []*prober.Prober{prober.New("workload",
+		prober.PodsReadyCheck(APIClient, OcpUpgradeTestConfig.TestWorkload.Namespace), interval)}
+
+	for index, target := range strings.Split(OcpUpgradeTestConfig.ProberTargets, ",") {
+		if strings.TrimSpace(target) == "" {
+			continue
+		}
+
+		check, err := prober.ParseTarget(target)
+		Expect(err).ToNot(HaveOccurred(), "invalid prober target")
+
+		continuityProbers = append(continuityProbers, prober.New(fmt.Sprintf("target%d", index), check, interval))
+	}
+
+	probers := append([]*prober.Prober{apiProber}, continuityProbers...)
+
+	for _, continuityProber := range probers {
+		continuityProber.Start()
+	}
+
+	DeferCleanup(func() {
+		for _, continuityProber := range probers {
+			// Persist availability metrics to ginkgo report for further processing in pipeline.
+			for metricName, metricValue := range continuityProber.Stop().Metrics("platformupgrade") {
+				_, err := fmt.Fprintf(GinkgoWriter, "%s: %s\n", metricName, metricValue)
+				Expect(err).ToNot(HaveOccurred())
+			}
+		}
+	})
+
+	return apiProber, continuityProbers
+}
+
+// verifyContinuity stops the probers and asserts the configured max downtime of the workload and of the targets.
+// The API downtime is only reported.
+func verifyContinuity(apiProber *prober.Prober, continuityProbers []*prober.Prober) {
+	maxDowntime, err := time.ParseDuration(OcpUpgradeTestConfig.MaxDowntime)
+	Expect(err).ToNot(HaveOccurred(), "invalid max downtime")
+
+	apiResult := apiProber.Stop()
+	fmt.Fprintf(GinkgoWriter, "API unavailable for %s in %d windows during the upgrade\n", apiResult.Downtime,
+		len(apiResult.Windows))
+
+	var violations []string
+
+	for _, continuityProber := range continuityProbers {
+		if err := continuityProber.Stop().CheckDowntime(maxDowntime); err != nil {
+			violations = append(violations, err.Error())
+		}
+	}
+
+	Expect(violations).To(BeEmpty(), "workload continuity checks failed during the upgrade")
+}
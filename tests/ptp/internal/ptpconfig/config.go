@@ -0,0 +1,85 @@
+package ptpconfig
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/config"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// PathToDefaultPtpParamsFile path to config file with default ptp parameters.
+	PathToDefaultPtpParamsFile = "./default.yaml"
+)
+
+// PtpConfig type keeps ptp configuration.
+type PtpConfig struct {
+	*config.GeneralConfig
+	OperatorNamespace      string `yaml:"ptp_operator_namespace" envconfig:"ECO_PTP_OPERATOR_NAMESPACE"`
+	SyncTimeout            string `yaml:"ptp_sync_timeout" envconfig:"ECO_PTP_SYNC_TIMEOUT"`
+	MaxOffsetNs            string `yaml:"ptp_max_offset_ns" envconfig:"ECO_PTP_MAX_OFFSET_NS"`
+	OffsetSamplingDuration string `yaml:"ptp_offset_sampling_duration" envconfig:"ECO_PTP_OFFSET_SAMPLING_DURATION"`
+	RebootIterations       string `yaml:"ptp_reboot_iterations" envconfig:"ECO_PTP_REBOOT_ITERATIONS"`
+}
+
+// NewPtpConfig returns instance of PtpConfig config type.
+func NewPtpConfig() *PtpConfig {
+	log.Print("Creating new PtpConfig struct")
+
+	var ptpConf PtpConfig
+	ptpConf.GeneralConfig = config.NewConfig()
+
+	_, filename, _, _ := runtime.Caller(0)
+	baseDir := filepath.Dir(filename)
+	confFile := filepath.Join(baseDir, PathToDefaultPtpParamsFile)
+	err := readFile(&ptpConf, confFile)
+
+	if err != nil {
+		log.Printf("Error to read config file %s", confFile)
+
+		return nil
+	}
+
+	err = readEnv(&ptpConf)
+
+	if err != nil {
+		log.Print("Error to read environment variables")
+
+		return nil
+	}
+
+	return &ptpConf
+}
+
+func readFile(ptpConfig *PtpConfig, cfgFile string) error {
+	openedCfgFile, err := os.Open(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = openedCfgFile.Close()
+	}()
+
+	decoder := yaml.NewDecoder(openedCfgFile)
+	err = decoder.Decode(&ptpConfig)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func readEnv(ptpConfig *PtpConfig) error {
+	err := envconfig.Process("", ptpConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,371 @@
+package ptphelper
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	"github.com/openshift-kni/eco-gosystem/tests/ptp/internal/ptpparams"
+	ptpv1 "github.com/openshift/ptp-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// ClockState is the synchronization state of a PTP process.
+type ClockState string
+
+const (
+	// Locked is reported when the clock is synchronized to its source.
+	Locked ClockState = "LOCKED"
+	// Freerun is reported when the clock is not synchronized.
+	Freerun ClockState = "FREERUN"
+	// Holdover is reported when the clock lost its source and keeps its last frequency.
+	Holdover ClockState = "HOLDOVER"
+)
+
+const (
+	clockStateMetric = "openshift_ptp_clock_state"
+	offsetMetric     = "openshift_ptp_offset_ns"
+)
+
+var (
+	// metricRegex matches a prometheus metric sample, e.g.
+	// openshift_ptp_offset_ns{from="master",iface="ens1f0",node="sno",process="ptp4l"} -3.
+	metricRegex = regexp.MustCompile(`^(\w+)\{(.*)\}\s+(\S+)$`)
+	// logRegex matches the ptp4l and phc2sys offset lines, e.g.
+	// ptp4l[1234.567]: [ptp4l.0.config] master offset         -3 s2 freq  -1234 path delay   456
+	// phc2sys[1234.567]: [ptp4l.0.config] CLOCK_REALTIME phc offset  -5 s2 freq -1000 delay 500.
+	logRegex = regexp.MustCompile(`(ptp4l|phc2sys)\[[\d.]+\]:\s+(?:\[(\S+)\]\s+)?.*offset\s+(-?\d+)\s+(s\d)`)
+)
+
+// ProcessStatus is the synchronization status of a PTP process on an interface.
+type ProcessStatus struct {
+	Process   string     `json:"process"`
+	Interface string     `json:"interface"`
+	State     ClockState `json:"state"`
+	OffsetNs  float64    `json:"offsetNs"`
+}
+
+// SyncResult is the outcome of waiting for the PTP processes of a node to lock.
+type SyncResult struct {
+	Node            string          `json:"node"`
+	ConvergenceTime time.Duration   `json:"convergenceTime"`
+	MaxOffsetNs     float64         `json:"maxOffsetNs"`
+	Statuses        []ProcessStatus `json:"statuses"`
+}
+
+// Metrics returns the convergence time and max offset metrics of the result named after the given tag.
+func (result *SyncResult) Metrics(tag string) map[string]string {
+	return map[string]string{
+		fmt.Sprintf("%s_%s_%s", ptpparams.MetricConvergenceTime, result.Node, tag): strconv.FormatFloat(
+			result.ConvergenceTime.Seconds(), 'f', 0, 64),
+		fmt.Sprintf("%s_%s_%s", ptpparams.MetricMaxOffset, result.Node, tag): strconv.FormatFloat(
+			result.MaxOffsetNs, 'f', 0, 64),
+	}
+}
+
+// ListPtpConfigs returns the PtpConfigs of the PTP operator namespace.
+func ListPtpConfigs(apiClient *clients.Settings, nsname string) ([]ptpv1.PtpConfig, error) {
+	ptpConfigList, err := apiClient.PtpV1Interface.PtpConfigs(nsname).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		glog.V(100).Infof("Failed to list PtpConfigs in namespace %s: %s", nsname, err)
+
+		return nil, err
+	}
+
+	return ptpConfigList.Items, nil
+}
+
+// GetDaemonPod returns the linuxptp daemon pod running on a node.
+func GetDaemonPod(apiClient *clients.Settings, nsname, nodeName string) (*pod.Builder, error) {
+	podsList, err := pod.List(apiClient, nsname, metav1.ListOptions{
+		LabelSelector: ptpparams.DaemonPodLabel,
+		FieldSelector: fields.SelectorFromSet(fields.Set{"spec.nodeName": nodeName}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(podsList) == 0 {
+		return nil, fmt.Errorf("linuxptp daemon pod not found on node %s", nodeName)
+	}
+
+	return podsList[0], nil
+}
+
+// ListPtpNodes returns the names of the nodes whose linuxptp daemon runs at least one ptp4l process.
+func ListPtpNodes(apiClient *clients.Settings, nsname string) ([]string, error) {
+	podsList, err := pod.List(apiClient, nsname, metav1.ListOptions{LabelSelector: ptpparams.DaemonPodLabel})
+	if err != nil {
+		return nil, err
+	}
+
+	var nodeNames []string
+
+	for _, daemonPod := range podsList {
+		statuses, err := GetSyncStatus(daemonPod)
+		if err != nil {
+			glog.V(100).Infof("Failed to get PTP status of pod %s: %s", daemonPod.Object.Name, err)
+
+			continue
+		}
+
+		for _, status := range statuses {
+			if status.Process == "ptp4l" {
+				nodeNames = append(nodeNames, daemonPod.Object.Spec.NodeName)
+
+				break
+			}
+		}
+	}
+
+	return nodeNames, nil
+}
+
+// GetSyncStatus returns the synchronization status of the PTP processes of a linuxptp daemon pod. The daemon
+// metrics are used when available, otherwise the status is parsed from the recent daemon logs.
+func GetSyncStatus(daemonPod *pod.Builder) ([]ProcessStatus, error) {
+	output, err := daemonPod.ExecCommand([]string{"curl", "-s", ptpparams.MetricsURL}, ptpparams.DaemonContainerName)
+	if err == nil {
+		statuses := ParseMetrics(output.String())
+		if len(statuses) > 0 {
+			return statuses, nil
+		}
+	}
+
+	glog.V(100).Infof("PTP metrics not available on pod %s, falling back to logs: %v", daemonPod.Object.Name, err)
+
+	logs, err := daemonPod.GetLog(time.Minute, ptpparams.DaemonContainerName)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := ParseLogs(logs)
+	if len(statuses) == 0 {
+		return nil, fmt.Errorf("no PTP status found in pod %s metrics or logs", daemonPod.Object.Name)
+	}
+
+	return statuses, nil
+}
+
+// ParseMetrics parses the clock state and offset metrics exposed by the linuxptp daemon.
+func ParseMetrics(output string) []ProcessStatus {
+	statuses := map[string]*ProcessStatus{}
+
+	var keys []string
+
+	for _, line := range strings.Split(strings.ReplaceAll(output, "\r", ""), "\n") {
+		match := metricRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil || (match[1] != clockStateMetric && match[1] != offsetMetric) {
+			continue
+		}
+
+		metricLabels := parseLabels(match[2])
+
+		value, err := strconv.ParseFloat(match[3], 64)
+		if err != nil {
+			continue
+		}
+
+		key := metricLabels["process"] + "/" + metricLabels["iface"]
+		if _, found := statuses[key]; !found {
+			statuses[key] = &ProcessStatus{Process: metricLabels["process"], Interface: metricLabels["iface"]}
+			keys = append(keys, key)
+		}
+
+		if match[1] == clockStateMetric {
+			statuses[key].State = metricClockState(value)
+		} else {
+			statuses[key].OffsetNs = value
+		}
+	}
+
+	result := make([]ProcessStatus, 0, len(keys))
+
+	for _, key := range keys {
+		if statuses[key].State != "" {
+			result = append(result, *statuses[key])
+		}
+	}
+
+	return result
+}
+
+// ParseLogs parses the last offset and servo state of every ptp4l and phc2sys process in the daemon logs.
+// The servo state s2 is reported as LOCKED, s0 and s1 as FREERUN.
+func ParseLogs(logs string) []ProcessStatus {
+	statuses := map[string]*ProcessStatus{}
+
+	var keys []string
+
+	for _, line := range strings.Split(logs, "\n") {
+		match := logRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		offset, err := strconv.ParseFloat(match[3], 64)
+		if err != nil {
+			continue
+		}
+
+		key := match[1] + "/" + match[2]
+		if _, found := statuses[key]; !found {
+			statuses[key] = &ProcessStatus{Process: match[1], Interface: match[2]}
+			keys = append(keys, key)
+		}
+
+		statuses[key].OffsetNs = offset
+		statuses[key].State = Freerun
+
+		if match[4] == "s2" {
+			statuses[key].State = Locked
+		}
+	}
+
+	result := make([]ProcessStatus, 0, len(keys))
+
+	for _, key := range keys {
+		result = append(result, *statuses[key])
+	}
+
+	return result
+}
+
+// WaitForLocked waits until all the PTP processes of a node report the LOCKED state and returns the time it took
+// since start, which should be the time the node became Ready so that the reboot is not accounted. API and exec
+// errors are tolerated while waiting since the node may be recovering from a disruption.
+func WaitForLocked(apiClient *clients.Settings, nsname, nodeName string, start time.Time,
+	timeout time.Duration) (*SyncResult, error) {
+	result := &SyncResult{Node: nodeName}
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), ptpparams.PollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			daemonPod, err := GetDaemonPod(apiClient, nsname, nodeName)
+			if err != nil {
+				glog.V(100).Infof("Failed to get linuxptp daemon pod on node %s: %s", nodeName, err)
+
+				return false, nil
+			}
+
+			statuses, err := GetSyncStatus(daemonPod)
+			if err != nil {
+				glog.V(100).Infof("Failed to get PTP status on node %s: %s", nodeName, err)
+
+				return false, nil
+			}
+
+			result.Statuses = statuses
+
+			return allLocked(statuses), nil
+		})
+	if err != nil {
+		return result, fmt.Errorf("PTP did not lock on node %s within %s, last status %v: %w", nodeName, timeout,
+			result.Statuses, err)
+	}
+
+	result.ConvergenceTime = time.Since(start)
+
+	glog.V(90).Infof("PTP locked on node %s after %s", nodeName, result.ConvergenceTime)
+
+	return result, nil
+}
+
+// MeasureMaxOffset samples the PTP offsets of a node for the given duration and records the max absolute offset
+// in the result. A sample failing to be read is skipped, an error is returned after MaxSampleErrors failed samples
+// in a row, when no sample could be read or if a process leaves the LOCKED state during the sampling.
+func MeasureMaxOffset(apiClient *clients.Settings, nsname string, result *SyncResult, duration time.Duration) error {
+	end := time.Now().Add(duration)
+	samples, sampleErrors := 0, 0
+
+	for {
+		statuses, err := sampleSyncStatus(apiClient, nsname, result.Node)
+
+		switch {
+		case err != nil:
+			sampleErrors++
+
+			glog.V(100).Infof("Skipping PTP offset sample %d on node %s: %s", samples+sampleErrors, result.Node, err)
+
+			if sampleErrors >= ptpparams.MaxSampleErrors {
+				return fmt.Errorf("failed to sample PTP offsets on node %s %d times in a row: %w", result.Node,
+					sampleErrors, err)
+			}
+		case !allLocked(statuses):
+			return fmt.Errorf("PTP lost lock on node %s: %v", result.Node, statuses)
+		default:
+			samples++
+			sampleErrors = 0
+
+			for _, status := range statuses {
+				result.MaxOffsetNs = math.Max(result.MaxOffsetNs, math.Abs(status.OffsetNs))
+			}
+		}
+
+		if !time.Now().Before(end) {
+			if samples == 0 {
+				return fmt.Errorf("no PTP offset sample could be read on node %s", result.Node)
+			}
+
+			return nil
+		}
+
+		time.Sleep(ptpparams.PollInterval)
+	}
+}
+
+func sampleSyncStatus(apiClient *clients.Settings, nsname, nodeName string) ([]ProcessStatus, error) {
+	daemonPod, err := GetDaemonPod(apiClient, nsname, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetSyncStatus(daemonPod)
+}
+
+func allLocked(statuses []ProcessStatus) bool {
+	if len(statuses) == 0 {
+		return false
+	}
+
+	for _, status := range statuses {
+		if status.State != Locked {
+			return false
+		}
+	}
+
+	return true
+}
+
+// metricClockState converts the value of the clock state metric: 0 FREERUN, 1 LOCKED, 2 HOLDOVER.
+func metricClockState(value float64) ClockState {
+	switch value {
+	case 1:
+		return Locked
+	case 2:
+		return Holdover
+	default:
+		return Freerun
+	}
+}
+
+func parseLabels(content string) map[string]string {
+	metricLabels := map[string]string{}
+
+	for _, pair := range strings.Split(content, ",") {
+		key, value, found := strings.Cut(pair, "=")
+		if found {
+			metricLabels[strings.TrimSpace(key)] = strings.Trim(value, `"`)
+		}
+	}
+
+	return metricLabels
+}
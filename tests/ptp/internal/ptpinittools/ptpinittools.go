@@ -0,0 +1,21 @@
+package ptpinittools
+
+import (
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/inittools"
+	"github.com/openshift-kni/eco-gosystem/tests/ptp/internal/ptpconfig"
+)
+
+var (
+	// APIClient provides API access to cluster.
+	APIClient *clients.Settings
+	// PtpTestConfig provides access to PTP system tests configuration parameters.
+	PtpTestConfig *ptpconfig.PtpConfig
+)
+
+// init loads all variables automatically when this package is imported. Once package is imported a user has full
+// access to all vars within init function. It is recommended to import this package using dot import.
+func init() {
+	PtpTestConfig = ptpconfig.NewPtpConfig()
+	APIClient = inittools.APIClient
+}
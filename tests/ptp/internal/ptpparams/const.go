@@ -0,0 +1,26 @@
+package ptpparams
+
+import "time"
+
+const (
+	// Label represents PTP system tests label that can be used for test cases selection.
+	Label = "ptp"
+	// LabelPtpSync represents tests labels related to the PTP synchronization health.
+	LabelPtpSync = "ptp-sync"
+	// DaemonPodLabel is the label selector of the linuxptp daemon pods.
+	DaemonPodLabel = "app=linuxptp-daemon"
+	// DaemonContainerName is the name of the linuxptp daemon container.
+	DaemonContainerName = "linuxptp-daemon-container"
+	// MetricsURL is the address the linuxptp daemon exposes its metrics on from the host network.
+	MetricsURL = "http://localhost:9091/metrics"
+	// PollInterval is the interval between two reads of the PTP synchronization state.
+	PollInterval = 5 * time.Second
+	// MaxSampleErrors is the number of offset samples in a row allowed to fail, e.g. on transient exec errors.
+	MaxSampleErrors = 3
+	// RebootTimeout is the time given to a node to reboot and become ready again.
+	RebootTimeout = 30 * time.Minute
+	// MetricConvergenceTime is the prefix of the metric reporting the time taken to reach the LOCKED state.
+	MetricConvergenceTime = "ranmetrics_ptp_convergence_seconds"
+	// MetricMaxOffset is the prefix of the metric reporting the max absolute offset once LOCKED.
+	MetricMaxOffset = "ranmetrics_ptp_max_offset_ns"
+)
@@ -0,0 +1,143 @@
+package ptp_system_test
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/await"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/reboot"
+	"github.com/openshift-kni/eco-gosystem/tests/ptp/internal/ptphelper"
+	. "github.com/openshift-kni/eco-gosystem/tests/ptp/internal/ptpinittools"
+	"github.com/openshift-kni/eco-gosystem/tests/ptp/internal/ptpparams"
+)
+
+var _ = Describe(
+	"PtpSync",
+	Ordered,
+	ContinueOnFailure,
+	Label(ptpparams.LabelPtpSync), func() {
+		var (
+			ptpNodes               []string
+			syncTimeout            time.Duration
+			offsetSamplingDuration time.Duration
+			maxOffsetNs            float64
+			rebootIterations       int
+		)
+
+		BeforeAll(func() {
+			var err error
+
+			syncTimeout, err = time.ParseDuration(PtpTestConfig.SyncTimeout)
+			Expect(err).ToNot(HaveOccurred(), "invalid PTP sync timeout")
+
+			offsetSamplingDuration, err = time.ParseDuration(PtpTestConfig.OffsetSamplingDuration)
+			Expect(err).ToNot(HaveOccurred(), "invalid PTP offset sampling duration")
+
+			maxOffsetNs, err = strconv.ParseFloat(PtpTestConfig.MaxOffsetNs, 64)
+			Expect(err).ToNot(HaveOccurred(), "invalid PTP max offset")
+
+			rebootIterations, err = strconv.Atoi(PtpTestConfig.RebootIterations)
+			Expect(err).ToNot(HaveOccurred(), "invalid PTP reboot iterations")
+
+			By("Discover PtpConfigs")
+			ptpConfigs, err := ptphelper.ListPtpConfigs(APIClient, PtpTestConfig.OperatorNamespace)
+			Expect(err).ToNot(HaveOccurred(), "error listing PtpConfigs")
+
+			if len(ptpConfigs) == 0 {
+				Skip("No PtpConfig found")
+			}
+
+			for _, ptpConfig := range ptpConfigs {
+				fmt.Fprintf(GinkgoWriter, "found PtpConfig %s with %d profiles\n", ptpConfig.Name,
+					len(ptpConfig.Spec.Profile))
+			}
+
+			By("Discover nodes running ptp4l")
+			ptpNodes, err = ptphelper.ListPtpNodes(APIClient, PtpTestConfig.OperatorNamespace)
+			Expect(err).ToNot(HaveOccurred(), "error listing linuxptp daemon pods")
+
+			if len(ptpNodes) == 0 {
+				Skip("No node runs ptp4l")
+			}
+		})
+
+		It("Verify PTP is locked", Label(ptpparams.LabelPtpSync), func() {
+			for _, nodeName := range ptpNodes {
+				verifySync(nodeName, time.Now(), syncTimeout, offsetSamplingDuration, maxOffsetNs, "steadystate")
+			}
+		})
+
+		It("Verify PTP locks after soft reboot", Label(ptpparams.LabelPtpSync), func() {
+			for iteration := 0; iteration < rebootIterations; iteration++ {
+				for _, nodeName := range ptpNodes {
+					node, err := nodes.Pull(APIClient, nodeName)
+					Expect(err).ToNot(HaveOccurred(), "error pulling node %s", nodeName)
+
+					bootID := node.Object.Status.NodeInfo.BootID
+
+					By(fmt.Sprintf("Soft reboot node %s", nodeName))
+					err = reboot.SoftRebootNode(nodeName)
+					Expect(err).ToNot(HaveOccurred(), "Error rebooting the nodes.")
+
+					By("Wait for node to become unreachable")
+					err = await.WaitUntilNodeIsUnreachable(nodeName, 3*time.Minute)
+					Expect(err).ToNot(HaveOccurred(), "Node is still reachable: %s", err)
+
+					By("Wait for node to reboot and become ready")
+					err = reboot.WaitUntilNodeRebooted(nodeName, bootID, ptpparams.RebootTimeout)
+					Expect(err).ToNot(HaveOccurred(), "node %s did not become ready after reboot", nodeName)
+
+					// The convergence time is measured from the node being Ready, excluding the reboot itself.
+					verifySync(nodeName, time.Now(), syncTimeout, offsetSamplingDuration, maxOffsetNs, "softreboot")
+				}
+			}
+		})
+
+		It("Verify PTP locks after hard reboot", Label(ptpparams.LabelPtpSync), func() {
+			for iteration := 0; iteration < rebootIterations; iteration++ {
+				for _, nodeName := range ptpNodes {
+					node, err := nodes.Pull(APIClient, nodeName)
+					Expect(err).ToNot(HaveOccurred(), "error pulling node %s", nodeName)
+
+					bootID := node.Object.Status.NodeInfo.BootID
+
+					By(fmt.Sprintf("Hard reboot node %s", nodeName))
+					err = reboot.HardRebootNode(nodeName, ptpparams.TestNamespaceName)
+					Expect(err).ToNot(HaveOccurred(), "Error rebooting the nodes.")
+
+					By("Wait for node to reboot and become ready")
+					err = reboot.WaitUntilNodeRebooted(nodeName, bootID, ptpparams.RebootTimeout)
+					Expect(err).ToNot(HaveOccurred(), "node %s did not become ready after reboot", nodeName)
+
+					// The convergence time is measured from the node being Ready, excluding the reboot itself.
+					verifySync(nodeName, time.Now(), syncTimeout, offsetSamplingDuration, maxOffsetNs, "hardreboot")
+				}
+			}
+		})
+	})
+
+// verifySync waits for the PTP processes of a node to lock, samples their offsets, persists the convergence time
+// and max offset metrics to the ginkgo report and asserts the max offset threshold.
+func verifySync(nodeName string, start time.Time, syncTimeout, samplingDuration time.Duration, maxOffsetNs float64,
+	tag string) {
+	By(fmt.Sprintf("Wait for PTP to lock on node %s", nodeName))
+	result, err := ptphelper.WaitForLocked(APIClient, PtpTestConfig.OperatorNamespace, nodeName, start, syncTimeout)
+	Expect(err).ToNot(HaveOccurred(), "PTP did not lock in time")
+
+	By(fmt.Sprintf("Sample PTP offsets on node %s", nodeName))
+	err = ptphelper.MeasureMaxOffset(APIClient, PtpTestConfig.OperatorNamespace, result, samplingDuration)
+	Expect(err).ToNot(HaveOccurred(), "error sampling PTP offsets")
+
+	// Persist PTP metrics to ginkgo report for further processing in pipeline.
+	for metricName, metricValue := range result.Metrics(tag) {
+		_, err := fmt.Fprintf(GinkgoWriter, "%s: %s\n", metricName, metricValue)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	Expect(result.MaxOffsetNs).To(BeNumerically("<=", maxOffsetNs),
+		"PTP offset on node %s above threshold", nodeName)
+}
@@ -28,6 +28,33 @@ type RanDuConfig struct {
 	HardRebootIterations     string `yaml:"hard_reboot_iterations" envconfig:"ECO_RANDU_HARD_REBOOT_ITERATIONS"`
 	IpmiToolImage            string `yaml:"ipmitool_image" envconfig:"ECO_RANDU_IPMITOOL_IMAGE"`
 	LaunchWorkloadIterations string `yaml:"launch_workload_iterations" envconfig:"ECO_RANDU_LAUNCH_WORKLOAD_ITERATIONS"`
+	NodeSamplerInterval      string `yaml:"node_sampler_interval" envconfig:"ECO_RANDU_NODE_SAMPLER_INTERVAL"`
+	NodeSamplerDuration      string `yaml:"node_sampler_duration" envconfig:"ECO_RANDU_NODE_SAMPLER_DURATION"`
+	SoakDuration             string `yaml:"soak_duration" envconfig:"ECO_RANDU_SOAK_DURATION"`
+	SoakInterval             string `yaml:"soak_interval" envconfig:"ECO_RANDU_SOAK_INTERVAL"`
+	SoakResume               bool   `yaml:"soak_resume" envconfig:"ECO_RANDU_SOAK_RESUME"`
+	SoakMaxRestarts          int    `yaml:"soak_max_restarts" envconfig:"ECO_RANDU_SOAK_MAX_RESTARTS"`
+	SoakMaxOOMKills          int    `yaml:"soak_max_oom_kills" envconfig:"ECO_RANDU_SOAK_MAX_OOM_KILLS"`
+	SoakMaxReadinessFlaps    int    `yaml:"soak_max_readiness_flaps" envconfig:"ECO_RANDU_SOAK_MAX_READINESS_FLAPS"`
+	SoakMaxPressureEvents    int    `yaml:"soak_max_pressure_events" envconfig:"ECO_RANDU_SOAK_MAX_PRESSURE_EVENTS"`
+	DisruptionDowntime       string `yaml:"disruption_downtime" envconfig:"ECO_RANDU_DISRUPTION_DOWNTIME"`
+	DisruptionTimeout        string `yaml:"disruption_recovery_timeout" envconfig:"ECO_RANDU_DISRUPTION_RECOVERY_TIMEOUT"`
+	DisruptionKillProcess    string `yaml:"disruption_kill_process" envconfig:"ECO_RANDU_DISRUPTION_KILL_PROCESS"`
+	DisruptionInterface      string `yaml:"disruption_interface" envconfig:"ECO_RANDU_DISRUPTION_INTERFACE"`
+	ControlPlaneTimeout      string `yaml:"controlplane_recovery_timeout" envconfig:"ECO_RANDU_CONTROLPLANE_TIMEOUT"`
+	ControlPlaneRecoverySLO  string `yaml:"controlplane_recovery_slo" envconfig:"ECO_RANDU_CONTROLPLANE_RECOVERY_SLO"`
+	ControlPlaneAPISLO       string `yaml:"controlplane_api_slo" envconfig:"ECO_RANDU_CONTROLPLANE_API_SLO"`
+	ProberInterval           string `yaml:"prober_interval" envconfig:"ECO_RANDU_PROBER_INTERVAL"`
+	ProberTargets            string `yaml:"prober_targets" envconfig:"ECO_RANDU_PROBER_TARGETS"`
+	ProberMaxDowntime        string `yaml:"prober_max_downtime" envconfig:"ECO_RANDU_PROBER_MAX_DOWNTIME"`
+	TimeSyncMaxOffset        string `yaml:"timesync_max_offset" envconfig:"ECO_RANDU_TIMESYNC_MAX_OFFSET"`
+	TimeSyncTimeout          string `yaml:"timesync_timeout" envconfig:"ECO_RANDU_TIMESYNC_TIMEOUT"`
+	PressureImage            string `yaml:"pressure_image" envconfig:"ECO_RANDU_PRESSURE_IMAGE"`
+	PressureTimeout          string `yaml:"pressure_timeout" envconfig:"ECO_RANDU_PRESSURE_TIMEOUT"`
+	PressureDiskPath         string `yaml:"pressure_disk_path" envconfig:"ECO_RANDU_PRESSURE_DISK_PATH"`
+	ShutdownOffDuration      string `yaml:"shutdown_off_duration" envconfig:"ECO_RANDU_SHUTDOWN_OFF_DURATION"`
+	ShutdownCertValidity     string `yaml:"shutdown_cert_validity" envconfig:"ECO_RANDU_SHUTDOWN_CERT_VALIDITY"`
+	ShutdownApproveCSRs      bool   `yaml:"shutdown_approve_csrs" envconfig:"ECO_RANDU_SHUTDOWN_APPROVE_CSRS"`
 }
 
 // NewRanDuConfig returns instance of RanDuConfig config type.
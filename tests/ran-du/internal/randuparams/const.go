@@ -13,4 +13,45 @@ const (
 	DefaultTimeout = 300 * time.Second
 	// TestWorkloadShellLaunchMethod is used when usin a shell script for launching the test workload.
 	TestWorkloadShellLaunchMethod = "shell"
+	// RebootTimeout is the time given to a node to reboot and become ready again.
+	RebootTimeout = 30 * time.Minute
+	// RebootMaxRestartDelta is the number of workload container restarts tolerated after a node reboot.
+	RebootMaxRestartDelta = 2
+	// NodeSamplerMaxErrorRatio is the ratio of failed node sampling attempts tolerated.
+	NodeSamplerMaxErrorRatio = 0.1
+	// LabelSoakTestCases represents tests labels related to the long-running soak test.
+	LabelSoakTestCases = "soak"
+	// DefaultLabelFilter excludes the opt-in test cases, the long-running soak and the node resource pressure
+	// which deliberately stresses the node, from the runs without label filter. They only run when selected by
+	// their label.
+	DefaultLabelFilter = "!" + LabelSoakTestCases + " && !" + LabelPressureTestCases
+	// LabelLatencyTestCases represents tests labels related to the real-time latency tests.
+	LabelLatencyTestCases = "latency"
+	// LabelDisruptionTestCases represents tests labels related to the node service disruptions.
+	LabelDisruptionTestCases = "disruption"
+	// DisruptionMaxRestartDelta is the number of workload container restarts tolerated after a node service
+	// disruption.
+	DisruptionMaxRestartDelta = 2
+	// LabelControlPlaneTestCases represents tests labels related to the control plane disruptions.
+	LabelControlPlaneTestCases = "controlplane"
+	// LabelTimeSyncTestCases represents tests labels related to the time synchronization.
+	LabelTimeSyncTestCases = "timesync"
+	// LabelPressureTestCases represents tests labels related to the node resource pressure.
+	LabelPressureTestCases = "pressure"
+	// PressureBystanderFill is the memory filled by the bystander pods of the memory pressure test.
+	PressureBystanderFill = 32 * 1024 * 1024
+	// LabelShutdownTestCases represents tests labels related to the graceful cluster shutdown.
+	LabelShutdownTestCases = "shutdown"
+	// ShutdownDelay is the delay of the graceful shutdown command, as documented.
+	ShutdownDelay = time.Minute
+	// ShutdownTimeout is the time given to drain the node, to shut it down and to power it off.
+	ShutdownTimeout = 10 * time.Minute
+	// ShutdownStableDuration is the time the cluster must be stable in a row after the restart.
+	ShutdownStableDuration = 5 * time.Minute
+	// ShutdownStabilityTimeout is the time given to the cluster to be stable after the restart.
+	ShutdownStabilityTimeout = 45 * time.Minute
+	// SoakCheckpointFile is the name of the soak progress checkpoint written to the reports directory.
+	SoakCheckpointFile = "randu_soak_checkpoint.json"
+	// SoakMaxConsecutiveProbeErrors is the number of probe rounds in a row allowed to fail, e.g. on API hiccups.
+	SoakMaxConsecutiveProbeErrors = 5
 )
@@ -23,11 +23,15 @@ var (
 )
 
 func TestRanDu(t *testing.T) {
-	_, reporterConfig := GinkgoConfiguration()
+	suiteConfig, reporterConfig := GinkgoConfiguration()
 	reporterConfig.JUnitReport = GeneralConfig.GetJunitReportPath(currentFile)
 
+	if suiteConfig.LabelFilter == "" {
+		suiteConfig.LabelFilter = randuparams.DefaultLabelFilter
+	}
+
 	RegisterFailHandler(Fail)
-	RunSpecs(t, "RanDU SystemTests Suite", Label(randuparams.Labels...), reporterConfig)
+	RunSpecs(t, "RanDU SystemTests Suite", Label(randuparams.Labels...), suiteConfig, reporterConfig)
 }
 
 var _ = BeforeSuite(func() {
@@ -0,0 +1,67 @@
+package ran_du_system_test
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/prober"
+	. "github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuinittools"
+)
+
+// startAvailabilityProbers starts probing the API and the configured targets. The probers are stopped and their
+// downtime metrics named after the tag are persisted to the ginkgo report at the end of the spec, even when the
+// spec fails before verifying them.
+func startAvailabilityProbers(tag string) []*prober.Prober {
+	interval, err := time.ParseDuration(RanDuTestConfig.ProberInterval)
+	Expect(err).ToNot(HaveOccurred(), "invalid prober interval")
+
+	probers := []*prober.Prober{prober.New("api", prober.APICheck(APIClient), interval).WithTimeout(2 * time.Second)}
+
+	for index, target := range strings.Split(RanDuTestConfig.ProberTargets, ",") {
+		if strings.TrimSpace(target) == "" {
+			continue
+		}
+
+		check, err := prober.ParseTarget(target)
+		Expect(err).ToNot(HaveOccurred(), "invalid prober target")
+
+		probers = append(probers, prober.New(fmt.Sprintf("target%d", index), check, interval).
+			WithTimeout(2*time.Second))
+	}
+
+	for _, availabilityProber := range probers {
+		availabilityProber.Start()
+	}
+
+	DeferCleanup(func() {
+		for _, availabilityProber := range probers {
+			// Persist availability metrics to ginkgo report for further processing in pipeline.
+			for metricName, metricValue := range availabilityProber.Stop().Metrics(tag) {
+				_, err := fmt.Fprintf(GinkgoWriter, "%s: %s\n", metricName, metricValue)
+				Expect(err).ToNot(HaveOccurred())
+			}
+		}
+	})
+
+	return probers
+}
+
+// verifyAvailability stops the probers and asserts the configured max downtime, which must be positive.
+func verifyAvailability(probers []*prober.Prober, tag string) {
+	maxDowntime, err := time.ParseDuration(RanDuTestConfig.ProberMaxDowntime)
+	Expect(err).ToNot(HaveOccurred(), "invalid prober max downtime")
+	Expect(maxDowntime).To(BeNumerically(">", 0), "prober max downtime must be positive")
+
+	var violations []string
+
+	for _, availabilityProber := range probers {
+		if err := availabilityProber.Stop().CheckDowntime(maxDowntime); err != nil {
+			violations = append(violations, err.Error())
+		}
+	}
+
+	Expect(violations).To(BeEmpty(), "availability checks failed after %s", tag)
+}
@@ -0,0 +1,49 @@
+package ran_du_system_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/snapshot"
+	. "github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuinittools"
+	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuparams"
+)
+
+// takeSnapshot takes the snapshot of the cluster before a disruption.
+func takeSnapshot() *snapshot.Snapshot {
+	clusterSnapshot, err := snapshot.Take(APIClient)
+	Expect(err).ToNot(HaveOccurred(), "Failed to take cluster snapshot")
+
+	return clusterSnapshot
+}
+
+// verifySnapshot waits until the snapshot of the cluster differs from the one taken before the disruption only as
+// allowed by the rules, and writes the diff report to the ginkgo report.
+func verifySnapshot(before *snapshot.Snapshot, rules []snapshot.Rule, tag string) {
+	var report *snapshot.Report
+
+	// The workloads of the custom namespaces may still be recovering from the disruption.
+	Eventually(func() ([]string, error) {
+		after, err := snapshot.Take(APIClient)
+		if err != nil {
+			return nil, err
+		}
+
+		report, err = snapshot.Diff(before, after, rules)
+		if err != nil {
+			return nil, err
+		}
+
+		return report.Failures(), nil
+	}, randuparams.DefaultTimeout, 30*time.Second).Should(BeEmpty(), func() string {
+		if report == nil {
+			return "Failed to compare cluster snapshots"
+		}
+
+		return fmt.Sprintf("Unexpected cluster state changes after %s\n%s", tag, report.String())
+	})
+
+	fmt.Fprint(GinkgoWriter, report.String())
+}
@@ -0,0 +1,80 @@
+package ran_du_system_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-goinfra/pkg/deployment"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/controlplane"
+	. "github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuinittools"
+	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuparams"
+)
+
+var _ = Describe(
+	"ControlPlaneDisruption",
+	Ordered,
+	ContinueOnFailure,
+	Label(randuparams.LabelControlPlaneTestCases), func() {
+		var (
+			nodeName        string
+			recoveryTimeout time.Duration
+			slo             controlplane.SLO
+		)
+
+		BeforeAll(func() {
+			var err error
+
+			recoveryTimeout, err = time.ParseDuration(RanDuTestConfig.ControlPlaneTimeout)
+			Expect(err).ToNot(HaveOccurred(), "invalid control plane recovery timeout")
+
+			slo.MaxRecoveryTime, err = time.ParseDuration(RanDuTestConfig.ControlPlaneRecoverySLO)
+			Expect(err).ToNot(HaveOccurred(), "invalid control plane recovery SLO")
+
+			slo.MaxAPIUnavailability, err = time.ParseDuration(RanDuTestConfig.ControlPlaneAPISLO)
+			Expect(err).ToNot(HaveOccurred(), "invalid control plane API unavailability SLO")
+
+			cluster.RequireCapabilities(APIClient, nil, cluster.RequiresTopology(cluster.TopologySNO))
+
+			By("Retrieve nodes list")
+			nodeList, err := nodes.List(APIClient)
+			Expect(err).ToNot(HaveOccurred(), "Error listing nodes.")
+
+			nodeName = nodeList[0].Definition.Name
+		})
+
+		for _, component := range controlplane.Components {
+			component := component
+
+			It(fmt.Sprintf("Verify %s recovers from a forced restart", component.Name),
+				Label(randuparams.LabelControlPlaneTestCases), func() {
+					By(fmt.Sprintf("Restart %s on node %s", component.Name, nodeName))
+					result, err := controlplane.RestartComponent(APIClient, component, nodeName, recoveryTimeout)
+					Expect(err).ToNot(HaveOccurred(), "%s did not recover", component.Name)
+
+					for _, outage := range result.Outages {
+						fmt.Fprintf(GinkgoWriter, "API unavailable from %s for %s\n",
+							outage.Start.Format(time.RFC3339), outage.Duration())
+					}
+
+					// Persist control plane metrics to ginkgo report for further processing in pipeline.
+					for metricName, metricValue := range result.Metrics("restart") {
+						_, err := fmt.Fprintf(GinkgoWriter, "%s: %s\n", metricName, metricValue)
+						Expect(err).ToNot(HaveOccurred())
+					}
+
+					By("Wait for the openshift apiserver deployment to be available")
+					deploy, err := deployment.Pull(APIClient, "apiserver", "openshift-apiserver")
+					Expect(err).ToNot(HaveOccurred(), "error while pulling openshift apiserver deployment")
+
+					err = deploy.WaitUntilCondition("Available", 5*time.Minute)
+					Expect(err).ToNot(HaveOccurred(), "openshift apiserver deployment has not recovered in time")
+
+					err = result.CheckSLO(slo)
+					Expect(err).ToNot(HaveOccurred(), "control plane recovery SLO not met")
+				})
+		}
+	})
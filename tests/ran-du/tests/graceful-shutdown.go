@@ -0,0 +1,214 @@
+package ran_du_system_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-goinfra/pkg/namespace"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/await"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/bmc"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/certs"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/shell"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/shutdown"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/workload"
+	. "github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuinittools"
+	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuparams"
+	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randutestworkload"
+)
+
+var _ = Describe(
+	"GracefulShutdown",
+	Ordered,
+	ContinueOnFailure,
+	Label(randuparams.LabelShutdownTestCases), func() {
+		var (
+			node         *nodes.Builder
+			bmcBackend   bmc.Backend
+			offDuration  time.Duration
+			certValidity time.Duration
+		)
+
+		BeforeAll(func() {
+			var err error
+
+			offDuration, err = time.ParseDuration(RanDuTestConfig.ShutdownOffDuration)
+			Expect(err).ToNot(HaveOccurred(), "invalid shutdown off duration")
+
+			certValidity, err = time.ParseDuration(RanDuTestConfig.ShutdownCertValidity)
+			Expect(err).ToNot(HaveOccurred(), "invalid shutdown certificate validity")
+
+			cluster.RequireCapabilities(APIClient, RanDuTestConfig.GeneralConfig,
+				cluster.RequiresTopology(cluster.TopologySNO), cluster.RequiresBMC())
+
+			By("Retrieve nodes list")
+			nodeList, err := nodes.List(APIClient)
+			Expect(err).ToNot(HaveOccurred(), "Error listing nodes.")
+
+			node = nodeList[0]
+
+			bmcBackend, err = bmc.NewIPMIFromConfig(RanDuTestConfig.GeneralConfig)
+			Expect(err).ToNot(HaveOccurred(), "Failed to get the BMC backend")
+
+			By("Preparing workload")
+
+			if namespace.NewBuilder(APIClient, RanDuTestConfig.TestWorkload.Namespace).Exists() {
+				err := randutestworkload.CleanNameSpace(randuparams.DefaultTimeout, RanDuTestConfig.TestWorkload.Namespace)
+				Expect(err).ToNot(HaveOccurred(), "Failed to clean workload test namespace objects")
+			}
+
+			if RanDuTestConfig.TestWorkload.CreateMethod == randuparams.TestWorkloadShellLaunchMethod {
+				By("Launching workload using shell method")
+				_, err := shell.ExecuteCmd(RanDuTestConfig.TestWorkload.CreateShellCmd)
+				Expect(err).ToNot(HaveOccurred(), "Failed to launch workload")
+			}
+
+			waitForWorkloadReady()
+		})
+
+		It("Verify cluster recovers from a graceful shutdown and power on", Label(randuparams.LabelShutdownTestCases),
+			func() {
+				nodeName := node.Definition.Name
+				bootID := node.Object.Status.NodeInfo.BootID
+				report := shutdown.NewReport(nodeName)
+
+				By("Verify the cluster certificates outlive the shutdown")
+				certificates, err := certs.ListManagedCertificates(APIClient)
+				Expect(err).ToNot(HaveOccurred(), "error listing cluster certificates")
+				Expect(certs.CheckExpiry(certificates, offDuration+certValidity)).To(BeEmpty(),
+					"cluster certificates expire before the end of the shutdown")
+
+				DeferCleanup(func() {
+					By("Make sure the node is powered on and schedulable")
+					Expect(bmcBackend.PowerOn()).To(Succeed(), "error powering node %s on", nodeName)
+
+					_, err := shutdown.WaitForRestart(APIClient, nodeName, "", RanDuTestConfig.ShutdownApproveCSRs,
+						randuparams.RebootTimeout)
+					Expect(err).ToNot(HaveOccurred(), "node %s is not ready", nodeName)
+
+					restartedNode, err := nodes.Pull(APIClient, nodeName)
+					Expect(err).ToNot(HaveOccurred(), "error pulling node %s", nodeName)
+					Expect(restartedNode.Uncordon()).To(Succeed(), "error uncordoning node %s", nodeName)
+				})
+
+				By(fmt.Sprintf("Cordon node %s", nodeName))
+				err = report.Run("cordon", node.Cordon)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Drain non-critical workloads")
+				err = report.Run("drain", func() error {
+					return shutdown.DrainNonCritical(APIClient, nodeName, randuparams.ShutdownTimeout, GinkgoWriter)
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Shut the node down and wait for it to be powered off")
+				err = report.Run("shutdown", func() error {
+					err := shutdown.Shutdown(nodeName, randuparams.ShutdownDelay)
+					if err != nil {
+						return err
+					}
+
+					err = await.WaitUntilNodeIsUnreachable(nodeName, randuparams.ShutdownDelay+randuparams.ShutdownTimeout)
+					if err != nil {
+						return err
+					}
+
+					return bmc.WaitForPowerState(bmcBackend, bmc.PowerOff, randuparams.ShutdownTimeout)
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				By(fmt.Sprintf("Keep the node powered off for %s", offDuration))
+				time.Sleep(offDuration)
+
+				var approved []string
+
+				By("Power the node on through the BMC and wait for it to be ready")
+				err = report.Run("poweron", func() error {
+					err := bmcBackend.PowerOn()
+					if err != nil {
+						return err
+					}
+
+					approved, err = shutdown.WaitForRestart(APIClient, nodeName, bootID,
+						RanDuTestConfig.ShutdownApproveCSRs, randuparams.RebootTimeout)
+
+					return err
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				By(fmt.Sprintf("Uncordon node %s", nodeName))
+				err = report.Run("uncordon", func() error {
+					restartedNode, err := nodes.Pull(APIClient, nodeName)
+					if err != nil {
+						return err
+					}
+
+					return restartedNode.Uncordon()
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Wait for the cluster to be stable")
+				err = report.Run("stability", func() error {
+					return cluster.WaitForStability(APIClient, randuparams.ShutdownStableDuration,
+						randuparams.ShutdownStabilityTimeout)
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Wait for the pending CSRs to be handled")
+				err = report.Run("csr", func() error {
+					names, err := cluster.WaitForNoPendingCSRs(APIClient, RanDuTestConfig.ShutdownApproveCSRs,
+						randuparams.ShutdownTimeout, nodeName)
+					approved = append(approved, names...)
+
+					return err
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				for _, name := range approved {
+					fmt.Fprintf(GinkgoWriter, "approved CSR %s\n", name)
+				}
+
+				By("Wait for the workload to be ready")
+				err = report.Run("workload", func() error {
+					_, err := await.WaitUntilAllDeploymentsReady(APIClient, RanDuTestConfig.TestWorkload.Namespace,
+						randuparams.DefaultTimeout)
+					if err != nil {
+						return err
+					}
+
+					_, err = await.WaitUntilAllStatefulSetsReady(APIClient, RanDuTestConfig.TestWorkload.Namespace,
+						randuparams.DefaultTimeout)
+
+					return err
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				// Persist shutdown metrics to ginkgo report for further processing in pipeline.
+				for metricName, metricValue := range report.Metrics("graceful") {
+					_, err := fmt.Fprintf(GinkgoWriter, "%s: %s\n", metricName, metricValue)
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				By("Verify no cluster certificate expired")
+				certificates, err = certs.ListManagedCertificates(APIClient)
+				Expect(err).ToNot(HaveOccurred(), "error listing cluster certificates")
+				Expect(certs.CheckExpiry(certificates, 0)).To(BeEmpty(), "cluster certificates expired")
+
+				By("Validate workload integrity")
+				workloadReport, err := workload.Validate(APIClient, RanDuTestConfig.TestWorkload.Namespace, nil,
+					workload.Options{})
+				Expect(err).ToNot(HaveOccurred(), "error when validating the workload")
+
+				fmt.Print(workloadReport.String())
+				Expect(workloadReport.Failures()).To(BeEmpty(), "workload integrity checks failed after the shutdown")
+			})
+
+		AfterAll(func() {
+			By("Cleaning up test workload resources")
+			err := randutestworkload.CleanNameSpace(randuparams.DefaultTimeout, RanDuTestConfig.TestWorkload.Namespace)
+			Expect(err).ToNot(HaveOccurred(), "Failed to clean workload test namespace objects")
+		})
+	})
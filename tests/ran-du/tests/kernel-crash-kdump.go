@@ -30,6 +30,7 @@ var _ = Describe(
 
 			for _, node := range nodeList {
 				By("Trigger kernel crash")
+				crashTime := time.Now()
 				err = reboot.KernelCrashKdump(node.Definition.Name)
 				Expect(err).ToNot(HaveOccurred(), "Error triggering a kernel crash on the node.")
 
@@ -43,6 +44,8 @@ var _ = Describe(
 				Expect(err).ToNot(HaveOccurred(), "could not execute command: %s", err)
 
 				Expect(len(strings.Fields(coreDumps))).To(BeNumerically(">=", 1), "error: vmcore dump was not generated")
+
+				verifyTimeSync(node.Definition.Name, crashTime, "kdump")
 			}
 
 		})
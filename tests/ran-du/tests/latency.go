@@ -0,0 +1,98 @@
+package ran_du_system_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-goinfra/pkg/nto" //nolint:misspell
+	"github.com/openshift-kni/eco-gosystem/tests/internal/await"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/latency"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/reboot"
+	systemtestsscc "github.com/openshift-kni/eco-gosystem/tests/internal/scc"
+	. "github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuinittools"
+	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuparams"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var _ = Describe(
+	"Latency",
+	Ordered,
+	ContinueOnFailure,
+	Label(randuparams.LabelLatencyTestCases), func() {
+		var (
+			perfProfile *nto.Builder
+			nodeList    []*nodes.Builder
+			settings    *latency.Settings
+		)
+
+		BeforeAll(func() {
+			By("Retrieve the performance profile")
+			var err error
+			perfProfile, err = latency.GetPerformanceProfile(APIClient)
+			Expect(err).ToNot(HaveOccurred(), "error retrieving the performance profile")
+
+			By("Retrieve the nodes of the performance profile")
+			nodeList, err = nodes.List(APIClient, metav1.ListOptions{
+				LabelSelector: labels.Set(perfProfile.Object.Spec.NodeSelector).String(),
+			})
+			Expect(err).ToNot(HaveOccurred(), "Error listing nodes.")
+			Expect(nodeList).ToNot(BeEmpty(), "no node matches the performance profile node selector")
+
+			settings, err = latency.NewSettings(RanDuTestConfig.GeneralConfig)
+			Expect(err).ToNot(HaveOccurred(), "invalid latency test configuration")
+
+			By("Allow privileged pods in the test namespace")
+			err = systemtestsscc.AddPrivilegedSCCtoDefaultSA(randuparams.TestNamespaceName)
+			Expect(err).ToNot(HaveOccurred(), "error adding privileged SCC to the test namespace")
+		})
+
+		It("Measure latency before reboot", Label(randuparams.LabelLatencyTestCases), func() {
+			measureLatency(perfProfile, nodeList, settings, "beforereboot")
+		})
+
+		It("Measure latency after soft reboot", Label(randuparams.LabelLatencyTestCases), func() {
+			for _, node := range nodeList {
+				bootID := node.Object.Status.NodeInfo.BootID
+
+				By("Reboot node")
+				err := reboot.SoftRebootNode(node.Definition.Name)
+				Expect(err).ToNot(HaveOccurred(), "Error rebooting the nodes.")
+
+				By("Wait for node to become unreachable")
+				err = await.WaitUntilNodeIsUnreachable(node.Definition.Name, 3*time.Minute)
+				Expect(err).ToNot(HaveOccurred(), "Node is still reachable: %s", err)
+
+				By("Wait for node to reboot and become ready")
+				err = reboot.WaitUntilNodeRebooted(node.Definition.Name, bootID, randuparams.RebootTimeout)
+				Expect(err).ToNot(HaveOccurred(), "node %s did not become ready after reboot", node.Definition.Name)
+			}
+
+			measureLatency(perfProfile, nodeList, settings, "afterreboot")
+		})
+	})
+
+// measureLatency runs every latency tool on every node, persists the metrics to the ginkgo report and asserts
+// the max latency thresholds.
+func measureLatency(perfProfile *nto.Builder, nodeList []*nodes.Builder, settings *latency.Settings, tag string) {
+	for _, node := range nodeList {
+		for _, tool := range latency.Tools {
+			By(fmt.Sprintf("Run %s on node %s", tool, node.Definition.Name))
+			result, err := latency.Run(APIClient, perfProfile,
+				settings.Options(tool, randuparams.TestNamespaceName, node.Definition.Name))
+			Expect(err).ToNot(HaveOccurred(), "error measuring latency with %s", tool)
+
+			// Persist latency metrics to ginkgo report for further processing in pipeline.
+			for metricName, metricValue := range result.Metrics(tag) {
+				_, err := fmt.Fprintf(GinkgoWriter, "%s: %s\n", metricName, metricValue)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			Expect(result.CheckThreshold(settings.MaxLatency[tool])).To(Succeed(),
+				"%s latency above threshold on node %s", tool, node.Definition.Name)
+		}
+	}
+}
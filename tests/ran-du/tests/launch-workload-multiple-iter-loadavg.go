@@ -3,7 +3,6 @@ package ran_du_system_test
 import (
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -12,12 +11,11 @@ import (
 	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
 	"github.com/openshift-kni/eco-goinfra/pkg/polarion"
 	"github.com/openshift-kni/eco-gosystem/tests/internal/await"
-	"github.com/openshift-kni/eco-gosystem/tests/internal/cmd"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/nodesampler"
 	"github.com/openshift-kni/eco-gosystem/tests/internal/shell"
 	. "github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuinittools"
 	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuparams"
 	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randutestworkload"
-	"gonum.org/v1/gonum/floats"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -70,25 +68,40 @@ var _ = Describe(
 			)
 			Expect(err).ToNot(HaveOccurred(), "Error listing nodes.")
 
-			By("Observe node load average while workload is running")
-			cmdToExec := []string{"awk", "{print $1}", "/proc/loadavg"}
-			var observedLoadAverage []float64
+			var nodeNames []string
+			for _, node := range nodeList {
+				nodeNames = append(nodeNames, node.Definition.Name)
+			}
 
-			for n := 0; n < 30; n++ {
-				buf, err := cmd.ExecCmd(cmdToExec, nodeList[0].Definition.Name)
-				Expect(err).ToNot(HaveOccurred(), "could not execute command: %s", err)
+			samplerInterval, err := time.ParseDuration(RanDuTestConfig.NodeSamplerInterval)
+			Expect(err).ToNot(HaveOccurred(), "invalid node sampler interval")
 
-				floatBuf, err := strconv.ParseFloat(strings.ReplaceAll(strings.ReplaceAll(buf, "\r", ""), "\n", ""), 32)
-				if err != nil {
-					fmt.Println(err)
-				}
+			samplerDuration, err := time.ParseDuration(RanDuTestConfig.NodeSamplerDuration)
+			Expect(err).ToNot(HaveOccurred(), "invalid node sampler duration")
+
+			By("Observe nodes resource usage while workload is running")
+			sampler := nodesampler.New(nodeNames, samplerInterval)
+			err = sampler.Start()
+			Expect(err).ToNot(HaveOccurred(), "failed to start node sampler")
+
+			time.Sleep(samplerDuration)
+
+			results := sampler.Stop()
+			thresholds := nodesampler.Thresholds{
+				MaxLoadAvg1: float64(randuparams.TestMultipleLaunchWorkloadLoadAvg),
+			}
+
+			err = results.WriteArtifacts(RanDuTestConfig.ReportsDirAbsPath, "launch_workload_node_samples", thresholds)
+			Expect(err).ToNot(HaveOccurred(), "failed to write node sampler artifacts")
 
-				observedLoadAverage = append(observedLoadAverage, floatBuf)
-				time.Sleep(10 * time.Second)
+			for _, samplingError := range results.Errors {
+				fmt.Fprintf(GinkgoWriter, "node sampling error: %s\n", samplingError)
 			}
 
-			Expect(floats.Max(observedLoadAverage)).To(BeNumerically("<", randuparams.TestMultipleLaunchWorkloadLoadAvg),
-				"error: node load average detected above 100")
+			Expect(results.CheckErrors(randuparams.NodeSamplerMaxErrorRatio)).To(Succeed(),
+				"too many errors while sampling nodes")
+			Expect(results.CheckThresholds(thresholds)).To(BeEmpty(),
+				"node resource usage above thresholds")
 
 		})
 		AfterAll(func() {
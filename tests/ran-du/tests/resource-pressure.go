@@ -0,0 +1,194 @@
+package ran_du_system_test
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-goinfra/pkg/namespace"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/pressure"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/shell"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/workload"
+	. "github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuinittools"
+	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuparams"
+	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randutestworkload"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe(
+	"ResourcePressure",
+	Ordered,
+	ContinueOnFailure,
+	Label(randuparams.LabelPressureTestCases), func() {
+		var (
+			nodeNames []string
+			timeout   time.Duration
+		)
+
+		BeforeAll(func() {
+			var err error
+
+			timeout, err = time.ParseDuration(RanDuTestConfig.PressureTimeout)
+			Expect(err).ToNot(HaveOccurred(), "invalid pressure timeout")
+
+			By("Preparing workload")
+
+			if namespace.NewBuilder(APIClient, RanDuTestConfig.TestWorkload.Namespace).Exists() {
+				err := randutestworkload.CleanNameSpace(randuparams.DefaultTimeout, RanDuTestConfig.TestWorkload.Namespace)
+				Expect(err).ToNot(HaveOccurred(), "Failed to clean workload test namespace objects")
+			}
+
+			if RanDuTestConfig.TestWorkload.CreateMethod == randuparams.TestWorkloadShellLaunchMethod {
+				By("Launching workload using shell method")
+				_, err := shell.ExecuteCmd(RanDuTestConfig.TestWorkload.CreateShellCmd)
+				Expect(err).ToNot(HaveOccurred(), "Failed to launch workload")
+			}
+
+			waitForWorkloadReady()
+
+			By("Retrieve nodes list")
+			nodeList, err := nodes.List(APIClient, metav1.ListOptions{})
+			Expect(err).ToNot(HaveOccurred(), "Error listing nodes.")
+
+			for _, node := range nodeList {
+				nodeNames = append(nodeNames, node.Definition.Name)
+			}
+		})
+
+		It("Verify memory pressure evicts BestEffort pods first and spares the workload",
+			Label(randuparams.LabelPressureTestCases), func() {
+				for _, nodeName := range nodeNames {
+					By(fmt.Sprintf("Deploy Burstable and Guaranteed bystander pods on node %s", nodeName))
+					for _, qos := range []v1.PodQOSClass{v1.PodQOSBurstable, v1.PodQOSGuaranteed} {
+						bystanderName := fmt.Sprintf("pressure-%s-%s", strings.ToLower(string(qos)), nodeName)
+						bystander, err := pressure.StressPod(APIClient, bystanderName, randuparams.TestNamespaceName,
+							nodeName, RanDuTestConfig.PressureImage, qos, randuparams.PressureBystanderFill,
+							timeout).CreateAndWaitUntilRunning(randuparams.DefaultTimeout)
+						Expect(err).ToNot(HaveOccurred(), "error deploying %s bystander pod", qos)
+
+						DeferCleanup(func() {
+							_, err := bystander.DeleteAndWait(randuparams.DefaultTimeout)
+							Expect(err).ToNot(HaveOccurred(), "error deleting bystander pod")
+						})
+					}
+
+					evictions := applyPressure(pressure.Memory(APIClient, randuparams.TestNamespaceName, nodeName,
+						RanDuTestConfig.PressureImage, timeout), timeout, "memory")
+
+					Expect(pressure.CheckEvictionOrder(evictions)).To(Succeed(), "unexpected eviction order")
+					Expect(pressure.CheckGuaranteedSpared(evictions)).To(Succeed(), "Guaranteed pod evicted")
+				}
+			})
+
+		It("Verify ephemeral storage limit evicts the exceeding pod", Label(randuparams.LabelPressureTestCases), func() {
+			for _, nodeName := range nodeNames {
+				evictions := applyPressure(pressure.EphemeralStorage(APIClient, randuparams.TestNamespaceName, nodeName,
+					RanDuTestConfig.PressureImage, 64*1024*1024, 128*1024*1024, timeout), timeout, "ephemeralstorage")
+
+				for _, eviction := range evictions {
+					Expect(eviction.Message).To(ContainSubstring("ephemeral"),
+						"pod %s was not evicted for its ephemeral storage usage", eviction.Pod)
+				}
+			}
+		})
+
+		It("Verify node disk pressure is reported and spares the workload", Label(randuparams.LabelPressureTestCases),
+			func() {
+				for _, nodeName := range nodeNames {
+					applyPressure(pressure.NodeDisk(APIClient, nodeName, RanDuTestConfig.PressureDiskPath, timeout),
+						timeout, "disk")
+				}
+			})
+
+		AfterAll(func() {
+			By("Cleaning up test workload resources")
+			err := randutestworkload.CleanNameSpace(randuparams.DefaultTimeout, RanDuTestConfig.TestWorkload.Namespace)
+			Expect(err).ToNot(HaveOccurred(), "Failed to clean workload test namespace objects")
+		})
+	})
+
+// applyPressure applies the pressure on its node, waits for the node condition to be set, for the pressure pods to
+// be evicted and for the node condition to be cleared once the pressure is released. The timing and eviction
+// metrics are persisted to the ginkgo report, the workload is checked for evictions and validated, and the
+// evictions of the test namespace are returned.
+func applyPressure(nodePressure *pressure.Pressure, timeout time.Duration, tag string) []pressure.Eviction {
+	By("Capture workload restart counts")
+	baseline, err := workload.CaptureBaseline(APIClient, RanDuTestConfig.TestWorkload.Namespace)
+	Expect(err).ToNot(HaveOccurred(), "error when capturing workload restart counts")
+
+	start := time.Now()
+
+	By(fmt.Sprintf("Apply %s on node %s", nodePressure.Description, nodePressure.Node))
+	DeferCleanup(nodePressure.Release)
+
+	err = nodePressure.Apply()
+	Expect(err).ToNot(HaveOccurred(), "error applying %s on node %s", nodePressure.Description, nodePressure.Node)
+
+	metrics := map[string]time.Duration{}
+
+	if nodePressure.Condition != "" {
+		By(fmt.Sprintf("Wait for node condition %s to be set", nodePressure.Condition))
+		metrics["condition"], err = pressure.WaitForNodeCondition(APIClient, nodePressure.Node,
+			nodePressure.Condition, v1.ConditionTrue, start, timeout)
+		Expect(err).ToNot(HaveOccurred(), "node condition %s was not set", nodePressure.Condition)
+	}
+
+	if len(nodePressure.Pods) > 0 {
+		By("Wait for the pressure pods to be evicted")
+		_, err = pressure.WaitForEvictions(APIClient, randuparams.TestNamespaceName, nodePressure.Pods, start, timeout)
+		Expect(err).ToNot(HaveOccurred(), "pressure pods were not evicted")
+	}
+
+	By("Release the pressure")
+	err = nodePressure.Release()
+	Expect(err).ToNot(HaveOccurred(), "error releasing %s on node %s", nodePressure.Description, nodePressure.Node)
+
+	if nodePressure.Condition != "" {
+		By(fmt.Sprintf("Wait for node condition %s to be cleared", nodePressure.Condition))
+		metrics["clear"], err = pressure.WaitForNodeCondition(APIClient, nodePressure.Node, nodePressure.Condition,
+			v1.ConditionFalse, start, timeout)
+		Expect(err).ToNot(HaveOccurred(), "node condition %s was not cleared", nodePressure.Condition)
+	}
+
+	evictions, err := pressure.ListEvictions(APIClient, randuparams.TestNamespaceName, start)
+	Expect(err).ToNot(HaveOccurred(), "error listing evicted pods")
+
+	for _, eviction := range evictions {
+		fmt.Fprintf(GinkgoWriter, "%s pod %s evicted at %s: %s\n", eviction.QoS, eviction.Pod,
+			eviction.Time.Format(time.RFC3339), eviction.Message)
+	}
+
+	// Persist pressure metrics to ginkgo report for further processing in pipeline.
+	for metricName, metricValue := range metrics {
+		_, err := fmt.Fprintf(GinkgoWriter, "ranmetrics_pressure_%s_seconds_%s_%s: %s\n", metricName,
+			nodePressure.Node, tag, strconv.FormatFloat(metricValue.Seconds(), 'f', 0, 64))
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	_, err = fmt.Fprintf(GinkgoWriter, "ranmetrics_pressure_evictions_%s_%s: %d\n", nodePressure.Node, tag,
+		len(evictions))
+	Expect(err).ToNot(HaveOccurred())
+
+	By("Verify no workload pod was evicted")
+	workloadEvictions, err := pressure.ListEvictions(APIClient, RanDuTestConfig.TestWorkload.Namespace, start)
+	Expect(err).ToNot(HaveOccurred(), "error listing evicted workload pods")
+	Expect(workloadEvictions).To(BeEmpty(), "workload pods were evicted by %s", nodePressure.Description)
+
+	waitForWorkloadReady()
+
+	By("Validate workload integrity")
+	report, err := workload.Validate(APIClient, RanDuTestConfig.TestWorkload.Namespace, baseline,
+		workload.Options{MaxRestartDelta: randuparams.DisruptionMaxRestartDelta})
+	Expect(err).ToNot(HaveOccurred(), "error when validating the workload")
+
+	fmt.Print(report.String())
+	Expect(report.Failures()).To(BeEmpty(), "workload integrity checks failed after %s on node %s",
+		nodePressure.Description, nodePressure.Node)
+
+	return evictions
+}
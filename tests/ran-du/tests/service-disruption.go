@@ -0,0 +1,165 @@
+package ran_du_system_test
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-goinfra/pkg/namespace"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/await"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/disruption"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/shell"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/workload"
+	. "github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuinittools"
+	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuparams"
+	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randutestworkload"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe(
+	"ServiceDisruption",
+	Ordered,
+	ContinueOnFailure,
+	Label(randuparams.LabelDisruptionTestCases), func() {
+		var (
+			nodeNames       []string
+			downtime        time.Duration
+			recoveryTimeout time.Duration
+		)
+
+		BeforeAll(func() {
+			var err error
+
+			downtime, err = time.ParseDuration(RanDuTestConfig.DisruptionDowntime)
+			Expect(err).ToNot(HaveOccurred(), "invalid disruption downtime")
+
+			recoveryTimeout, err = time.ParseDuration(RanDuTestConfig.DisruptionTimeout)
+			Expect(err).ToNot(HaveOccurred(), "invalid disruption recovery timeout")
+
+			By("Preparing workload")
+
+			if namespace.NewBuilder(APIClient, RanDuTestConfig.TestWorkload.Namespace).Exists() {
+				err := randutestworkload.CleanNameSpace(randuparams.DefaultTimeout, RanDuTestConfig.TestWorkload.Namespace)
+				Expect(err).ToNot(HaveOccurred(), "Failed to clean workload test namespace objects")
+			}
+
+			if RanDuTestConfig.TestWorkload.CreateMethod == randuparams.TestWorkloadShellLaunchMethod {
+				By("Launching workload using shell method")
+				_, err := shell.ExecuteCmd(RanDuTestConfig.TestWorkload.CreateShellCmd)
+				Expect(err).ToNot(HaveOccurred(), "Failed to launch workload")
+			}
+
+			waitForWorkloadReady()
+
+			By("Retrieve nodes list")
+			nodeList, err := nodes.List(APIClient, metav1.ListOptions{})
+			Expect(err).ToNot(HaveOccurred(), "Error listing nodes.")
+
+			for _, node := range nodeList {
+				nodeNames = append(nodeNames, node.Definition.Name)
+			}
+		})
+
+		It("Verify workload recovers from a kubelet stop", Label(randuparams.LabelDisruptionTestCases), func() {
+			for _, nodeName := range nodeNames {
+				disruptAndValidate(disruption.StopService(APIClient, nodeName, disruption.Kubelet, downtime),
+					recoveryTimeout, "kubeletstop")
+			}
+		})
+
+		It("Verify workload recovers from a kubelet restart", Label(randuparams.LabelDisruptionTestCases), func() {
+			for _, nodeName := range nodeNames {
+				disruptAndValidate(disruption.RestartService(APIClient, nodeName, disruption.Kubelet),
+					recoveryTimeout, "kubeletrestart")
+			}
+		})
+
+		It("Verify workload recovers from a CRI-O stop", Label(randuparams.LabelDisruptionTestCases), func() {
+			for _, nodeName := range nodeNames {
+				disruptAndValidate(disruption.StopService(APIClient, nodeName, disruption.Crio, downtime),
+					recoveryTimeout, "criostop")
+			}
+		})
+
+		It("Verify workload recovers from a CRI-O restart", Label(randuparams.LabelDisruptionTestCases), func() {
+			for _, nodeName := range nodeNames {
+				disruptAndValidate(disruption.RestartService(APIClient, nodeName, disruption.Crio),
+					recoveryTimeout, "criorestart")
+			}
+		})
+
+		It("Verify workload recovers from a host process kill", Label(randuparams.LabelDisruptionTestCases), func() {
+			if RanDuTestConfig.DisruptionKillProcess == "" {
+				Skip("No process to kill configured")
+			}
+
+			for _, nodeName := range nodeNames {
+				disruptAndValidate(disruption.KillProcess(APIClient, nodeName, RanDuTestConfig.DisruptionKillProcess,
+					"KILL"), recoveryTimeout, "processkill")
+			}
+		})
+
+		It("Verify workload recovers from an interface down", Label(randuparams.LabelDisruptionTestCases), func() {
+			if RanDuTestConfig.DisruptionInterface == "" {
+				Skip("No interface to take down configured")
+			}
+
+			for _, nodeName := range nodeNames {
+				disruptAndValidate(disruption.InterfaceDown(APIClient, nodeName, RanDuTestConfig.DisruptionInterface,
+					downtime), recoveryTimeout, "interfacedown")
+			}
+		})
+
+		AfterAll(func() {
+			By("Cleaning up test workload resources")
+			err := randutestworkload.CleanNameSpace(randuparams.DefaultTimeout, RanDuTestConfig.TestWorkload.Namespace)
+			Expect(err).ToNot(HaveOccurred(), "Failed to clean workload test namespace objects")
+		})
+	})
+
+// disruptAndValidate injects the disruption, waits for the node to recover without a reboot, persists the
+// recovery time metric to the ginkgo report and validates the workload integrity.
+func disruptAndValidate(nodeDisruption *disruption.Disruption, recoveryTimeout time.Duration, tag string) {
+	By("Capture workload restart counts")
+	baseline, err := workload.CaptureBaseline(APIClient, RanDuTestConfig.TestWorkload.Namespace)
+	Expect(err).ToNot(HaveOccurred(), "error when capturing workload restart counts")
+
+	By(fmt.Sprintf("Disrupt node %s: %s", nodeDisruption.Node, nodeDisruption.Description))
+	err = nodeDisruption.Inject()
+	Expect(err).ToNot(HaveOccurred(), "error disrupting node %s", nodeDisruption.Node)
+
+	By("Wait for the node to recover")
+	recoveryTime, err := nodeDisruption.WaitForRecovery(recoveryTimeout)
+	Expect(err).ToNot(HaveOccurred(), "node %s did not recover", nodeDisruption.Node)
+
+	// Persist recovery time metric to ginkgo report for further processing in pipeline.
+	_, err = fmt.Fprintf(GinkgoWriter, "ranmetrics_disruption_recovery_seconds_%s_%s: %s\n", nodeDisruption.Node, tag,
+		strconv.FormatFloat(recoveryTime.Seconds(), 'f', 0, 64))
+	Expect(err).ToNot(HaveOccurred())
+
+	waitForWorkloadReady()
+
+	By("Validate workload integrity")
+	report, err := workload.Validate(APIClient, RanDuTestConfig.TestWorkload.Namespace, baseline,
+		workload.Options{MaxRestartDelta: randuparams.DisruptionMaxRestartDelta})
+	Expect(err).ToNot(HaveOccurred(), "error when validating the workload")
+
+	fmt.Print(report.String())
+	Expect(report.Failures()).To(BeEmpty(), "workload integrity checks failed after %s on node %s",
+		nodeDisruption.Description, nodeDisruption.Node)
+}
+
+func waitForWorkloadReady() {
+	By("Waiting for deployment replicas to become ready")
+	_, err := await.WaitUntilAllDeploymentsReady(APIClient, RanDuTestConfig.TestWorkload.Namespace,
+		randuparams.DefaultTimeout)
+	Expect(err).ToNot(HaveOccurred(), "error while waiting for deployment to become ready")
+
+	By("Waiting for statefulset replicas to become ready")
+	_, err = await.WaitUntilAllStatefulSetsReady(APIClient, RanDuTestConfig.TestWorkload.Namespace,
+		randuparams.DefaultTimeout)
+	Expect(err).ToNot(HaveOccurred(), "error while waiting for statefulsets to become ready")
+}
@@ -0,0 +1,91 @@
+package ran_du_system_test
+
+import (
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-goinfra/pkg/namespace"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/await"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/shell"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/soak"
+	. "github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuinittools"
+	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuparams"
+	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randutestworkload"
+)
+
+var _ = Describe(
+	"Soak",
+	Ordered,
+	ContinueOnFailure,
+	Label(randuparams.LabelSoakTestCases), func() {
+		BeforeAll(func() {
+			if RanDuTestConfig.SoakResume &&
+				namespace.NewBuilder(APIClient, RanDuTestConfig.TestWorkload.Namespace).Exists() {
+				By("Resuming soak on the running workload")
+
+				return
+			}
+
+			By("Preparing workload")
+			if namespace.NewBuilder(APIClient, RanDuTestConfig.TestWorkload.Namespace).Exists() {
+				err := randutestworkload.CleanNameSpace(randuparams.DefaultTimeout, RanDuTestConfig.TestWorkload.Namespace)
+				Expect(err).ToNot(HaveOccurred(), "Failed to clean workload test namespace objects")
+			}
+
+			if RanDuTestConfig.TestWorkload.CreateMethod == randuparams.TestWorkloadShellLaunchMethod {
+				By("Launching workload using shell method")
+				_, err := shell.ExecuteCmd(RanDuTestConfig.TestWorkload.CreateShellCmd)
+				Expect(err).ToNot(HaveOccurred(), "Failed to launch workload")
+			}
+
+			By("Waiting for deployment replicas to become ready")
+			_, err := await.WaitUntilAllDeploymentsReady(APIClient, RanDuTestConfig.TestWorkload.Namespace,
+				randuparams.DefaultTimeout)
+			Expect(err).ToNot(HaveOccurred(), "error while waiting for deployment to become ready")
+
+			By("Waiting for statefulset replicas to become ready")
+			_, err = await.WaitUntilAllStatefulSetsReady(APIClient, RanDuTestConfig.TestWorkload.Namespace,
+				randuparams.DefaultTimeout)
+			Expect(err).ToNot(HaveOccurred(), "error while waiting for statefulsets to become ready")
+
+			By("Waiting for all pods to become ready")
+			_, err = await.WaitUntilAllPodsReady(APIClient, RanDuTestConfig.TestWorkload.Namespace, 10*time.Second)
+			Expect(err).ToNot(HaveOccurred(), "pod not ready: %s", err)
+		})
+
+		It("Keeps the workload stable for the soak duration", Label(randuparams.LabelSoakTestCases), func() {
+			soakDuration, err := time.ParseDuration(RanDuTestConfig.SoakDuration)
+			Expect(err).ToNot(HaveOccurred(), "invalid soak duration")
+
+			soakInterval, err := time.ParseDuration(RanDuTestConfig.SoakInterval)
+			Expect(err).ToNot(HaveOccurred(), "invalid soak interval")
+
+			By("Probing workload and nodes until the end of the soak")
+			state, err := soak.Run(APIClient, soak.Config{
+				Namespace:      RanDuTestConfig.TestWorkload.Namespace,
+				Duration:       soakDuration,
+				Interval:       soakInterval,
+				CheckpointPath: filepath.Join(RanDuTestConfig.ReportsDirAbsPath, randuparams.SoakCheckpointFile),
+				Resume:         RanDuTestConfig.SoakResume,
+				Criteria: soak.Criteria{
+					MaxRestarts:               RanDuTestConfig.SoakMaxRestarts,
+					MaxOOMKills:               RanDuTestConfig.SoakMaxOOMKills,
+					MaxReadinessFlaps:         RanDuTestConfig.SoakMaxReadinessFlaps,
+					MaxPressureEvents:         RanDuTestConfig.SoakMaxPressureEvents,
+					MaxLoadAvg:                float64(randuparams.TestMultipleLaunchWorkloadLoadAvg),
+					MaxConsecutiveProbeErrors: randuparams.SoakMaxConsecutiveProbeErrors,
+				},
+				Output: GinkgoWriter,
+			})
+			Expect(err).ToNot(HaveOccurred(), "soak failed")
+			Expect(state.Finished).To(BeTrue(), "soak did not complete")
+		})
+
+		AfterAll(func() {
+			By("Cleaning up test workload resources")
+			err := randutestworkload.CleanNameSpace(randuparams.DefaultTimeout, RanDuTestConfig.TestWorkload.Namespace)
+			Expect(err).ToNot(HaveOccurred(), "Failed to clean workload test namespace objects")
+		})
+	})
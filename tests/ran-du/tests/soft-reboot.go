@@ -3,7 +3,6 @@ package ran_du_system_test
 import (
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -16,7 +15,8 @@ import (
 	"github.com/openshift-kni/eco-gosystem/tests/internal/await"
 	"github.com/openshift-kni/eco-gosystem/tests/internal/reboot"
 	"github.com/openshift-kni/eco-gosystem/tests/internal/shell"
-	"github.com/openshift-kni/eco-gosystem/tests/internal/sriov"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/snapshot"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/workload"
 	. "github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuinittools"
 	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuparams"
 	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randutestworkload"
@@ -74,8 +74,20 @@ var _ = Describe(
 				By("Soft rebooting cluster")
 				fmt.Printf("Soft reboot iteration no. %d\n", r)
 				for _, node := range nodeList {
+					By("Capture workload restart counts")
+					baseline, err := workload.CaptureBaseline(APIClient, RanDuTestConfig.TestWorkload.Namespace)
+					Expect(err).ToNot(HaveOccurred(), "error when capturing workload restart counts")
+
+					By("Take cluster snapshot")
+					preRebootSnapshot := takeSnapshot()
+
+					By("Start availability probers")
+					availabilityTag := fmt.Sprintf("softreboot_%s_%d", node.Definition.Name, r)
+					availabilityProbers := startAvailabilityProbers(availabilityTag)
+
 					By("Reboot node")
 					fmt.Printf("Reboot node %s", node.Definition.Name)
+					rebootTime := time.Now()
 					err = reboot.SoftRebootNode(node.Definition.Name)
 					Expect(err).ToNot(HaveOccurred(), "Error rebooting the nodes.")
 
@@ -115,46 +127,21 @@ var _ = Describe(
 						randuparams.DefaultTimeout)
 					Expect(err).ToNot(HaveOccurred(), "error while waiting for statefulsets to become ready")
 
-					By("Retrieve pod list")
-					podsList, err = pod.List(APIClient, RanDuTestConfig.TestWorkload.Namespace, metav1.ListOptions{})
-					Expect(err).ToNot(HaveOccurred(), "could not retrieve pod list")
-
-					By("Retrieve sriov networks with vfio-pci driver")
-					vfioNetworks, err := sriov.ListNetworksByDeviceType(APIClient, "vfio-pci")
-					Expect(err).ToNot(HaveOccurred(), "error when retrieving sriov network using vfio-pci driver")
+					By("Validate workload integrity")
+					report, err := workload.Validate(APIClient, RanDuTestConfig.TestWorkload.Namespace, baseline,
+						workload.Options{MaxRestartDelta: randuparams.RebootMaxRestartDelta})
+					Expect(err).ToNot(HaveOccurred(), "error when validating the workload")
 
-					By("Assert devices under /dev/vfio on pod are equal or more to the pods vfio-pci network attachments\n")
-					for _, pod := range podsList {
-						networkNames, err := sriov.ExtractNetworkNames(pod.Object.Annotations["k8s.v1.cni.cncf.io/network-status"])
-						Expect(err).ToNot(HaveOccurred(), "error when retrieving pod network attachments")
+					fmt.Print(report.String())
+					Expect(report.Failures()).To(BeEmpty(), "workload integrity checks failed after node %s reboot",
+						node.Definition.Name)
 
-						podvfioDevices := 0
+					verifyAvailability(availabilityProbers, availabilityTag)
 
-						for _, vfioNet := range vfioNetworks {
-							for _, podNet := range networkNames {
-								if strings.Contains(podNet, pod.Definition.Namespace+"/"+vfioNet) {
-									podvfioDevices++
-								}
-							}
-						}
+					By("Verify the cluster state is restored")
+					verifySnapshot(preRebootSnapshot, snapshot.RebootRules(node.Definition.Name), "softreboot")
 
-						if podvfioDevices > 0 {
-							fmt.Printf("Check /dev/vfio on pod %s\n", pod.Definition.Name)
-							lscmd := []string{"ls", "--color=never", "/dev/vfio"}
-							cmd, err := pod.ExecCommand(lscmd)
-							Expect(err).ToNot(HaveOccurred(), "error when executing command on pod")
-
-							// retry in case the command exec returns an empty string
-							if len(cmd.String()) == 0 {
-								cmd, err = pod.ExecCommand(lscmd)
-								Expect(err).ToNot(HaveOccurred(), "error when executing command on pod")
-							}
-
-							vfioDevls := strings.Fields(strings.ReplaceAll(cmd.String(), "vfio", ""))
-							Expect(len(vfioDevls)).To(BeNumerically(">=", podvfioDevices),
-								"error: vfio devices inside pod( %s ) do not match pod %s attachments:", cmd.String(), pod.Definition.Name)
-						}
-					}
+					verifyTimeSync(node.Definition.Name, rebootTime, "softreboot")
 				}
 			}
 		})
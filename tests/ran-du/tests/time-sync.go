@@ -0,0 +1,79 @@
+package ran_du_system_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/timesync"
+	. "github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuinittools"
+	"github.com/openshift-kni/eco-gosystem/tests/ran-du/internal/randuparams"
+)
+
+var _ = Describe(
+	"TimeSync",
+	Ordered,
+	ContinueOnFailure,
+	Label(randuparams.LabelTimeSyncTestCases), func() {
+		It("Verify time is synchronized", Label(randuparams.LabelTimeSyncTestCases), func() {
+			By("Retrieve nodes list")
+			nodeList, err := nodes.List(APIClient)
+			Expect(err).ToNot(HaveOccurred(), "Error listing nodes.")
+
+			var checked int
+
+			for _, node := range nodeList {
+				if verifyTimeSync(node.Definition.Name, time.Now(), "steadystate") {
+					checked++
+				}
+			}
+
+			if checked == 0 {
+				Skip("chronyd is not active on any node")
+			}
+		})
+	})
+
+// verifyTimeSync waits for the node clock to be synchronized by chrony within the configured offset, persists the
+// offset and time to synchronization metrics to the ginkgo report and returns true. The check is skipped and false
+// is returned when chronyd is not active on the node, e.g. when the clock is synchronized by PTP.
+func verifyTimeSync(nodeName string, start time.Time, tag string) bool {
+	maxOffset, err := time.ParseDuration(RanDuTestConfig.TimeSyncMaxOffset)
+	Expect(err).ToNot(HaveOccurred(), "invalid time synchronization max offset")
+
+	timeout, err := time.ParseDuration(RanDuTestConfig.TimeSyncTimeout)
+	Expect(err).ToNot(HaveOccurred(), "invalid time synchronization timeout")
+
+	active, err := timesync.IsChronyActive(nodeName)
+	Expect(err).ToNot(HaveOccurred(), "error checking chronyd on node %s", nodeName)
+
+	if !active {
+		_, err := fmt.Fprintf(GinkgoWriter, "chronyd not active on node %s, time synchronization not checked\n",
+			nodeName)
+		Expect(err).ToNot(HaveOccurred())
+
+		return false
+	}
+
+	By(fmt.Sprintf("Wait for time to be synchronized on node %s", nodeName))
+	status, err := timesync.WaitForSync(nodeName, maxOffset, start, timeout)
+
+	if status != nil {
+		for _, source := range status.Sources {
+			fmt.Fprintf(GinkgoWriter, "node %s time source %s%s %s stratum %d\n", nodeName, source.Mode,
+				source.State, source.Address, source.Stratum)
+		}
+	}
+
+	Expect(err).ToNot(HaveOccurred(), "time is not synchronized on node %s", nodeName)
+
+	// Persist time synchronization metrics to ginkgo report for further processing in pipeline.
+	for metricName, metricValue := range status.Metrics(tag) {
+		_, err := fmt.Fprintf(GinkgoWriter, "%s: %s\n", metricName, metricValue)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	return true
+}
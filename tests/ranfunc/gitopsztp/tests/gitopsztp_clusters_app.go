@@ -8,9 +8,9 @@ import (
 	"github.com/openshift-kni/eco-goinfra/pkg/assisted"
 	"github.com/openshift-kni/eco-goinfra/pkg/clients"
 	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/version"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/gitopsztp/internal/gitopsztphelper"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/gitopsztp/internal/gitopsztpparams"
-	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfunchelper"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfuncinittools"
 )
 
@@ -32,17 +32,7 @@ var _ = Describe("ZTP Argocd clusters Tests", Ordered, Label("ztp-argocd-cluster
 
 		// Check for minimum ztp version
 		By("Checking the ZTP version", func() {
-			if !ranfunchelper.IsVersionStringInRange(
-				ranfunchelper.ZtpVersion,
-				"4.11",
-				"",
-			) {
-				Skip(fmt.Sprintf(
-					"unable to run test on ztp version '%s' as it is less than minimum '%s",
-					ranfunchelper.ZtpVersion,
-					"4.11",
-				))
-			}
+			version.RequireVersion(version.ZTP, ">=4.11")
 		})
 	})
 
@@ -9,9 +9,9 @@ import (
 	"github.com/openshift-kni/eco-goinfra/pkg/polarion"
 	"github.com/openshift-kni/eco-goinfra/pkg/serviceaccount"
 	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/version"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/gitopsztp/internal/gitopsztphelper"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/gitopsztp/internal/gitopsztpparams"
-	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfunchelper"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfuncinittools"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfuncparams"
 )
@@ -40,17 +40,7 @@ var _ = Describe("ZTP Argocd policies Tests", Label("ztp-argocd-policies"), func
 
 		// Check for minimum ztp version
 		By("Checking the ZTP version", func() {
-			if !ranfunchelper.IsVersionStringInRange(
-				ranfunchelper.ZtpVersion,
-				"4.10",
-				"",
-			) {
-				Skip(fmt.Sprintf(
-					"unable to run test on ztp version '%s' as it is less than minimum '%s",
-					ranfunchelper.ZtpVersion,
-					"4.10",
-				))
-			}
+			version.RequireVersion(version.ZTP, ">=4.10")
 		})
 
 		// Collecting and storing default Git values of policies app before the test
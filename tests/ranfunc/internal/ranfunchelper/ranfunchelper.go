@@ -3,9 +3,7 @@ package ranfunchelper
 import (
 	"fmt"
 	"log"
-	"math"
 	"os"
-	"strconv"
 	"strings"
 
 	"github.com/golang/glog"
@@ -14,6 +12,7 @@ import (
 	"github.com/openshift-kni/eco-goinfra/pkg/olm"
 	"github.com/openshift-kni/eco-goinfra/pkg/pod"
 	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/version"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfuncinittools"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfuncparams"
 	v1 "k8s.io/api/core/v1"
@@ -33,137 +32,64 @@ var (
 	TalmVersion string
 )
 
-// IsVersionStringInRange can be used to check if a version string is between a specified min and max value.
-// All the string inputs to this function should be dot separated positive intergers, e.g. "1.0.0" or "4.10".
-// Each string inputs must be at least two dot separarted integers but may also be 3 or more.
-func IsVersionStringInRange(version, minimum, maximum string) bool {
-	// First we will define a helper to validate that a string is at least two dot separated positive integers
-	ValidateInputString := func(input string) (bool, []int) {
-		// Split the input string on the dot character
-		versionSplits := strings.Split(input, ".")
-
-		// We need at least two digits to verify
-		if len(versionSplits) < 2 {
-			return false, []int{}
-		}
-
-		// Prepare a list of digits to return later
-		digits := []int{}
-
-		// Check if the first two splits are valid integers
-		for i := 0; i < 2; i++ {
-			// Attempt to convert the string to an integer
-			digit, err := strconv.Atoi(versionSplits[i])
-			if err != nil {
-				return false, []int{}
-			}
-
-			// Save the digit
-			digits = append(digits, digit)
-		}
+// InitializeClients initializes hub & spoke clients and registers the versions of the hub components. The hub
+// kubeconfig is required since the versions of its components gate the specs.
+func InitializeClients() error {
+	var err error
 
-		// Otherwise if we've passed all the checks it is a valid version string
-		return true, digits
+	if os.Getenv(ranfuncparams.HubKubeEnvKey) == "" {
+		return fmt.Errorf("hub kubeconfig not set, %s is required", ranfuncparams.HubKubeEnvKey)
 	}
 
-	versionValid, versionDigits := ValidateInputString(version)
-	minimumValid, minimumDigits := ValidateInputString(minimum)
-	maximumValid, maximumDigits := ValidateInputString(maximum)
-
-	if !minimumValid {
-		// We don't want any bad minimums to be passed at all, so panic if minimum wasn't an empty string
-		if minimum != "" {
-			panic(fmt.Errorf("invalid minimum provided: '%s'", minimum))
-		}
-
-		// Assume the minimum digits are [0,0] for later comparison
-		minimumDigits = []int{0, 0}
+	HubName, err = cluster.GetClusterName(ranfuncparams.HubKubeEnvKey)
+	if err != nil {
+		return err
 	}
 
-	if !maximumValid {
-		// We don't want any bad maximums to be passed at all, so panic if maximum wasn't an empty string
-		if maximum != "" {
-			panic(fmt.Errorf("invalid maximum provided: '%s'", maximum))
-		}
-
-		// Assume the maximum digits are [math.MaxInt, math.MaxInt] for later comparison
-		maximumDigits = []int{math.MaxInt, math.MaxInt}
+	ocpVersion, err := cluster.GetClusterVersion(ranfuncinittools.HubAPIClient)
+	if err != nil {
+		return err
 	}
 
-	// If the version was not valid then we need to check the min and max
-	if !versionValid {
-		// If no min or max was defined then return true
-		if !minimumValid && !maximumValid {
-			return true
-		}
+	log.Printf("cluster '%s' has OCP version '%s'\n", HubName, ocpVersion)
+	version.Register(version.HubOCP, ocpVersion)
 
-		// Otherwise return whether the input maximum was an empty string or not
-		return maximum == ""
-	}
-
-	// Otherwise the versions were valid so compare the digits
-	for i := 0; i < 2; i++ {
-		// The version bit should be between the minimum and maximum
-		if versionDigits[i] < minimumDigits[i] || versionDigits[i] > maximumDigits[i] {
-			return false
-		}
+	AcmVersion, err = GetOperatorVersionFromCSV(
+		ranfuncinittools.HubAPIClient,
+		ranfuncparams.AcmOperatorName,
+		ranfuncparams.AcmOperatorNamespace,
+	)
+	if err != nil {
+		return err
 	}
 
-	// At the end if we never returned then all the digits were in valid range
-	return true
-}
-
-// InitializeClients initializes hub & spoke clients.
-func InitializeClients() error {
-	var err error
-
-	if os.Getenv(ranfuncparams.HubKubeEnvKey) != "" {
-		HubName, err = cluster.GetClusterName(ranfuncparams.HubKubeEnvKey)
-		if err != nil {
-			return err
-		}
-
-		ocpVersion, err := cluster.GetClusterVersion(ranfuncinittools.HubAPIClient)
-		if err != nil {
-			return err
-		}
-
-		log.Printf("cluster '%s' has OCP version '%s'\n", HubName, ocpVersion)
-
-		AcmVersion, err = GetOperatorVersionFromCSV(
-			ranfuncinittools.HubAPIClient,
-			ranfuncparams.AcmOperatorName,
-			ranfuncparams.AcmOperatorNamespace,
-		)
-		if err != nil {
-			return err
-		}
-
-		log.Printf("cluster '%s' has ACM version '%s'\n", HubName, AcmVersion)
+	log.Printf("cluster '%s' has ACM version '%s'\n", HubName, AcmVersion)
+	version.Register(version.ACM, AcmVersion)
 
-		ZtpVersion, err = GetZtpVersionFromArgocd(
-			ranfuncinittools.HubAPIClient,
-			ranfuncparams.OpenshiftGitopsRepoServer,
-			ranfuncparams.OpenshiftGitops,
-		)
-		if err != nil {
-			return err
-		}
-
-		log.Printf("cluster '%s' has ZTP version '%s'\n", HubName, ZtpVersion)
+	ZtpVersion, err = GetZtpVersionFromArgocd(
+		ranfuncinittools.HubAPIClient,
+		ranfuncparams.OpenshiftGitopsRepoServer,
+		ranfuncparams.OpenshiftGitops,
+	)
+	if err != nil {
+		return err
+	}
 
-		TalmVersion, err = GetOperatorVersionFromCSV(
-			ranfuncinittools.HubAPIClient,
-			ranfuncparams.OperatorHubTalmNamespace,
-			ranfuncparams.OpenshiftOperatorNamespace,
-		)
-		if err != nil {
-			return err
-		}
+	log.Printf("cluster '%s' has ZTP version '%s'\n", HubName, ZtpVersion)
+	version.Register(version.ZTP, ZtpVersion)
 
-		log.Printf("cluster '%s' has TALM version '%s'\n", HubName, TalmVersion)
+	TalmVersion, err = GetOperatorVersionFromCSV(
+		ranfuncinittools.HubAPIClient,
+		ranfuncparams.OperatorHubTalmNamespace,
+		ranfuncparams.OpenshiftOperatorNamespace,
+	)
+	if err != nil {
+		return err
 	}
 
+	log.Printf("cluster '%s' has TALM version '%s'\n", HubName, TalmVersion)
+	version.Register(version.TALM, TalmVersion)
+
 	// Spoke is the default kubeconfig
 	if os.Getenv(ranfuncparams.SpokeKubeEnvKey) != "" {
 		SpokeName, err = cluster.GetClusterName(ranfuncparams.SpokeKubeEnvKey)
@@ -177,6 +103,7 @@ func InitializeClients() error {
 		}
 
 		log.Printf("cluster '%s' has OCP version '%s'\n", SpokeName, ocpVersion)
+		version.Register(version.OCP, ocpVersion)
 	}
 
 	return nil
@@ -13,7 +13,6 @@ import (
 	"time"
 
 	"github.com/golang/glog"
-	"github.com/openshift-kni/eco-goinfra/pkg/clients"
 	"github.com/openshift-kni/eco-goinfra/pkg/daemonset"
 	"github.com/openshift-kni/eco-goinfra/pkg/mco"
 	"github.com/openshift-kni/eco-goinfra/pkg/namespace"
@@ -135,14 +134,6 @@ func GetPerformanceProfileWithCPUSet() (*nto.Builder, error) {
 	return nil, fmt.Errorf("performance profile with Reserved and Isolated CPU set is not found")
 }
 
-// IsSingleNodeCluster returns true if the cluster is single-node, otherwise false.
-func IsSingleNodeCluster(client *clients.Settings) bool {
-	nodeList, err := nodes.List(client)
-	Expect(err).ToNot(HaveOccurred())
-
-	return len(nodeList) == 1
-}
-
 // DefineQoSTestPod defines test pod with given cpu and memory resources.
 func DefineQoSTestPod(namespace namespace.Builder, nodeName, cpuReq, cpuLimit, memReq, memLimit string) *pod.Builder {
 	image := powermanagementparams.CnfTestImage
@@ -186,9 +177,19 @@ func RedefineContainerResources(
 }
 
 // SetPowerModeAndWaitForMcpUpdate updates the performance profile with the given workload hints,
-// and waits for the mcp update.
+// and waits for the mcp update. Nothing is done when the profile already has these workload hints since no mcp
+// update would start.
 func SetPowerModeAndWaitForMcpUpdate(perfProfile *nto.Builder, node nodes.Builder, perPodPowerManagement,
 	highPowerConsumption, realTime bool) error {
+	if hints := perfProfile.Definition.Spec.WorkloadHints; hints != nil &&
+		ptr.Deref(hints.PerPodPowerManagement, false) == perPodPowerManagement &&
+		ptr.Deref(hints.HighPowerConsumption, false) == highPowerConsumption &&
+		ptr.Deref(hints.RealTime, true) == realTime {
+		glog.V(100).Infof("Performance profile already has the requested workload hints")
+
+		return nil
+	}
+
 	glog.V(100).Infof("Set powersave mode on performance profile")
 
 	perfProfile.Definition.Spec.WorkloadHints = &performancev2.WorkloadHints{
@@ -13,7 +13,12 @@ import (
 	"github.com/openshift-kni/eco-goinfra/pkg/namespace"
 	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
 	"github.com/openshift-kni/eco-goinfra/pkg/nto" //nolint:misspell
+	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
 	"github.com/openshift-kni/eco-gosystem/tests/internal/cmd"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/inittools"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/latency"
+	systemtestsparams "github.com/openshift-kni/eco-gosystem/tests/internal/params"
+	systemtestsscc "github.com/openshift-kni/eco-gosystem/tests/internal/scc"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfuncinittools"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/powermanagement/internal/powermanagementhelper"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/powermanagement/internal/powermanagementparams"
@@ -30,20 +35,18 @@ var _ = Describe("Per-Core Runtime Tuning of power states - CRI-O", Ordered, fun
 		nodeList                     []*nodes.Builder
 		snoNode                      *corev1.Node
 		err                          error
-		isSNO                        bool
 		perfProfile                  *nto.Builder
 		workloadHints                *performancev2.WorkloadHints
 		originPerformanceProfileSpec performancev2.PerformanceProfileSpec
 	)
 
 	BeforeAll(func() {
+		cluster.RequireCapabilities(ranfuncinittools.HubAPIClient, nil, cluster.RequiresTopology(cluster.TopologySNO))
+
 		// Get nodes for connection host
 		nodeList, err = nodes.List(ranfuncinittools.HubAPIClient)
 		Expect(err).ToNot(HaveOccurred())
 
-		isSNO = powermanagementhelper.IsSingleNodeCluster(ranfuncinittools.HubAPIClient)
-		Expect(isSNO).Should(BeTrue(), "Currently only SNO nodes are supported by this test")
-
 		perfProfile, err = powermanagementhelper.GetPerformanceProfileWithCPUSet()
 		Expect(err).ToNot(HaveOccurred())
 		snoNode = nodeList[0].Object
@@ -53,6 +56,10 @@ var _ = Describe("Per-Core Runtime Tuning of power states - CRI-O", Ordered, fun
 	})
 
 	AfterAll(func() {
+		if perfProfile == nil {
+			return
+		}
+
 		// Restore performance profile to original spec after each test
 		glog.V(100).Infof("Restore performance profile to original specs")
 		perfProfile.Definition.Spec = originPerformanceProfileSpec
@@ -250,4 +257,104 @@ var _ = Describe("Per-Core Runtime Tuning of power states - CRI-O", Ordered, fun
 		})
 	})
 
+	Context("Collect latency metrics", func() {
+		var (
+			latencySettings *latency.Settings
+			baseline        map[latency.Tool]*latency.Result
+		)
+
+		It("Record the baseline latency in performance mode", func() {
+			latencySettings, err = latency.NewSettings(inittools.GeneralConfig)
+			Expect(err).ToNot(HaveOccurred(), "invalid latency test configuration")
+
+			By("Prepare privileged namespace")
+			ns := namespace.NewBuilder(ranfuncinittools.HubAPIClient, powermanagementparams.PrivPodNamespace)
+			if !ns.Exists() {
+				for key, value := range systemtestsparams.PrivilegedNSLabels {
+					ns.WithLabel(key, value)
+				}
+
+				_, err = ns.Create()
+				Expect(err).ToNot(HaveOccurred(), "error creating the privileged namespace")
+			}
+
+			err = systemtestsscc.AddPrivilegedSCCtoDefaultSA(powermanagementparams.PrivPodNamespace)
+			Expect(err).ToNot(HaveOccurred(), "error adding privileged SCC to the namespace")
+
+			// The earlier specs leave the node in any power state, the baseline is always measured in performance
+			// mode.
+			By(fmt.Sprintf("Switching to %s mode", powermanagementparams.PerformanceMode))
+			err = powermanagementhelper.SetPowerModeAndWaitForMcpUpdate(perfProfile, *nodeList[0], false, false, true)
+			Expect(err).ToNot(HaveOccurred(), "Unable to set power mode")
+
+			baseline = measureLatency(latencySettings, perfProfile, snoNode.Name)
+		})
+
+		// The baseline is measured in performance mode. Power saving trades wake-up latency for power, its latency
+		// is only checked against the max latency thresholds.
+		for _, powerMode := range []struct {
+			name                                        string
+			perPodPowerManagement, highPowerConsumption bool
+			checkRegression                             bool
+		}{
+			{name: powermanagementparams.HighPerformanceMode, highPowerConsumption: true, checkRegression: true},
+			{name: powermanagementparams.PowerSavingMode, perPodPowerManagement: true},
+		} {
+			powerMode := powerMode
+
+			It(fmt.Sprintf("Check latency after switching to %s mode", powerMode.name), func() {
+				if baseline == nil {
+					Skip("No baseline latency recorded")
+				}
+
+				By(fmt.Sprintf("Switching to %s mode", powerMode.name))
+				err := powermanagementhelper.SetPowerModeAndWaitForMcpUpdate(perfProfile, *nodeList[0],
+					powerMode.perPodPowerManagement, powerMode.highPowerConsumption, true)
+				Expect(err).ToNot(HaveOccurred(), "Unable to set power mode")
+
+				results := measureLatency(latencySettings, perfProfile, snoNode.Name)
+
+				if !powerMode.checkRegression {
+					return
+				}
+
+				for _, tool := range latency.Tools {
+					Expect(results[tool].CheckRegression(baseline[tool], latencySettings.Tolerance)).To(Succeed(),
+						"%s latency regressed in %s mode", tool, powerMode.name)
+				}
+			})
+		}
+	})
+
 })
+
+// measureLatency runs every latency tool on the node in the current power state, writes the metrics tagged with
+// the power state to the report and checks the max latency thresholds.
+func measureLatency(settings *latency.Settings, perfProfile *nto.Builder,
+	nodeName string) map[latency.Tool]*latency.Result {
+	// Determine power state to be used as a tag for the metric
+	powerState, err := powermanagementhelper.GetPowerState(perfProfile)
+	Expect(err).ToNot(HaveOccurred())
+
+	results := make(map[latency.Tool]*latency.Result)
+
+	for _, tool := range latency.Tools {
+		By(fmt.Sprintf("Run %s in %s mode", tool, powerState))
+		result, err := latency.Run(ranfuncinittools.HubAPIClient, perfProfile,
+			settings.Options(tool, powermanagementparams.PrivPodNamespace, nodeName))
+		Expect(err).ToNot(HaveOccurred(), "error measuring latency with %s", tool)
+
+		// Persist latency metrics to ginkgo report for further processing in pipeline.
+		for metricName, metricValue := range result.Metrics(powerState) {
+			_, err := fmt.Fprintf(GinkgoWriter, "%s: %s\n", metricName, metricValue)
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		Expect(result.CheckThreshold(settings.MaxLatency[tool])).To(Succeed(),
+			"%s latency above threshold in %s mode", tool, powerState)
+
+		results[tool] = result
+	}
+
+	return results
+}
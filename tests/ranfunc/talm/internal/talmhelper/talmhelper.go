@@ -17,6 +17,7 @@ import (
 	"github.com/openshift-kni/eco-goinfra/pkg/olm"
 	"github.com/openshift-kni/eco-goinfra/pkg/pod"
 
+	"github.com/openshift-kni/eco-gosystem/tests/internal/version"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfunchelper"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfuncinittools"
 	k8sErr "k8s.io/apimachinery/pkg/api/errors"
@@ -42,8 +43,6 @@ var (
 	Spoke2APIClient *clients.Settings
 	// Spoke2Name name of the second spoke.
 	Spoke2Name string
-	// TalmHubVersion talm hub version on hub.
-	TalmHubVersion string
 )
 
 // talm consts.
@@ -360,11 +359,7 @@ func WaitForCguToFinishSuccessfully(cguName string, namespace string, timeout ti
 	conditionType := SucceededType
 	conditionReason := ConditionReasonCompleted
 
-	if !ranfunchelper.IsVersionStringInRange(
-		TalmHubVersion,
-		talmparams.TalmUpdatedConditionsVersion,
-		"",
-	) {
+	if !version.Is(version.TALM, talmparams.TalmUpdatedConditionsVersion) {
 		conditionType = ReadyType
 		conditionReason = ConditionReasonUpgradeCompleted
 	}
@@ -1073,11 +1068,7 @@ func WaitForCguToTimeout(cguName string, namespace string, timeout time.Duration
 	conditionType := SucceededType
 	conditionReason := "TimedOut"
 
-	if !ranfunchelper.IsVersionStringInRange(
-		TalmHubVersion,
-		talmparams.TalmUpdatedConditionsVersion,
-		"",
-	) {
+	if !version.Is(version.TALM, talmparams.TalmUpdatedConditionsVersion) {
 		conditionType = ReadyType
 		conditionReason = "UpgradeTimedOut"
 	}
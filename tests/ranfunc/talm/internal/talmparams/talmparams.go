@@ -22,7 +22,7 @@ var (
 // talm related vars.
 const (
 	HubKubeEnvKey                = "KUBECONFIG"
-	TalmUpdatedConditionsVersion = "4.12"
+	TalmUpdatedConditionsVersion = ">=4.12"
 	OpenshiftOperatorNamespace   = "openshift-operators"
 	OperatorHubTalmNamespace     = "topology-aware-lifecycle-manager"
 	Spoke1KubeEnvKey             = "KUBECONFIG_SPOKE1"
@@ -14,7 +14,7 @@ import (
 	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
 	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
 	"github.com/openshift-kni/eco-gosystem/tests/internal/cmd"
-	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfunchelper"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/version"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfuncinittools"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/talm/internal/talmhelper"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/talm/internal/talmparams"
@@ -36,13 +36,7 @@ var (
 var _ = Describe("Talm Backup Tests with single spoke", func() {
 
 	BeforeEach(func() {
-		if !ranfunchelper.IsVersionStringInRange(
-			talmhelper.TalmHubVersion,
-			"4.11",
-			"",
-		) {
-			Skip("backup tests require talm 4.11 or higher")
-		}
+		version.RequireVersion(version.TALM, ">=4.11")
 	})
 
 	// ocp-50835
@@ -133,9 +127,7 @@ var _ = Describe("Talm Backup Tests with single spoke", func() {
 
 		// ocp-54294, ocp-54295
 		It("verifies backup begins and succeeds after CGU is enabled", func() {
-			if !ranfunchelper.IsVersionStringInRange(talmhelper.TalmHubVersion, "4.12", "") {
-				Skip("backup begins after CGU enable requires talm 4.12 or higher")
-			}
+			version.RequireVersion(version.TALM, ">=4.12")
 
 			By("creating a disabled cgu with backup enabled")
 			cgu := talmhelper.GetCguDefinition(
@@ -204,13 +196,7 @@ var _ = Describe("Talm Backup Tests with two spokes", Ordered, func() {
 	policyName := fmt.Sprintf("%s-%s", talmparams.PolicyNameCommonName, curName)
 
 	BeforeAll(func() {
-		if !ranfunchelper.IsVersionStringInRange(
-			talmhelper.TalmHubVersion,
-			"4.11",
-			"",
-		) {
-			Skip("backup tests require talm 4.11 or higher")
-		}
+		version.RequireVersion(version.TALM, ">=4.11")
 		// tests below requires all clusters to be present. hub + spoke1 + spoke2
 		clusterList := talmhelper.GetAllTestClients()
 		// Check that the required clusters are present
@@ -12,7 +12,7 @@ import (
 	"github.com/openshift-kni/eco-goinfra/pkg/namespace"
 	"github.com/openshift-kni/eco-goinfra/pkg/olm"
 	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
-	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfunchelper"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/version"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfuncinittools"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/talm/internal/talmhelper"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/talm/internal/talmparams"
@@ -85,13 +85,7 @@ var _ = Describe("Talm Batching Tests", Ordered, Label("talmbatching"), func() {
 			By("validating the talm version meets the test minimum", func() {
 				// TALM 4.11 does not set any conditions for a non managed cluster error
 				// We are unable to verify the state in 4.11 therefore we cannot run this test
-				if !ranfunchelper.IsVersionStringInRange(
-					talmhelper.TalmHubVersion,
-					talmparams.TalmUpdatedConditionsVersion,
-					"",
-				) {
-					Skip("test requires talm 4.12 or higher")
-				}
+				version.RequireVersion(version.TALM, talmparams.TalmUpdatedConditionsVersion)
 			})
 			By("creating the cgu", func() {
 				cgu := talmhelper.GetCguDefinition(
@@ -150,11 +144,7 @@ var _ = Describe("Talm Batching Tests", Ordered, Label("talmbatching"), func() {
 				conditionType := talmhelper.ValidatedType
 				conditionMessage := "Missing managed policies: [non-existent-policy]"
 
-				if !ranfunchelper.IsVersionStringInRange(
-					talmhelper.TalmHubVersion,
-					talmparams.TalmUpdatedConditionsVersion,
-					"",
-				) {
+				if !version.Is(version.TALM, talmparams.TalmUpdatedConditionsVersion) {
 					conditionType = talmhelper.ReadyType
 					conditionMessage = "The ClusterGroupUpgrade CR has: missing managed policies: [non-existent-policy]"
 				}
@@ -473,11 +463,7 @@ var _ = Describe("Talm Batching Tests", Ordered, Label("talmbatching"), func() {
 				})
 
 				// Deletion of TALM-generated policy requires 4.12 or higher
-				if ranfunchelper.IsVersionStringInRange(
-					talmhelper.TalmHubVersion,
-					talmparams.TalmUpdatedConditionsVersion,
-					"",
-				) {
+				if version.Is(version.TALM, talmparams.TalmUpdatedConditionsVersion) {
 
 					By("verifying the test policy was deleted upon CGU expiration", func() {
 						TalmPolicyPrefix := talmhelper.CguName + "-" + talmhelper.PolicyName
@@ -518,7 +504,7 @@ var _ = Describe("Talm Batching Tests", Ordered, Label("talmbatching"), func() {
 
 					policyLabelSelector := metav1.LabelSelector{}
 
-					if ranfunchelper.IsVersionStringInRange(talmhelper.TalmHubVersion, talmparams.TalmUpdatedConditionsVersion, "") {
+					if version.Is(version.TALM, talmparams.TalmUpdatedConditionsVersion) {
 						glog.V(100).Infof("Test using MatchLabels with name %s and MatchExpressions with name %s...",
 							talmhelper.Spoke1Name, talmhelper.Spoke2Name)
 						policyLabelSelector = metav1.LabelSelector{
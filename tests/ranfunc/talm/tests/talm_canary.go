@@ -12,7 +12,7 @@ import (
 	"github.com/openshift-kni/eco-goinfra/pkg/namespace"
 	"github.com/openshift-kni/eco-goinfra/pkg/polarion"
 	"github.com/openshift-kni/eco-gosystem/tests/internal/cluster"
-	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfunchelper"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/version"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/internal/ranfuncinittools"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/talm/internal/talmhelper"
 	"github.com/openshift-kni/eco-gosystem/tests/ranfunc/talm/internal/talmparams"
@@ -179,11 +179,7 @@ var _ = Describe("Talm Canary Tests", Ordered, Label("talmcanary"), func() {
 					conditionType := talmhelper.SucceededType
 					conditionMessage := "Policy remediation took too long on canary clusters"
 
-					if !ranfunchelper.IsVersionStringInRange(
-						talmhelper.TalmHubVersion,
-						talmparams.TalmUpdatedConditionsVersion,
-						"",
-					) {
+					if !version.Is(version.TALM, talmparams.TalmUpdatedConditionsVersion) {
 						conditionType = talmhelper.ReadyType
 						conditionMessage = talmhelper.Talm411TimeoutMessage
 					}
@@ -0,0 +1,85 @@
+package storageconfig
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/config"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// PathToDefaultStorageParamsFile path to config file with default storage parameters.
+	PathToDefaultStorageParamsFile = "./default.yaml"
+)
+
+// StorageConfig type keeps storage configuration.
+type StorageConfig struct {
+	*config.GeneralConfig
+	StorageClass string `yaml:"storage_class" envconfig:"ECO_STORAGE_CLASS"`
+	VolumeCount  string `yaml:"storage_volume_count" envconfig:"ECO_STORAGE_VOLUME_COUNT"`
+	VolumeSize   string `yaml:"storage_volume_size" envconfig:"ECO_STORAGE_VOLUME_SIZE"`
+	DataSizeMB   string `yaml:"storage_data_size_mb" envconfig:"ECO_STORAGE_DATA_SIZE_MB"`
+	TestImage    string `yaml:"storage_test_image" envconfig:"ECO_STORAGE_TEST_IMAGE"`
+}
+
+// NewStorageConfig returns instance of StorageConfig config type.
+func NewStorageConfig() *StorageConfig {
+	log.Print("Creating new StorageConfig struct")
+
+	var storageConf StorageConfig
+	storageConf.GeneralConfig = config.NewConfig()
+
+	_, filename, _, _ := runtime.Caller(0)
+	baseDir := filepath.Dir(filename)
+	confFile := filepath.Join(baseDir, PathToDefaultStorageParamsFile)
+	err := readFile(&storageConf, confFile)
+
+	if err != nil {
+		log.Printf("Error to read config file %s", confFile)
+
+		return nil
+	}
+
+	err = readEnv(&storageConf)
+
+	if err != nil {
+		log.Print("Error to read environment variables")
+
+		return nil
+	}
+
+	return &storageConf
+}
+
+func readFile(storageConfig *StorageConfig, cfgFile string) error {
+	openedCfgFile, err := os.Open(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = openedCfgFile.Close()
+	}()
+
+	decoder := yaml.NewDecoder(openedCfgFile)
+	err = decoder.Decode(&storageConfig)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func readEnv(storageConfig *StorageConfig) error {
+	err := envconfig.Process("", storageConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
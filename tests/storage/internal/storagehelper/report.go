@@ -0,0 +1,137 @@
+package storagehelper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const metricCorruptedVolumes = "storagemetrics_corrupted_volumes"
+
+// VolumeReport is the outcome of the checks of a volume after a disruption.
+type VolumeReport struct {
+	Volume
+	Bound            bool     `json:"bound"`
+	SamePV           bool     `json:"samePV"`
+	Mounted          bool     `json:"mounted"`
+	ObservedChecksum string   `json:"observedChecksum"`
+	Failures         []string `json:"failures,omitempty"`
+}
+
+// Corrupted returns true if the volume failed any of the checks.
+func (report *VolumeReport) Corrupted() bool {
+	return len(report.Failures) > 0
+}
+
+// String returns a one line description of the volume report.
+func (report *VolumeReport) String() string {
+	if !report.Corrupted() {
+		return fmt.Sprintf("PVC %s (PV %s): ok", report.PVC, report.PV)
+	}
+
+	return fmt.Sprintf("PVC %s (PV %s): %s", report.PVC, report.PV, strings.Join(report.Failures, ", "))
+}
+
+// CheckVolumes verifies that every volume is still bound to the same PV, is mounted in the writer pod and holds
+// the data written before, and returns one report per volume.
+func CheckVolumes(apiClient *clients.Settings, writer *pod.Builder, volumes []Volume) []VolumeReport {
+	reports := make([]VolumeReport, 0, len(volumes))
+
+	mounts, err := writer.ExecCommand([]string{"cat", "/proc/mounts"})
+	if err != nil {
+		glog.V(100).Infof("Failed to read writer pod mounts: %s", err)
+	}
+
+	for _, volume := range volumes {
+		report := VolumeReport{Volume: volume}
+
+		pvc, err := apiClient.PersistentVolumeClaims(writer.Definition.Namespace).Get(
+			context.TODO(), volume.PVC, metav1.GetOptions{})
+		if err != nil {
+			report.Failures = append(report.Failures, fmt.Sprintf("failed to get PVC: %s", err))
+		} else {
+			report.Bound = pvc.Status.Phase == v1.ClaimBound
+			report.SamePV = pvc.Spec.VolumeName == volume.PV
+
+			if !report.Bound {
+				report.Failures = append(report.Failures, fmt.Sprintf("PVC is %s", pvc.Status.Phase))
+			}
+
+			if !report.SamePV {
+				report.Failures = append(report.Failures, fmt.Sprintf("PVC bound to PV %s", pvc.Spec.VolumeName))
+			}
+		}
+
+		report.Mounted = isMounted(mounts.String(), mountPath(volume.PVC))
+		if !report.Mounted {
+			report.Failures = append(report.Failures, "volume not mounted")
+		}
+
+		report.ObservedChecksum, err = getChecksum(writer, filepath.Join(mountPath(volume.PVC), dataFileName))
+
+		switch {
+		case err != nil:
+			report.Failures = append(report.Failures, fmt.Sprintf("failed to read data: %s", err))
+		case report.ObservedChecksum != volume.Checksum:
+			report.Failures = append(report.Failures, fmt.Sprintf("checksum mismatch, expected %s, got %s",
+				volume.Checksum, report.ObservedChecksum))
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports
+}
+
+// Metrics returns the number of corrupted volumes metric named after the given tag.
+func Metrics(reports []VolumeReport, tag string) map[string]string {
+	var corrupted int
+
+	for index := range reports {
+		if reports[index].Corrupted() {
+			corrupted++
+		}
+	}
+
+	return map[string]string{
+		fmt.Sprintf("%s_%s", metricCorruptedVolumes, tag): strconv.Itoa(corrupted),
+	}
+}
+
+// WriteReport writes the volume reports as <name>_volumes.json to dir.
+func WriteReport(dir, name string, reports []VolumeReport) error {
+	glog.V(90).Infof("Writing storage report %s to %s", name, dir)
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, name+"_volumes.json"), content, 0644)
+}
+
+func isMounted(mounts, path string) bool {
+	for _, line := range strings.Split(mounts, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 1 && fields[1] == path {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,200 @@
+package storagehelper
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	"github.com/openshift-kni/eco-gosystem/tests/storage/internal/storageparams"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	dataMountRoot = "/data"
+	dataFileName  = "data.bin"
+	// defaultClassAnnotation marks the cluster default StorageClass.
+	defaultClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+)
+
+// Volume is a test volume and the checksum of the data written to it.
+type Volume struct {
+	PVC      string `json:"pvc"`
+	PV       string `json:"pv"`
+	Checksum string `json:"checksum"`
+}
+
+// GetStorageClass returns the given StorageClass name after checking it exists, or the cluster default StorageClass
+// name when empty.
+func GetStorageClass(apiClient *clients.Settings, name string) (string, error) {
+	if name != "" {
+		_, err := apiClient.StorageClasses().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+
+		return name, nil
+	}
+
+	storageClassList, err := apiClient.StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	for _, storageClass := range storageClassList.Items {
+		if storageClass.Annotations[defaultClassAnnotation] == "true" {
+			return storageClass.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("no default StorageClass found")
+}
+
+// CreatePVCs creates count PersistentVolumeClaims of the given size from the StorageClass and returns their names.
+func CreatePVCs(apiClient *clients.Settings, nsname, storageClass, size string, count int) ([]string, error) {
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid volume size %s: %w", size, err)
+	}
+
+	var pvcNames []string
+
+	for index := 0; index < count; index++ {
+		pvc := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%d", storageparams.PVCNamePrefix, index),
+				Namespace: nsname,
+			},
+			Spec: v1.PersistentVolumeClaimSpec{
+				AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+				StorageClassName: &storageClass,
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceStorage: quantity},
+				},
+			},
+		}
+
+		glog.V(90).Infof("Creating PVC %s in namespace %s", pvc.Name, nsname)
+
+		_, err = apiClient.PersistentVolumeClaims(nsname).Create(context.TODO(), pvc, metav1.CreateOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		pvcNames = append(pvcNames, pvc.Name)
+	}
+
+	return pvcNames, nil
+}
+
+// DeployWriter creates the privileged pod mounting every PVC under /data/<pvc name>. Local volumes bind on first
+// consumer, so the pod is scheduled on the node owning them.
+func DeployWriter(apiClient *clients.Settings, nsname, image string, pvcNames []string) (*pod.Builder, error) {
+	writer := pod.NewBuilder(apiClient, storageparams.WriterPodName, nsname, image).
+		RedefineDefaultCMD([]string{"/bin/sh", "-c", "sleep infinity"}).
+		WithPrivilegedFlag()
+
+	for _, pvcName := range pvcNames {
+		writer.WithVolume(v1.Volume{
+			Name: pvcName,
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName},
+			},
+		})
+
+		writer.Definition.Spec.Containers[0].VolumeMounts = append(writer.Definition.Spec.Containers[0].VolumeMounts,
+			v1.VolumeMount{Name: pvcName, MountPath: mountPath(pvcName)})
+	}
+
+	return writer.CreateAndWaitUntilRunning(storageparams.DefaultTimeout)
+}
+
+// WriteData writes sizeMB of random data to every volume, flushes it to disk and returns the volumes with their
+// bound PV and data checksum.
+func WriteData(apiClient *clients.Settings, writer *pod.Builder, pvcNames []string, sizeMB int) ([]Volume, error) {
+	var volumes []Volume
+
+	for _, pvcName := range pvcNames {
+		dataFile := filepath.Join(mountPath(pvcName), dataFileName)
+
+		glog.V(90).Infof("Writing %dMB to %s", sizeMB, dataFile)
+
+		_, err := writer.ExecCommand([]string{"/bin/sh", "-c", fmt.Sprintf(
+			"dd if=/dev/urandom of=%s bs=1M count=%d conv=fsync status=none && sync", dataFile, sizeMB)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to write data to PVC %s: %w", pvcName, err)
+		}
+
+		checksum, err := getChecksum(writer, dataFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute checksum on PVC %s: %w", pvcName, err)
+		}
+
+		pvc, err := apiClient.PersistentVolumeClaims(writer.Definition.Namespace).Get(
+			context.TODO(), pvcName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		volumes = append(volumes, Volume{PVC: pvcName, PV: pvc.Spec.VolumeName, Checksum: checksum})
+	}
+
+	return volumes, nil
+}
+
+// WaitForWriterRestart waits until the writer pod containers were restarted after the given time and are ready.
+// API errors are tolerated while waiting since the writer node may be recovering from a disruption.
+func WaitForWriterRestart(apiClient *clients.Settings, nsname string, since time.Time,
+	timeout time.Duration) (*pod.Builder, error) {
+	var writer *pod.Builder
+
+	err := wait.PollUntilContextTimeout(
+		context.TODO(), storageparams.PollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+			var err error
+
+			writer, err = pod.Pull(apiClient, storageparams.WriterPodName, nsname)
+			if err != nil {
+				glog.V(100).Infof("Failed to pull writer pod: %s", err)
+
+				return false, nil
+			}
+
+			for _, status := range writer.Object.Status.ContainerStatuses {
+				if !status.Ready || status.State.Running == nil || status.State.Running.StartedAt.Time.Before(since) {
+					return false, nil
+				}
+			}
+
+			return len(writer.Object.Status.ContainerStatuses) > 0, nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("writer pod was not restarted within %s: %w", timeout, err)
+	}
+
+	return writer, nil
+}
+
+func mountPath(pvcName string) string {
+	return filepath.Join(dataMountRoot, pvcName)
+}
+
+func getChecksum(writer *pod.Builder, dataFile string) (string, error) {
+	output, err := writer.ExecCommand([]string{"sha256sum", dataFile})
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(output.String())
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty sha256sum output for %s", dataFile)
+	}
+
+	return fields[0], nil
+}
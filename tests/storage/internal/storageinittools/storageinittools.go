@@ -0,0 +1,21 @@
+package storageinittools
+
+import (
+	"github.com/openshift-kni/eco-goinfra/pkg/clients"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/inittools"
+	"github.com/openshift-kni/eco-gosystem/tests/storage/internal/storageconfig"
+)
+
+var (
+	// APIClient provides API access to cluster.
+	APIClient *clients.Settings
+	// StorageTestConfig provides access to storage system tests configuration parameters.
+	StorageTestConfig *storageconfig.StorageConfig
+)
+
+// init loads all variables automatically when this package is imported. Once package is imported a user has full
+// access to all vars within init function. It is recommended to import this package using dot import.
+func init() {
+	StorageTestConfig = storageconfig.NewStorageConfig()
+	APIClient = inittools.APIClient
+}
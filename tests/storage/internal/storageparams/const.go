@@ -0,0 +1,20 @@
+package storageparams
+
+import "time"
+
+const (
+	// Label represents storage system tests label that can be used for test cases selection.
+	Label = "storage"
+	// LabelStoragePersistence represents tests labels related to the data persistence across reboots.
+	LabelStoragePersistence = "storage-persistence"
+	// WriterPodName is the name of the pod mounting the test volumes.
+	WriterPodName = "eco-storage-writer"
+	// PVCNamePrefix is the prefix of the test PersistentVolumeClaims names.
+	PVCNamePrefix = "eco-storage-pvc"
+	// DefaultTimeout is the timeout used for test resources creation.
+	DefaultTimeout = 5 * time.Minute
+	// RebootTimeout is the time given to a node to reboot and become ready again.
+	RebootTimeout = 30 * time.Minute
+	// PollInterval is the interval between two checks of the writer pod status.
+	PollInterval = 10 * time.Second
+)
@@ -0,0 +1,151 @@
+package storage_system_test
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/eco-goinfra/pkg/nodes"
+	"github.com/openshift-kni/eco-goinfra/pkg/pod"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/await"
+	"github.com/openshift-kni/eco-gosystem/tests/internal/reboot"
+	systemtestsscc "github.com/openshift-kni/eco-gosystem/tests/internal/scc"
+	"github.com/openshift-kni/eco-gosystem/tests/storage/internal/storagehelper"
+	. "github.com/openshift-kni/eco-gosystem/tests/storage/internal/storageinittools"
+	"github.com/openshift-kni/eco-gosystem/tests/storage/internal/storageparams"
+)
+
+var _ = Describe(
+	"StoragePersistence",
+	Ordered,
+	ContinueOnFailure,
+	Label(storageparams.LabelStoragePersistence), func() {
+		var (
+			writer  *pod.Builder
+			volumes []storagehelper.Volume
+		)
+
+		BeforeAll(func() {
+			volumeCount, err := strconv.Atoi(StorageTestConfig.VolumeCount)
+			Expect(err).ToNot(HaveOccurred(), "invalid volume count")
+
+			dataSizeMB, err := strconv.Atoi(StorageTestConfig.DataSizeMB)
+			Expect(err).ToNot(HaveOccurred(), "invalid data size")
+
+			By("Get the StorageClass")
+			storageClass, err := storagehelper.GetStorageClass(APIClient, StorageTestConfig.StorageClass)
+			if StorageTestConfig.StorageClass == "" && err != nil {
+				Skip(fmt.Sprintf("No StorageClass configured and no default StorageClass: %s", err))
+			}
+
+			Expect(err).ToNot(HaveOccurred(), "error getting StorageClass %s", StorageTestConfig.StorageClass)
+
+			By(fmt.Sprintf("Create %d PVCs from StorageClass %s", volumeCount, storageClass))
+			pvcNames, err := storagehelper.CreatePVCs(APIClient, storageparams.TestNamespaceName, storageClass,
+				StorageTestConfig.VolumeSize, volumeCount)
+			Expect(err).ToNot(HaveOccurred(), "error creating PVCs")
+
+			By("Deploy the pod mounting the volumes")
+			err = systemtestsscc.AddPrivilegedSCCtoDefaultSA(storageparams.TestNamespaceName)
+			Expect(err).ToNot(HaveOccurred(), "error adding privileged SCC to the default service account")
+
+			writer, err = storagehelper.DeployWriter(APIClient, storageparams.TestNamespaceName,
+				StorageTestConfig.TestImage, pvcNames)
+			Expect(err).ToNot(HaveOccurred(), "error deploying the writer pod")
+
+			By("Write data with known checksums")
+			volumes, err = storagehelper.WriteData(APIClient, writer, pvcNames, dataSizeMB)
+			Expect(err).ToNot(HaveOccurred(), "error writing data to the volumes")
+
+			for _, volume := range volumes {
+				fmt.Fprintf(GinkgoWriter, "PVC %s bound to PV %s, checksum %s\n", volume.PVC, volume.PV, volume.Checksum)
+			}
+		})
+
+		It("Verify volumes survive a soft reboot", Label(storageparams.LabelStoragePersistence), func() {
+			nodeName := writer.Object.Spec.NodeName
+			bootID := getBootID(nodeName)
+
+			By(fmt.Sprintf("Soft reboot node %s", nodeName))
+			rebootTime := time.Now()
+			err := reboot.SoftRebootNode(nodeName)
+			Expect(err).ToNot(HaveOccurred(), "Error rebooting the nodes.")
+
+			By("Wait for node to become unreachable")
+			err = await.WaitUntilNodeIsUnreachable(nodeName, 3*time.Minute)
+			Expect(err).ToNot(HaveOccurred(), "Node is still reachable: %s", err)
+
+			verifyVolumes(nodeName, bootID, rebootTime, volumes, "softreboot")
+		})
+
+		It("Verify volumes survive a hard reboot", Label(storageparams.LabelStoragePersistence), func() {
+			nodeName := writer.Object.Spec.NodeName
+			bootID := getBootID(nodeName)
+
+			By(fmt.Sprintf("Hard reboot node %s", nodeName))
+			rebootTime := time.Now()
+			err := reboot.HardRebootNode(nodeName, storageparams.TestNamespaceName)
+			Expect(err).ToNot(HaveOccurred(), "Error rebooting the nodes.")
+
+			verifyVolumes(nodeName, bootID, rebootTime, volumes, "hardreboot")
+		})
+
+		It("Verify volumes survive a kernel crash", Label(storageparams.LabelStoragePersistence), func() {
+			nodeName := writer.Object.Spec.NodeName
+			bootID := getBootID(nodeName)
+
+			By(fmt.Sprintf("Trigger kernel crash on node %s", nodeName))
+			crashTime := time.Now()
+			err := reboot.KernelCrashKdump(nodeName)
+			Expect(err).ToNot(HaveOccurred(), "Error triggering a kernel crash on the node.")
+
+			verifyVolumes(nodeName, bootID, crashTime, volumes, "kdump")
+		})
+	})
+
+func getBootID(nodeName string) string {
+	node, err := nodes.Pull(APIClient, nodeName)
+	Expect(err).ToNot(HaveOccurred(), "error pulling node %s", nodeName)
+
+	return node.Object.Status.NodeInfo.BootID
+}
+
+// verifyVolumes waits for the node to reboot and the writer pod to restart, checks the PV binding, mount and data
+// checksum of every volume, persists the per volume report to the reports directory and the corrupted volumes
+// metric to the ginkgo report, and asserts no volume is corrupted.
+func verifyVolumes(nodeName, bootID string, since time.Time, volumes []storagehelper.Volume, tag string) {
+	By("Wait for node to reboot and become ready")
+	err := reboot.WaitUntilNodeRebooted(nodeName, bootID, storageparams.RebootTimeout)
+	Expect(err).ToNot(HaveOccurred(), "node %s did not become ready after reboot", nodeName)
+
+	By("Wait for the writer pod to restart")
+	writer, err := storagehelper.WaitForWriterRestart(APIClient, storageparams.TestNamespaceName, since,
+		storageparams.DefaultTimeout)
+	Expect(err).ToNot(HaveOccurred(), "writer pod did not restart")
+
+	By("Check the volumes binding, mount and data")
+	reports := storagehelper.CheckVolumes(APIClient, writer, volumes)
+
+	err = storagehelper.WriteReport(StorageTestConfig.ReportsDirAbsPath, "storage_"+tag, reports)
+	Expect(err).ToNot(HaveOccurred(), "error writing the storage report")
+
+	var corrupted []string
+
+	for index := range reports {
+		fmt.Fprintf(GinkgoWriter, "%s\n", reports[index].String())
+
+		if reports[index].Corrupted() {
+			corrupted = append(corrupted, reports[index].PVC)
+		}
+	}
+
+	// Persist storage metrics to ginkgo report for further processing in pipeline.
+	for metricName, metricValue := range storagehelper.Metrics(reports, tag) {
+		_, err := fmt.Fprintf(GinkgoWriter, "%s: %s\n", metricName, metricValue)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	Expect(corrupted).To(BeEmpty(), "volumes corrupted after %s", tag)
+}